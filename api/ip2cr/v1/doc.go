@@ -0,0 +1,4 @@
+// Package ip2crv1 contains the v1 gRPC API schema for IP2CR, along with the generated Go client and server stubs.
+package ip2crv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative ip2cr.proto
@@ -0,0 +1,246 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: ip2cr.proto
+
+// v1 of the IP2CR API; breaking changes must go in a new package (e.g. ip2cr.v2) so that existing clients keep working
+
+package ip2crv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	LookupService_Lookup_FullMethodName          = "/ip2cr.v1.LookupService/Lookup"
+	LookupService_BatchLookup_FullMethodName     = "/ip2cr.v1.LookupService/BatchLookup"
+	LookupService_StreamInventory_FullMethodName = "/ip2cr.v1.LookupService/StreamInventory"
+)
+
+// LookupServiceClient is the client API for LookupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LookupServiceClient interface {
+	// searches for the resource that owns a single IP
+	Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error)
+	// searches for each IP in the request, streaming back each result as soon as it's searched
+	BatchLookup(ctx context.Context, in *BatchLookupRequest, opts ...grpc.CallOption) (LookupService_BatchLookupClient, error)
+	// streams every resource with a public IP
+	StreamInventory(ctx context.Context, in *StreamInventoryRequest, opts ...grpc.CallOption) (LookupService_StreamInventoryClient, error)
+}
+
+type lookupServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLookupServiceClient(cc grpc.ClientConnInterface) LookupServiceClient {
+	return &lookupServiceClient{cc}
+}
+
+func (c *lookupServiceClient) Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error) {
+	out := new(LookupResponse)
+	err := c.cc.Invoke(ctx, LookupService_Lookup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lookupServiceClient) BatchLookup(ctx context.Context, in *BatchLookupRequest, opts ...grpc.CallOption) (LookupService_BatchLookupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LookupService_ServiceDesc.Streams[0], LookupService_BatchLookup_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lookupServiceBatchLookupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LookupService_BatchLookupClient interface {
+	Recv() (*LookupResponse, error)
+	grpc.ClientStream
+}
+
+type lookupServiceBatchLookupClient struct {
+	grpc.ClientStream
+}
+
+func (x *lookupServiceBatchLookupClient) Recv() (*LookupResponse, error) {
+	m := new(LookupResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lookupServiceClient) StreamInventory(ctx context.Context, in *StreamInventoryRequest, opts ...grpc.CallOption) (LookupService_StreamInventoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LookupService_ServiceDesc.Streams[1], LookupService_StreamInventory_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lookupServiceStreamInventoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LookupService_StreamInventoryClient interface {
+	Recv() (*Resource, error)
+	grpc.ClientStream
+}
+
+type lookupServiceStreamInventoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *lookupServiceStreamInventoryClient) Recv() (*Resource, error) {
+	m := new(Resource)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LookupServiceServer is the server API for LookupService service.
+// All implementations must embed UnimplementedLookupServiceServer
+// for forward compatibility
+type LookupServiceServer interface {
+	// searches for the resource that owns a single IP
+	Lookup(context.Context, *LookupRequest) (*LookupResponse, error)
+	// searches for each IP in the request, streaming back each result as soon as it's searched
+	BatchLookup(*BatchLookupRequest, LookupService_BatchLookupServer) error
+	// streams every resource with a public IP
+	StreamInventory(*StreamInventoryRequest, LookupService_StreamInventoryServer) error
+	mustEmbedUnimplementedLookupServiceServer()
+}
+
+// UnimplementedLookupServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedLookupServiceServer struct {
+}
+
+func (UnimplementedLookupServiceServer) Lookup(context.Context, *LookupRequest) (*LookupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lookup not implemented")
+}
+func (UnimplementedLookupServiceServer) BatchLookup(*BatchLookupRequest, LookupService_BatchLookupServer) error {
+	return status.Errorf(codes.Unimplemented, "method BatchLookup not implemented")
+}
+func (UnimplementedLookupServiceServer) StreamInventory(*StreamInventoryRequest, LookupService_StreamInventoryServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamInventory not implemented")
+}
+func (UnimplementedLookupServiceServer) mustEmbedUnimplementedLookupServiceServer() {}
+
+// UnsafeLookupServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LookupServiceServer will
+// result in compilation errors.
+type UnsafeLookupServiceServer interface {
+	mustEmbedUnimplementedLookupServiceServer()
+}
+
+func RegisterLookupServiceServer(s grpc.ServiceRegistrar, srv LookupServiceServer) {
+	s.RegisterService(&LookupService_ServiceDesc, srv)
+}
+
+func _LookupService_Lookup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LookupServiceServer).Lookup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LookupService_Lookup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LookupServiceServer).Lookup(ctx, req.(*LookupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LookupService_BatchLookup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchLookupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LookupServiceServer).BatchLookup(m, &lookupServiceBatchLookupServer{stream})
+}
+
+type LookupService_BatchLookupServer interface {
+	Send(*LookupResponse) error
+	grpc.ServerStream
+}
+
+type lookupServiceBatchLookupServer struct {
+	grpc.ServerStream
+}
+
+func (x *lookupServiceBatchLookupServer) Send(m *LookupResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LookupService_StreamInventory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamInventoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LookupServiceServer).StreamInventory(m, &lookupServiceStreamInventoryServer{stream})
+}
+
+type LookupService_StreamInventoryServer interface {
+	Send(*Resource) error
+	grpc.ServerStream
+}
+
+type lookupServiceStreamInventoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *lookupServiceStreamInventoryServer) Send(m *Resource) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// LookupService_ServiceDesc is the grpc.ServiceDesc for LookupService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LookupService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ip2cr.v1.LookupService",
+	HandlerType: (*LookupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Lookup",
+			Handler:    _LookupService_Lookup_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchLookup",
+			Handler:       _LookupService_BatchLookup_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamInventory",
+			Handler:       _LookupService_StreamInventory_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ip2cr.proto",
+}
@@ -2,20 +2,30 @@ package aws
 
 import (
 	"errors"
+	"slices"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	configtypes "github.com/aws/aws-sdk-go-v2/service/configservice/types"
 	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
 	log "github.com/sirupsen/logrus"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
 	cfp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/cloudfront"
+	ctp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/cloudtrail"
+	configp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/config"
 	ec2p "github.com/magneticstain/ip-2-cloudresource/aws/plugin/ec2"
 	elbp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/elb"
 	orgp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/organizations"
+	iphistory "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_history"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 type AWSController struct {
 	PrincipalAWSConn awsconnector.AWSConnector
+	// shared with plugins so resource inventories are only fetched once per account when searching for multiple IPs
+	InvCache *inventorycache.InventoryCache
 }
 
 func New() (AWSController, error) {
@@ -77,25 +87,25 @@ func (awsCtrlr *AWSController) SearchAWSSvc(ipAddr, cloudSvc string, doNetMappin
 
 	switch cloudSvc {
 	case "cloudfront":
-		pluginConn := cfp.CloudfrontPlugin{AwsConn: awsCtrlr.PrincipalAWSConn, NetworkMapping: doNetMapping}
+		pluginConn := cfp.CloudfrontPlugin{AwsConn: awsCtrlr.PrincipalAWSConn, InvCache: awsCtrlr.InvCache, NetworkMapping: doNetMapping}
 		matchingResource, err = pluginConn.SearchResources(ipAddr)
 		if err != nil {
 			return matchingResource, err
 		}
 	case "ec2":
-		pluginConn := ec2p.EC2Plugin{AwsConn: awsCtrlr.PrincipalAWSConn, NetworkMapping: doNetMapping}
+		pluginConn := ec2p.EC2Plugin{AwsConn: awsCtrlr.PrincipalAWSConn, InvCache: awsCtrlr.InvCache, NetworkMapping: doNetMapping}
 		matchingResource, err = pluginConn.SearchResources(ipAddr)
 		if err != nil {
 			return matchingResource, err
 		}
 	case "elbv1": // classic ELBs
-		pluginConn := elbp.ELBv1Plugin{AwsConn: awsCtrlr.PrincipalAWSConn, NetworkMapping: doNetMapping}
+		pluginConn := elbp.ELBv1Plugin{AwsConn: awsCtrlr.PrincipalAWSConn, InvCache: awsCtrlr.InvCache, NetworkMapping: doNetMapping}
 		matchingResource, err = pluginConn.SearchResources(ipAddr)
 		if err != nil {
 			return matchingResource, err
 		}
 	case "elbv2":
-		pluginConn := elbp.ELBPlugin{AwsConn: awsCtrlr.PrincipalAWSConn, NetworkMapping: doNetMapping}
+		pluginConn := elbp.ELBPlugin{AwsConn: awsCtrlr.PrincipalAWSConn, InvCache: awsCtrlr.InvCache, NetworkMapping: doNetMapping}
 		matchingResource, err = pluginConn.SearchResources(ipAddr)
 		if err != nil {
 			return matchingResource, err
@@ -106,3 +116,81 @@ func (awsCtrlr *AWSController) SearchAWSSvc(ipAddr, cloudSvc string, doNetMappin
 
 	return matchingResource, nil
 }
+
+func (awsCtrlr *AWSController) GetAWSSvcInventory(cloudSvc string) ([]generalResource.Resource, error) {
+	log.Debug("fetching ", cloudSvc, " inventory in AWS controller")
+
+	switch cloudSvc {
+	case "cloudfront":
+		pluginConn := cfp.CloudfrontPlugin{AwsConn: awsCtrlr.PrincipalAWSConn, InvCache: awsCtrlr.InvCache}
+		return pluginConn.GetResourceInventory()
+	case "ec2":
+		pluginConn := ec2p.EC2Plugin{AwsConn: awsCtrlr.PrincipalAWSConn, InvCache: awsCtrlr.InvCache}
+		return pluginConn.GetResourceInventory()
+	case "elbv1": // classic ELBs
+		pluginConn := elbp.ELBv1Plugin{AwsConn: awsCtrlr.PrincipalAWSConn, InvCache: awsCtrlr.InvCache}
+		return pluginConn.GetResourceInventory()
+	case "elbv2":
+		pluginConn := elbp.ELBPlugin{AwsConn: awsCtrlr.PrincipalAWSConn, InvCache: awsCtrlr.InvCache}
+		return pluginConn.GetResourceInventory()
+	default:
+		return nil, errors.New("invalid cloud service provided for AWS inventory")
+	}
+}
+
+func (awsCtrlr AWSController) GetIPAttribution(ipAddr string, at time.Time, lookback time.Duration) (iphistory.Attribution, error) {
+	// determines which resource owned the IP at the given time using CloudTrail events and, if it's enabled, AWS Config history
+	log.Debug("fetching ownership history of ", ipAddr, " at ", at, " in AWS controller")
+
+	var cloudTrailRecords []iphistory.CloudTrailRecord
+
+	ctPluginConn := ctp.CloudTrailPlugin{AwsConn: awsCtrlr.PrincipalAWSConn}
+	cloudTrailEvents, err := ctPluginConn.GetResources(at.Add(-lookback), at)
+	if err != nil {
+		return iphistory.Attribution{IPAddr: ipAddr, At: at, Confidence: iphistory.ConfidenceNone}, err
+	}
+
+	for _, cloudTrailEvent := range cloudTrailEvents {
+		cloudTrailRecord, err := iphistory.ParseCloudTrailEvent(aws.ToString(cloudTrailEvent.CloudTrailEvent))
+		if err != nil {
+			log.Debug("skipping unparseable CloudTrail event ", aws.ToString(cloudTrailEvent.EventId), ": ", err)
+			continue
+		}
+
+		cloudTrailRecords = append(cloudTrailRecords, cloudTrailRecord)
+	}
+
+	// the resources CloudTrail points to are checked against AWS Config too, since Config only finds resources by their current IPs
+	var configItems []configtypes.ConfigurationItem
+
+	configPluginConn := configp.ConfigPlugin{AwsConn: awsCtrlr.PrincipalAWSConn}
+	recordedResources, err := configPluginConn.FindResourcesByIP(ipAddr)
+	if err != nil {
+		log.Warn("unable to query AWS Config, so only CloudTrail events will be used as evidence: ", err)
+
+		return iphistory.Attribute(ipAddr, at, configItems, cloudTrailRecords), nil
+	}
+
+	for _, resourceID := range iphistory.GetCandidateResourceIDs(iphistory.AttributeFromCloudTrail(ipAddr, at, cloudTrailRecords)) {
+		resourceType, _ := iphistory.GetConfigResourceType(resourceID)
+		recordedResource := configp.RecordedResource{ResourceType: resourceType, ResourceID: resourceID}
+
+		if !slices.Contains(recordedResources, recordedResource) {
+			recordedResources = append(recordedResources, recordedResource)
+		}
+	}
+
+	for _, recordedResource := range recordedResources {
+		configItem, err := configPluginConn.GetConfigItemAt(recordedResource, at)
+		if err != nil {
+			log.Warn("unable to fetch AWS Config history for ", recordedResource.ResourceID, ": ", err)
+			continue
+		}
+
+		if configItem != nil {
+			configItems = append(configItems, *configItem)
+		}
+	}
+
+	return iphistory.Attribute(ipAddr, at, configItems, cloudTrailRecords), nil
+}
@@ -7,16 +7,19 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type CloudfrontPlugin struct {
 	AwsConn        awsconnector.AWSConnector
+	InvCache       *inventorycache.InventoryCache
 	NetworkMapping bool
 }
 
@@ -65,7 +68,7 @@ func (cfp CloudfrontPlugin) SearchResources(tgtIP string) (generalResource.Resou
 	var matchingResource generalResource.Resource
 	var originIdSet, originDomainNameSet []string
 
-	cfResources, err := cfp.GetResources()
+	cfResources, err := inventorycache.Fetch(cfp.InvCache, "cloudfront", cfp.GetResources)
 	if err != nil {
 		return matchingResource, err
 	}
@@ -104,3 +107,36 @@ func (cfp CloudfrontPlugin) SearchResources(tgtIP string) (generalResource.Resou
 
 	return matchingResource, nil
 }
+
+func (cfp CloudfrontPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	// distributions are served from shared edge IPs, so the IPs listed are only the ones the domain name currently resolves to from here
+	var inventory []generalResource.Resource
+
+	cfResources, err := inventorycache.Fetch(cfp.InvCache, "cloudfront", cfp.GetResources)
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, cfDistro := range cfResources {
+		cfIPAddrs, err := utils.LookupFQDN(NormalizeCFDistroFQDN(aws.ToString(cfDistro.DomainName)))
+		if err != nil {
+			// e.g. a disabled distribution; it shouldn't keep the rest of the account out of the inventory
+			log.Warn("skipping CloudFront distribution [ ", aws.ToString(cfDistro.Id), " ] in inventory; unable to resolve its domain name: ", err)
+			continue
+		}
+		publicIPv4Addrs, publicIPv6Addrs := utils.SplitIPAddrsByVersion(cfIPAddrs)
+
+		inventory = append(inventory, generalResource.Resource{
+			Id:              aws.ToString(cfDistro.Id),
+			RID:             aws.ToString(cfDistro.ARN),
+			Name:            aws.ToString(cfDistro.DomainName),
+			Status:          aws.ToString(cfDistro.Status),
+			CloudSvc:        "cloudfront",
+			PublicIPv4Addrs: publicIPv4Addrs,
+			PublicIPv6Addrs: publicIPv6Addrs,
+			FQDNs:           []string{utils.NormalizeFQDN(aws.ToString(cfDistro.DomainName))},
+		})
+	}
+
+	return inventory, nil
+}
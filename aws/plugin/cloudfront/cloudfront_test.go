@@ -78,3 +78,17 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceInventory(t *testing.T) {
+	cfp := cfpFactory()
+
+	inventory, _ := cfp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via CloudFront Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
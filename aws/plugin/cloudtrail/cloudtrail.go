@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	log "github.com/sirupsen/logrus"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+)
+
+type CloudTrailPlugin struct {
+	AwsConn awsconnector.AWSConnector
+}
+
+func GetIPEventNames() []string {
+	// management events that can allocate, move, or release a public IP
+	return []string{
+		"AllocateAddress",
+		"AssociateAddress",
+		"DisassociateAddress",
+		"ReleaseAddress",
+		"RunInstances",
+		"CreateNetworkInterface",
+		"AttachNetworkInterface",
+		"DeleteNetworkInterface",
+	}
+}
+
+func (ctp CloudTrailPlugin) GetResources(startTime time.Time, endTime time.Time) ([]types.Event, error) {
+	var events []types.Event
+
+	cloudTrailClient := cloudtrail.NewFromConfig(ctp.AwsConn.AwsConfig)
+
+	// lookups only support a single attribute at a time, so we need to look up each event name separately
+	for _, eventName := range GetIPEventNames() {
+		paginator := cloudtrail.NewLookupEventsPaginator(cloudTrailClient, &cloudtrail.LookupEventsInput{
+			StartTime: aws.Time(startTime),
+			EndTime:   aws.Time(endTime),
+			LookupAttributes: []types.LookupAttribute{
+				{AttributeKey: types.LookupAttributeKeyEventName, AttributeValue: aws.String(eventName)},
+			},
+		})
+
+		for paginator.HasMorePages() {
+			output, err := paginator.NextPage(context.TODO())
+			if err != nil {
+				return events, err
+			}
+
+			events = append(events, output.Events...)
+		}
+	}
+
+	log.Debug("found [ ", len(events), " ] CloudTrail events that may involve public IPs")
+
+	return events, nil
+}
@@ -0,0 +1,39 @@
+package plugin_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/cloudtrail"
+)
+
+func ctpFactory() plugin.CloudTrailPlugin {
+	ac, _ := awsconnector.New()
+
+	ctp := plugin.CloudTrailPlugin{AwsConn: ac}
+
+	return ctp
+}
+
+func TestGetResources(t *testing.T) {
+	ctp := ctpFactory()
+
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Hour)
+
+	cloudTrailEvents, _ := ctp.GetResources(startTime, endTime)
+
+	for _, cloudTrailEvent := range cloudTrailEvents {
+		if !slices.Contains(plugin.GetIPEventNames(), aws.ToString(cloudTrailEvent.EventName)) {
+			t.Errorf("Fetching events via CloudTrail plugin failed; received unexpected event %s", aws.ToString(cloudTrailEvent.EventName))
+		}
+
+		if cloudTrailEvent.EventTime.Before(startTime) || cloudTrailEvent.EventTime.After(endTime) {
+			t.Errorf("Fetching events via CloudTrail plugin failed; received event from %s, outside of %s - %s", cloudTrailEvent.EventTime, startTime, endTime)
+		}
+	}
+}
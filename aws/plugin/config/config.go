@@ -0,0 +1,106 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/aws/aws-sdk-go-v2/service/configservice/types"
+	log "github.com/sirupsen/logrus"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+)
+
+type ConfigPlugin struct {
+	AwsConn awsconnector.AWSConnector
+}
+
+// a resource recorded by AWS Config
+type RecordedResource struct {
+	ResourceType types.ResourceType
+	ResourceID   string
+}
+
+func GetIPResourceTypes() []types.ResourceType {
+	// resource types whose configuration includes the public IPs associated with them
+	return []types.ResourceType{
+		types.ResourceTypeEip,
+		types.ResourceTypeNetworkInterface,
+		types.ResourceTypeInstance,
+	}
+}
+
+func GenerateIPQuery(ipAddr string) string {
+	return fmt.Sprintf(
+		"SELECT resourceId, resourceType WHERE resourceType IN ('%s', '%s', '%s') AND (configuration.publicIp = '%s' OR configuration.association.publicIp = '%s' OR configuration.publicIpAddress = '%s')",
+		types.ResourceTypeEip,
+		types.ResourceTypeNetworkInterface,
+		types.ResourceTypeInstance,
+		ipAddr,
+		ipAddr,
+		ipAddr,
+	)
+}
+
+func (configp ConfigPlugin) FindResourcesByIP(ipAddr string) ([]RecordedResource, error) {
+	// advanced queries only cover each resource's current configuration, so this won't find resources that have since released the IP
+	var recordedResources []RecordedResource
+
+	configClient := configservice.NewFromConfig(configp.AwsConn.AwsConfig)
+	paginator := configservice.NewSelectResourceConfigPaginator(configClient, &configservice.SelectResourceConfigInput{
+		Expression: aws.String(GenerateIPQuery(ipAddr)),
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return recordedResources, err
+		}
+
+		for _, result := range output.Results {
+			var recordedResource struct {
+				ResourceID   string `json:"resourceId"`
+				ResourceType string `json:"resourceType"`
+			}
+
+			err = json.Unmarshal([]byte(result), &recordedResource)
+			if err != nil {
+				return recordedResources, err
+			}
+
+			recordedResources = append(recordedResources, RecordedResource{
+				ResourceType: types.ResourceType(recordedResource.ResourceType),
+				ResourceID:   recordedResource.ResourceID,
+			})
+		}
+	}
+
+	log.Debug("found [ ", len(recordedResources), " ] resources in AWS Config currently associated with ", ipAddr)
+
+	return recordedResources, nil
+}
+
+func (configp ConfigPlugin) GetConfigItemAt(recordedResource RecordedResource, at time.Time) (*types.ConfigurationItem, error) {
+	// returns the configuration of the resource that was in effect at the given time, or nil if it wasn't recorded by then
+	configClient := configservice.NewFromConfig(configp.AwsConn.AwsConfig)
+
+	output, err := configClient.GetResourceConfigHistory(context.TODO(), &configservice.GetResourceConfigHistoryInput{
+		ResourceType:       recordedResource.ResourceType,
+		ResourceId:         aws.String(recordedResource.ResourceID),
+		LaterTime:          aws.Time(at),
+		ChronologicalOrder: types.ChronologicalOrderReverse,
+		Limit:              1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(output.ConfigurationItems) == 0 {
+		return nil, nil
+	}
+
+	return &output.ConfigurationItems[0], nil
+}
@@ -0,0 +1,54 @@
+package plugin_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/config"
+)
+
+func configpFactory() plugin.ConfigPlugin {
+	ac, _ := awsconnector.New()
+
+	configp := plugin.ConfigPlugin{AwsConn: ac}
+
+	return configp
+}
+
+func TestGenerateIPQuery(t *testing.T) {
+	query := plugin.GenerateIPQuery("18.161.22.61")
+
+	for _, resourceType := range plugin.GetIPResourceTypes() {
+		if !strings.Contains(query, string(resourceType)) {
+			t.Errorf("Generating AWS Config IP query failed; expected query to include %s, received %s", resourceType, query)
+		}
+	}
+
+	if strings.Count(query, "'18.161.22.61'") != 3 {
+		t.Errorf("Generating AWS Config IP query failed; expected query to match the IP on each IP field, received %s", query)
+	}
+}
+
+func TestFindResourcesByIP(t *testing.T) {
+	configp := configpFactory()
+
+	recordedResources, _ := configp.FindResourcesByIP("18.161.22.61")
+
+	for _, recordedResource := range recordedResources {
+		if recordedResource.ResourceID == "" {
+			t.Errorf("Finding resources by IP via AWS Config plugin failed; received resource with no ID: %+v", recordedResource)
+		}
+	}
+}
+
+func TestGetConfigItemAt(t *testing.T) {
+	configp := configpFactory()
+	at := time.Now().Add(-24 * time.Hour)
+
+	configItem, err := configp.GetConfigItemAt(plugin.RecordedResource{ResourceType: plugin.GetIPResourceTypes()[0], ResourceID: "eipalloc-00000000000000000"}, at)
+	if err == nil && configItem != nil && configItem.ConfigurationItemCaptureTime.After(at) {
+		t.Errorf("Fetching AWS Config history failed; expected configuration item from before %s, received one from %s", at, configItem.ConfigurationItemCaptureTime)
+	}
+}
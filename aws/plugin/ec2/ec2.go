@@ -5,15 +5,18 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 type EC2Plugin struct {
 	AwsConn        awsconnector.AWSConnector
+	InvCache       *inventorycache.InventoryCache
 	NetworkMapping bool
 }
 
@@ -38,7 +41,7 @@ func (ec2p EC2Plugin) GetResources() ([]types.Reservation, error) {
 func (ec2p EC2Plugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
 	var matchingResource generalResource.Resource
 
-	ec2Resources, err := ec2p.GetResources()
+	ec2Resources, err := inventorycache.Fetch(ec2p.InvCache, "ec2", ec2p.GetResources)
 	if err != nil {
 		return matchingResource, err
 	}
@@ -75,3 +78,37 @@ func (ec2p EC2Plugin) SearchResources(tgtIP string) (generalResource.Resource, e
 
 	return matchingResource, nil
 }
+
+func (ec2p EC2Plugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	var inventory []generalResource.Resource
+
+	ec2Resources, err := inventorycache.Fetch(ec2p.InvCache, "ec2", ec2p.GetResources)
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, ec2Reservation := range ec2Resources {
+		for _, instance := range ec2Reservation.Instances {
+			currentResource := generalResource.Resource{
+				Id:       aws.ToString(instance.InstanceId),
+				RID:      aws.ToString(instance.InstanceId),
+				CloudSvc: "ec2",
+			}
+
+			if instance.State != nil {
+				currentResource.Status = string(instance.State.Name)
+			}
+
+			if instance.PublicIpAddress != nil {
+				currentResource.PublicIPv4Addrs = append(currentResource.PublicIPv4Addrs, *instance.PublicIpAddress)
+			}
+			if instance.Ipv6Address != nil {
+				currentResource.PublicIPv6Addrs = append(currentResource.PublicIPv6Addrs, *instance.Ipv6Address)
+			}
+
+			inventory = append(inventory, currentResource)
+		}
+	}
+
+	return inventory, nil
+}
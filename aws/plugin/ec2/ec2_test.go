@@ -56,3 +56,17 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceInventory(t *testing.T) {
+	ec2p := ec2pFactory()
+
+	inventory, _ := ec2p.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via EC2 Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
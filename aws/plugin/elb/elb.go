@@ -7,16 +7,19 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type ELBPlugin struct {
 	AwsConn        awsconnector.AWSConnector
+	InvCache       *inventorycache.InventoryCache
 	NetworkMapping bool
 }
 
@@ -110,7 +113,7 @@ func (elbp ELBPlugin) SearchResources(tgtIP string) (generalResource.Resource, e
 	var elbListners []types.Listener
 	var elbTgts []ELBTarget
 
-	elbResources, err := elbp.GetResources()
+	elbResources, err := inventorycache.Fetch(elbp.InvCache, "elbv2", elbp.GetResources)
 	if err != nil {
 		return matchingResource, err
 	}
@@ -157,3 +160,45 @@ func (elbp ELBPlugin) SearchResources(tgtIP string) (generalResource.Resource, e
 
 	return matchingResource, nil
 }
+
+func (elbp ELBPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	// ALB/NLB IPs aren't returned by the API, so each internet-facing LB's DNS name is resolved to get them
+	var inventory []generalResource.Resource
+
+	elbResources, err := inventorycache.Fetch(elbp.InvCache, "elbv2", elbp.GetResources)
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, elb := range elbResources {
+		if elb.Scheme == types.LoadBalancerSchemeEnumInternal {
+			continue
+		}
+
+		elbIPAddrs, err := utils.LookupFQDN(aws.ToString(elb.DNSName))
+		if err != nil {
+			// e.g. an LB that's still provisioning; it shouldn't keep the rest of the account out of the inventory
+			log.Warn("skipping ELB [ ", aws.ToString(elb.LoadBalancerName), " ] in inventory; unable to resolve its DNS name: ", err)
+			continue
+		}
+		publicIPv4Addrs, publicIPv6Addrs := utils.SplitIPAddrsByVersion(elbIPAddrs)
+
+		currentResource := generalResource.Resource{
+			Id:              aws.ToString(elb.LoadBalancerName),
+			RID:             aws.ToString(elb.LoadBalancerArn),
+			Name:            aws.ToString(elb.LoadBalancerName),
+			CloudSvc:        "elbv2",
+			PublicIPv4Addrs: publicIPv4Addrs,
+			PublicIPv6Addrs: publicIPv6Addrs,
+			FQDNs:           []string{utils.NormalizeFQDN(aws.ToString(elb.DNSName))},
+		}
+
+		if elb.State != nil {
+			currentResource.Status = string(elb.State.Code)
+		}
+
+		inventory = append(inventory, currentResource)
+	}
+
+	return inventory, nil
+}
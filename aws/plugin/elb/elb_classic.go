@@ -6,16 +6,19 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing/types"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type ELBv1Plugin struct {
 	AwsConn        awsconnector.AWSConnector
+	InvCache       *inventorycache.InventoryCache
 	NetworkMapping bool
 }
 
@@ -41,7 +44,7 @@ func (elbv1p ELBv1Plugin) SearchResources(tgtIP string) (generalResource.Resourc
 	var elbIPAddrs []net.IP
 	var matchingResource generalResource.Resource
 
-	elbResources, err := elbv1p.GetResources()
+	elbResources, err := inventorycache.Fetch(elbv1p.InvCache, "elbv1", elbv1p.GetResources)
 	if err != nil {
 		return matchingResource, err
 	}
@@ -70,3 +73,38 @@ func (elbv1p ELBv1Plugin) SearchResources(tgtIP string) (generalResource.Resourc
 
 	return matchingResource, nil
 }
+
+func (elbv1p ELBv1Plugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	// same as ALBs/NLBs, classic LB IPs are only available by resolving their DNS names
+	var inventory []generalResource.Resource
+
+	elbResources, err := inventorycache.Fetch(elbv1p.InvCache, "elbv1", elbv1p.GetResources)
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, elb := range elbResources {
+		if aws.ToString(elb.Scheme) == "internal" {
+			continue
+		}
+
+		elbIPAddrs, err := utils.LookupFQDN(aws.ToString(elb.DNSName))
+		if err != nil {
+			log.Warn("skipping classic ELB [ ", aws.ToString(elb.LoadBalancerName), " ] in inventory; unable to resolve its DNS name: ", err)
+			continue
+		}
+		publicIPv4Addrs, publicIPv6Addrs := utils.SplitIPAddrsByVersion(elbIPAddrs)
+
+		inventory = append(inventory, generalResource.Resource{
+			Id:              aws.ToString(elb.LoadBalancerName),
+			RID:             aws.ToString(elb.LoadBalancerName),
+			Name:            aws.ToString(elb.LoadBalancerName),
+			CloudSvc:        "elbv1",
+			PublicIPv4Addrs: publicIPv4Addrs,
+			PublicIPv6Addrs: publicIPv6Addrs,
+			FQDNs:           []string{utils.NormalizeFQDN(aws.ToString(elb.DNSName))},
+		})
+	}
+
+	return inventory, nil
+}
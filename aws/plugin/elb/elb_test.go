@@ -161,3 +161,17 @@ func TestSearchResources_Elbv1(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceInventory(t *testing.T) {
+	elbp := elbpFactory()
+
+	inventory, _ := elbp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via ELB Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
@@ -6,15 +6,29 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"sync"
 
 	awsipprefix "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing/models/aws_ip_prefix"
 )
 
 const awsIPRangeURL string = "https://ip-ranges.amazonaws.com/ip-ranges.json"
 
+// the IP ranges are only fetched once per run so that searching for multiple IPs doesn't refetch them for each one
+var ipRangeCache struct {
+	sync.Mutex
+	data *awsipprefix.RawAwsIPRangeJSON
+}
+
 func FetchIPRanges() (awsipprefix.RawAwsIPRangeJSON, error) {
 	var ipRangeData awsipprefix.RawAwsIPRangeJSON
 
+	ipRangeCache.Lock()
+	defer ipRangeCache.Unlock()
+
+	if ipRangeCache.data != nil {
+		return *ipRangeCache.data, nil
+	}
+
 	// fetch IP prefixes from AWS's Public IP Range API
 	resp, err := http.Get(awsIPRangeURL)
 	if err != nil {
@@ -35,6 +49,8 @@ func FetchIPRanges() (awsipprefix.RawAwsIPRangeJSON, error) {
 		return ipRangeData, jsonErr
 	}
 
+	ipRangeCache.data = &ipRangeData
+
 	return ipRangeData, nil
 }
 
@@ -0,0 +1,331 @@
+package iphistory
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	configtypes "github.com/aws/aws-sdk-go-v2/service/configservice/types"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+const (
+	// AWS Config recorded the IP on the resource at the given time
+	ConfidenceHigh = "high"
+	// CloudTrail shows the IP being associated with the resource, and it wasn't disassociated before the given time
+	ConfidenceMedium = "medium"
+	// CloudTrail shows activity involving the IP, but not a direct association with the resource
+	ConfidenceLow = "low"
+	// no evidence of the IP being owned at the given time
+	ConfidenceNone = "none"
+)
+
+type Evidence struct {
+	// "config" or "cloudtrail"
+	Source string
+	Time   time.Time
+	// the CloudTrail event name, or the status of the AWS Config configuration item
+	Name string
+	// the CloudTrail event ID, or the AWS Config configuration state ID
+	ID         string
+	ResourceID string
+	Summary    string
+}
+
+type Attribution struct {
+	IPAddr     string
+	At         time.Time
+	Confidence string
+	Resource   generalResource.Resource
+	Evidence   []Evidence
+}
+
+// the fields we need from the raw CloudTrail event JSON
+type CloudTrailRecord struct {
+	EventID            string         `json:"eventID"`
+	EventName          string         `json:"eventName"`
+	EventTime          time.Time      `json:"eventTime"`
+	AWSRegion          string         `json:"awsRegion"`
+	RecipientAccountID string         `json:"recipientAccountId"`
+	ErrorCode          string         `json:"errorCode"`
+	RequestParameters  map[string]any `json:"requestParameters"`
+	ResponseElements   map[string]any `json:"responseElements"`
+}
+
+func ParseCloudTrailEvent(rawEvent string) (CloudTrailRecord, error) {
+	var cloudTrailRecord CloudTrailRecord
+
+	err := json.Unmarshal([]byte(rawEvent), &cloudTrailRecord)
+
+	return cloudTrailRecord, err
+}
+
+func ContainsValue(node any, value string) bool {
+	// recursively checks every value within the decoded JSON for the given string
+	switch nodeVal := node.(type) {
+	case string:
+		return nodeVal == value
+	case map[string]any:
+		for _, childNode := range nodeVal {
+			if ContainsValue(childNode, value) {
+				return true
+			}
+		}
+	case []any:
+		for _, childNode := range nodeVal {
+			if ContainsValue(childNode, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func FindStringValue(node any, key string) string {
+	// recursively searches the decoded JSON for the first string value with the given key
+	switch nodeVal := node.(type) {
+	case map[string]any:
+		if keyVal, isStr := nodeVal[key].(string); isStr && keyVal != "" {
+			return keyVal
+		}
+
+		for _, childNode := range nodeVal {
+			if keyVal := FindStringValue(childNode, key); keyVal != "" {
+				return keyVal
+			}
+		}
+	case []any:
+		for _, childNode := range nodeVal {
+			if keyVal := FindStringValue(childNode, key); keyVal != "" {
+				return keyVal
+			}
+		}
+	}
+
+	return ""
+}
+
+func GetConfigResourceType(resourceID string) (configtypes.ResourceType, bool) {
+	// maps the ID of a resource that can own a public IP to its AWS Config resource type
+	switch {
+	case strings.HasPrefix(resourceID, "i-"):
+		return configtypes.ResourceTypeInstance, true
+	case strings.HasPrefix(resourceID, "eni-"):
+		return configtypes.ResourceTypeNetworkInterface, true
+	case strings.HasPrefix(resourceID, "eipalloc-"):
+		return configtypes.ResourceTypeEip, true
+	}
+
+	return "", false
+}
+
+func GenerateResource(resourceID string, region string, acctID string) generalResource.Resource {
+	resourcePaths := map[configtypes.ResourceType]string{
+		configtypes.ResourceTypeInstance:         "instance",
+		configtypes.ResourceTypeNetworkInterface: "network-interface",
+		configtypes.ResourceTypeEip:              "elastic-ip",
+	}
+
+	resource := generalResource.Resource{
+		Id:        resourceID,
+		RID:       resourceID,
+		AccountID: acctID,
+		CloudSvc:  "ec2",
+		Location:  region,
+	}
+
+	resourceType, found := GetConfigResourceType(resourceID)
+	if found {
+		resource.RID = fmt.Sprintf("arn:aws:ec2:%s:%s:%s/%s", region, acctID, resourcePaths[resourceType], resourceID)
+	}
+
+	return resource
+}
+
+func GetOwnerFromConfigItem(configItem configtypes.ConfigurationItem) string {
+	// IPs are recorded on ENIs and EIPs, but the instance they're attached to is usually what we're after
+	var configuration map[string]any
+
+	resourceID := aws.ToString(configItem.ResourceId)
+
+	err := json.Unmarshal([]byte(aws.ToString(configItem.Configuration)), &configuration)
+	if err != nil {
+		return resourceID
+	}
+
+	switch configItem.ResourceType {
+	case configtypes.ResourceTypeNetworkInterface:
+		if instanceID := FindStringValue(configuration["attachment"], "instanceId"); instanceID != "" {
+			return instanceID
+		}
+	case configtypes.ResourceTypeEip:
+		for _, ownerKey := range []string{"instanceId", "networkInterfaceId"} {
+			if ownerID, isStr := configuration[ownerKey].(string); isStr && ownerID != "" {
+				return ownerID
+			}
+		}
+	}
+
+	return resourceID
+}
+
+func AttributeFromConfig(ipAddr string, at time.Time, configItems []configtypes.ConfigurationItem) (Attribution, bool) {
+	attribution := Attribution{IPAddr: ipAddr, At: at, Confidence: ConfidenceNone}
+
+	for _, configItem := range configItems {
+		var configuration any
+
+		if configItem.ConfigurationItemStatus == configtypes.ConfigurationItemStatusResourceDeleted || configItem.ConfigurationItemStatus == configtypes.ConfigurationItemStatusResourceDeletedNotRecorded {
+			continue
+		}
+
+		err := json.Unmarshal([]byte(aws.ToString(configItem.Configuration)), &configuration)
+		if err != nil || !ContainsValue(configuration, ipAddr) {
+			continue
+		}
+
+		ownerID := GetOwnerFromConfigItem(configItem)
+
+		attribution.Confidence = ConfidenceHigh
+		attribution.Resource = GenerateResource(ownerID, aws.ToString(configItem.AwsRegion), aws.ToString(configItem.AccountId))
+		attribution.Evidence = append(attribution.Evidence, Evidence{
+			Source:     "config",
+			Time:       aws.ToTime(configItem.ConfigurationItemCaptureTime),
+			Name:       string(configItem.ConfigurationItemStatus),
+			ID:         aws.ToString(configItem.ConfigurationStateId),
+			ResourceID: aws.ToString(configItem.ResourceId),
+			Summary:    fmt.Sprintf("AWS Config recorded %s on %s %s", ipAddr, configItem.ResourceType, aws.ToString(configItem.ResourceId)),
+		})
+
+		return attribution, true
+	}
+
+	return attribution, false
+}
+
+func AttributeFromCloudTrail(ipAddr string, at time.Time, cloudTrailRecords []CloudTrailRecord) Attribution {
+	// replays the events leading up to the given time to work out who held the IP at that point
+	attribution := Attribution{IPAddr: ipAddr, At: at, Confidence: ConfidenceNone}
+
+	sortedRecords := slices.Clone(cloudTrailRecords)
+	sort.SliceStable(sortedRecords, func(i, j int) bool {
+		return sortedRecords[i].EventTime.Before(sortedRecords[j].EventTime)
+	})
+
+	// EIPs are usually referenced by allocation or association ID rather than the IP itself, so we track those as we go
+	var trackedIDs []string
+	var allocationID, ownerID, ownerRegion, ownerAcctID string
+
+	for _, record := range sortedRecords {
+		if record.ErrorCode != "" || record.EventTime.After(at) {
+			continue
+		}
+
+		isRelevant := ContainsValue(record.RequestParameters, ipAddr) || ContainsValue(record.ResponseElements, ipAddr)
+		for _, trackedID := range trackedIDs {
+			isRelevant = isRelevant || ContainsValue(record.RequestParameters, trackedID)
+		}
+		if !isRelevant {
+			continue
+		}
+
+		var summary string
+		resourceID := ""
+
+		switch record.EventName {
+		case "AllocateAddress":
+			allocationID = FindStringValue(record.ResponseElements, "allocationId")
+			trackedIDs = append(trackedIDs, allocationID)
+			resourceID = allocationID
+			ownerID, attribution.Confidence = resourceID, ConfidenceLow
+			summary = fmt.Sprintf("%s allocated as EIP %s", ipAddr, resourceID)
+		case "AssociateAddress":
+			resourceID = FindStringValue(record.RequestParameters, "instanceId")
+			if resourceID == "" {
+				resourceID = FindStringValue(record.RequestParameters, "networkInterfaceId")
+			}
+			if associationID := FindStringValue(record.ResponseElements, "associationId"); associationID != "" {
+				trackedIDs = append(trackedIDs, associationID)
+			}
+			if requestAllocationID := FindStringValue(record.RequestParameters, "allocationId"); requestAllocationID != "" {
+				allocationID = requestAllocationID
+			}
+			ownerID, attribution.Confidence = resourceID, ConfidenceMedium
+			summary = fmt.Sprintf("%s associated with %s", ipAddr, resourceID)
+		case "DisassociateAddress":
+			// the IP stays allocated to the account, so the allocation is all that's left to attribute it to
+			ownerID, attribution.Confidence = allocationID, ConfidenceLow
+			if ownerID == "" {
+				attribution.Confidence = ConfidenceNone
+			}
+			summary = fmt.Sprintf("%s disassociated", ipAddr)
+		case "ReleaseAddress", "DeleteNetworkInterface":
+			resourceID = FindStringValue(record.RequestParameters, "allocationId")
+			if resourceID == "" {
+				resourceID = FindStringValue(record.RequestParameters, "networkInterfaceId")
+			}
+			ownerID, attribution.Confidence = "", ConfidenceNone
+			summary = fmt.Sprintf("%s released by %s", ipAddr, record.EventName)
+		default:
+			// RunInstances and ENI events only include the IP if it was known when the event was recorded
+			resourceID = FindStringValue(record.ResponseElements, "instanceId")
+			if resourceID == "" {
+				resourceID = FindStringValue(record.ResponseElements, "networkInterfaceId")
+			}
+			ownerID, attribution.Confidence = resourceID, ConfidenceLow
+			summary = fmt.Sprintf("%s referenced by %s for %s", ipAddr, record.EventName, resourceID)
+		}
+
+		if ownerID != "" {
+			ownerRegion, ownerAcctID = record.AWSRegion, record.RecipientAccountID
+		}
+
+		attribution.Evidence = append(attribution.Evidence, Evidence{
+			Source:     "cloudtrail",
+			Time:       record.EventTime,
+			Name:       record.EventName,
+			ID:         record.EventID,
+			ResourceID: resourceID,
+			Summary:    summary,
+		})
+	}
+
+	if ownerID != "" {
+		attribution.Resource = GenerateResource(ownerID, ownerRegion, ownerAcctID)
+	}
+
+	return attribution
+}
+
+func Attribute(ipAddr string, at time.Time, configItems []configtypes.ConfigurationItem, cloudTrailRecords []CloudTrailRecord) Attribution {
+	// AWS Config records the actual state of the resource, so it's preferred over piecing together CloudTrail events
+	cloudTrailAttribution := AttributeFromCloudTrail(ipAddr, at, cloudTrailRecords)
+
+	configAttribution, found := AttributeFromConfig(ipAddr, at, configItems)
+	if !found {
+		return cloudTrailAttribution
+	}
+
+	configAttribution.Evidence = append(configAttribution.Evidence, cloudTrailAttribution.Evidence...)
+
+	return configAttribution
+}
+
+func GetCandidateResourceIDs(attribution Attribution) []string {
+	// returns every resource referenced by the evidence, so each can be checked against its AWS Config history
+	var resourceIDs []string
+
+	for _, evidence := range attribution.Evidence {
+		if _, found := GetConfigResourceType(evidence.ResourceID); found && !slices.Contains(resourceIDs, evidence.ResourceID) {
+			resourceIDs = append(resourceIDs, evidence.ResourceID)
+		}
+	}
+
+	return resourceIDs
+}
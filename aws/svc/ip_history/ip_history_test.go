@@ -0,0 +1,192 @@
+package iphistory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	configtypes "github.com/aws/aws-sdk-go-v2/service/configservice/types"
+
+	iphistory "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_history"
+)
+
+const testIPAddr = "18.161.22.61"
+
+func cloudTrailRecordFactory(eventName string, eventTime time.Time, requestParameters string, responseElements string) iphistory.CloudTrailRecord {
+	rawEvent := `{"eventID": "` + eventName + `-evt", "eventName": "` + eventName + `", "eventTime": "` + eventTime.Format(time.RFC3339) + `", "awsRegion": "us-east-1", "recipientAccountId": "123456789012", "requestParameters": ` + requestParameters + `, "responseElements": ` + responseElements + `}`
+
+	cloudTrailRecord, _ := iphistory.ParseCloudTrailEvent(rawEvent)
+
+	return cloudTrailRecord
+}
+
+func eipLifecycleFactory(baseTime time.Time) []iphistory.CloudTrailRecord {
+	return []iphistory.CloudTrailRecord{
+		cloudTrailRecordFactory("AllocateAddress", baseTime, `{"domain": "vpc"}`, `{"publicIp": "`+testIPAddr+`", "allocationId": "eipalloc-0123456789abcdef0"}`),
+		cloudTrailRecordFactory("AssociateAddress", baseTime.Add(time.Hour), `{"allocationId": "eipalloc-0123456789abcdef0", "instanceId": "i-0123456789abcdef0"}`, `{"associationId": "eipassoc-0123456789abcdef0"}`),
+		cloudTrailRecordFactory("DisassociateAddress", baseTime.Add(3*time.Hour), `{"associationId": "eipassoc-0123456789abcdef0"}`, `{"_return": true}`),
+		cloudTrailRecordFactory("ReleaseAddress", baseTime.Add(5*time.Hour), `{"allocationId": "eipalloc-0123456789abcdef0"}`, `{"_return": true}`),
+	}
+}
+
+func TestParseCloudTrailEvent(t *testing.T) {
+	eventTime := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	cloudTrailRecord := cloudTrailRecordFactory("AssociateAddress", eventTime, `{"publicIp": "`+testIPAddr+`"}`, `null`)
+
+	if cloudTrailRecord.EventName != "AssociateAddress" || !cloudTrailRecord.EventTime.Equal(eventTime) || cloudTrailRecord.RecipientAccountID != "123456789012" {
+		t.Errorf("Parsing CloudTrail event failed; received %+v", cloudTrailRecord)
+	}
+}
+
+func TestAttributeFromCloudTrail(t *testing.T) {
+	baseTime := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		name, expectedConfidence, expectedResourceID string
+		at                                           time.Time
+		expectedEvidenceCnt                          int
+	}{
+		{"before_allocation", iphistory.ConfidenceNone, "", baseTime.Add(-time.Hour), 0},
+		{"allocated", iphistory.ConfidenceLow, "eipalloc-0123456789abcdef0", baseTime.Add(30 * time.Minute), 1},
+		{"associated", iphistory.ConfidenceMedium, "i-0123456789abcdef0", baseTime.Add(2 * time.Hour), 2},
+		{"disassociated", iphistory.ConfidenceLow, "eipalloc-0123456789abcdef0", baseTime.Add(4 * time.Hour), 3},
+		{"released", iphistory.ConfidenceNone, "", baseTime.Add(6 * time.Hour), 4},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			attribution := iphistory.AttributeFromCloudTrail(testIPAddr, td.at, eipLifecycleFactory(baseTime))
+
+			if attribution.Confidence != td.expectedConfidence || attribution.Resource.Id != td.expectedResourceID {
+				t.Errorf("Attributing IP from CloudTrail failed; expected %s (%s confidence), received %s (%s confidence)", td.expectedResourceID, td.expectedConfidence, attribution.Resource.Id, attribution.Confidence)
+			}
+
+			if len(attribution.Evidence) != td.expectedEvidenceCnt {
+				t.Errorf("Attributing IP from CloudTrail failed; expected %d pieces of evidence, received %d", td.expectedEvidenceCnt, len(attribution.Evidence))
+			}
+		})
+	}
+}
+
+func TestAttributeFromCloudTrail_FailedEventsIgnored(t *testing.T) {
+	baseTime := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	cloudTrailRecords := eipLifecycleFactory(baseTime)
+	cloudTrailRecords[1].ErrorCode = "UnauthorizedOperation"
+
+	attribution := iphistory.AttributeFromCloudTrail(testIPAddr, baseTime.Add(2*time.Hour), cloudTrailRecords)
+	if attribution.Confidence != iphistory.ConfidenceLow || attribution.Resource.Id != "eipalloc-0123456789abcdef0" {
+		t.Errorf("Attributing IP from CloudTrail failed; expected failed association to be ignored, received %s (%s confidence)", attribution.Resource.Id, attribution.Confidence)
+	}
+}
+
+func TestAttributeFromConfig(t *testing.T) {
+	at := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		name, expectedRID string
+		configItem        configtypes.ConfigurationItem
+		expectedFound     bool
+	}{
+		{
+			"eni_attached",
+			"arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0",
+			configtypes.ConfigurationItem{
+				ResourceType:            configtypes.ResourceTypeNetworkInterface,
+				ResourceId:              aws.String("eni-0123456789abcdef0"),
+				AccountId:               aws.String("123456789012"),
+				AwsRegion:               aws.String("us-east-1"),
+				ConfigurationItemStatus: configtypes.ConfigurationItemStatusOk,
+				Configuration:           aws.String(`{"association": {"publicIp": "` + testIPAddr + `"}, "attachment": {"instanceId": "i-0123456789abcdef0"}}`),
+			},
+			true,
+		},
+		{
+			"eni_unattached",
+			"arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0123456789abcdef0",
+			configtypes.ConfigurationItem{
+				ResourceType:            configtypes.ResourceTypeNetworkInterface,
+				ResourceId:              aws.String("eni-0123456789abcdef0"),
+				AccountId:               aws.String("123456789012"),
+				AwsRegion:               aws.String("us-east-1"),
+				ConfigurationItemStatus: configtypes.ConfigurationItemStatusOk,
+				Configuration:           aws.String(`{"association": {"publicIp": "` + testIPAddr + `"}}`),
+			},
+			true,
+		},
+		{
+			"different_ip",
+			"",
+			configtypes.ConfigurationItem{
+				ResourceType:            configtypes.ResourceTypeInstance,
+				ResourceId:              aws.String("i-0123456789abcdef0"),
+				ConfigurationItemStatus: configtypes.ConfigurationItemStatusOk,
+				Configuration:           aws.String(`{"publicIpAddress": "1.1.1.1"}`),
+			},
+			false,
+		},
+		{
+			"deleted",
+			"",
+			configtypes.ConfigurationItem{
+				ResourceType:            configtypes.ResourceTypeEip,
+				ResourceId:              aws.String("eipalloc-0123456789abcdef0"),
+				ConfigurationItemStatus: configtypes.ConfigurationItemStatusResourceDeleted,
+			},
+			false,
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			attribution, found := iphistory.AttributeFromConfig(testIPAddr, at, []configtypes.ConfigurationItem{td.configItem})
+
+			if found != td.expectedFound || attribution.Resource.RID != td.expectedRID {
+				t.Errorf("Attributing IP from AWS Config failed; expected %s (%t), received %s (%t)", td.expectedRID, td.expectedFound, attribution.Resource.RID, found)
+			}
+
+			if found && attribution.Confidence != iphistory.ConfidenceHigh {
+				t.Errorf("Attributing IP from AWS Config failed; expected %s confidence, received %s", iphistory.ConfidenceHigh, attribution.Confidence)
+			}
+		})
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	baseTime := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	at := baseTime.Add(2 * time.Hour)
+
+	configItems := []configtypes.ConfigurationItem{
+		{
+			ResourceType:            configtypes.ResourceTypeEip,
+			ResourceId:              aws.String("eipalloc-0123456789abcdef0"),
+			AccountId:               aws.String("123456789012"),
+			AwsRegion:               aws.String("us-east-1"),
+			ConfigurationItemStatus: configtypes.ConfigurationItemStatusOk,
+			Configuration:           aws.String(`{"publicIp": "` + testIPAddr + `", "instanceId": "i-0123456789abcdef0"}`),
+		},
+	}
+
+	attribution := iphistory.Attribute(testIPAddr, at, configItems, eipLifecycleFactory(baseTime))
+
+	if attribution.Confidence != iphistory.ConfidenceHigh || attribution.Resource.Id != "i-0123456789abcdef0" {
+		t.Errorf("Attributing IP failed; expected i-0123456789abcdef0 (high confidence), received %s (%s confidence)", attribution.Resource.Id, attribution.Confidence)
+	}
+
+	// Config evidence comes first, followed by the CloudTrail events that support it
+	if len(attribution.Evidence) != 3 || attribution.Evidence[0].Source != "config" {
+		t.Errorf("Attributing IP failed; expected AWS Config evidence followed by 2 CloudTrail events, received %+v", attribution.Evidence)
+	}
+}
+
+func TestGetCandidateResourceIDs(t *testing.T) {
+	baseTime := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	attribution := iphistory.AttributeFromCloudTrail(testIPAddr, baseTime.Add(6*time.Hour), eipLifecycleFactory(baseTime))
+
+	resourceIDs := iphistory.GetCandidateResourceIDs(attribution)
+
+	expectedResourceIDs := []string{"eipalloc-0123456789abcdef0", "i-0123456789abcdef0"}
+	if len(resourceIDs) != len(expectedResourceIDs) || resourceIDs[0] != expectedResourceIDs[0] || resourceIDs[1] != expectedResourceIDs[1] {
+		t.Errorf("Getting candidate resource IDs failed; expected %s, received %s", expectedResourceIDs, resourceIDs)
+	}
+}
@@ -6,19 +6,29 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	"github.com/magneticstain/ip-2-cloudresource/azure/plugin/aks"
+	"github.com/magneticstain/ip-2-cloudresource/azure/plugin/app_service"
+	"github.com/magneticstain/ip-2-cloudresource/azure/plugin/application_gateway"
+	"github.com/magneticstain/ip-2-cloudresource/azure/plugin/azure_firewall"
 	azcdn "github.com/magneticstain/ip-2-cloudresource/azure/plugin/cdn"
+	"github.com/magneticstain/ip-2-cloudresource/azure/plugin/container_apps"
+	"github.com/magneticstain/ip-2-cloudresource/azure/plugin/container_instances"
 	"github.com/magneticstain/ip-2-cloudresource/azure/plugin/load_balancer"
 	virtual_machine "github.com/magneticstain/ip-2-cloudresource/azure/plugin/virtual_machines"
+	"github.com/magneticstain/ip-2-cloudresource/azure/plugin/vm_scale_sets"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 type AzureController struct {
-	AzureConn azidentity.DefaultAzureCredential
+	AzureConn azureconnector.AzureConnector
+	// shared with plugins so resource inventories are only fetched once per subscription when searching for multiple IPs
+	InvCache *inventorycache.InventoryCache
 }
 
-func New() (AzureController, error) {
-	azConn, err := ConnectToAzure()
+func New(connConfig azureconnector.AzureConnectorConfig) (AzureController, error) {
+	azConn, err := azureconnector.New(connConfig)
 	if err != nil {
 		return AzureController{}, err
 	}
@@ -28,22 +38,22 @@ func New() (AzureController, error) {
 	return azc, err
 }
 
-func ConnectToAzure() (azidentity.DefaultAzureCredential, error) {
-	log.Debug("connecting to Azure using default credentials")
-	dac, err := azidentity.NewDefaultAzureCredential(nil)
-
-	return *dac, err
-}
-
 func GetSupportedSvcs() []string {
 	return []string{
 		"virtual_machines",
 		"load_balancer",
 		"cdn",
+		"application_gateway",
+		"azure_firewall",
+		"app_service",
+		"container_apps",
+		"vm_scale_sets",
+		"aks",
+		"container_instances",
 	}
 }
 
-func (azctrlr AzureController) SearchAzureSvc(subscriptionID, ipAddr, cloudSvc string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
+func (azctrlr AzureController) SearchAzureSvc(subscriptionID, ipAddr, cloudSvc string, doNetMapping bool, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
 	var err error
 
 	log.Debug("searching ", cloudSvc, " in subscription ", subscriptionID, " using Azure controller")
@@ -53,6 +63,8 @@ func (azctrlr AzureController) SearchAzureSvc(subscriptionID, ipAddr, cloudSvc s
 		azvmp := virtual_machine.AzVirtualMachinePlugin{
 			SubscriptionID: subscriptionID,
 			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+			NetworkMapping: doNetMapping,
 		}
 
 		matchingResource, err = azvmp.SearchResources(ipAddr, matchingResource)
@@ -63,6 +75,8 @@ func (azctrlr AzureController) SearchAzureSvc(subscriptionID, ipAddr, cloudSvc s
 		azlbp := load_balancer.AzLoadBalancerPlugin{
 			SubscriptionID: subscriptionID,
 			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+			NetworkMapping: doNetMapping,
 		}
 
 		matchingResource, err = azlbp.SearchResources(ipAddr, matchingResource)
@@ -73,12 +87,94 @@ func (azctrlr AzureController) SearchAzureSvc(subscriptionID, ipAddr, cloudSvc s
 		azcdnp := azcdn.AzCDNPlugin{
 			SubscriptionID: subscriptionID,
 			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+			NetworkMapping: doNetMapping,
 		}
 
 		matchingResource, err = azcdnp.SearchResources(ipAddr, matchingResource)
 		if err != nil {
 			return *matchingResource, err
 		}
+	case "application_gateway":
+		azagp := application_gateway.AzApplicationGatewayPlugin{
+			SubscriptionID: subscriptionID,
+			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+			NetworkMapping: doNetMapping,
+		}
+
+		matchingResource, err = azagp.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
+	case "azure_firewall":
+		azfwp := azure_firewall.AzFirewallPlugin{
+			SubscriptionID: subscriptionID,
+			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+			NetworkMapping: doNetMapping,
+		}
+
+		matchingResource, err = azfwp.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
+	case "app_service":
+		azasp := app_service.AzAppServicePlugin{
+			SubscriptionID: subscriptionID,
+			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+		}
+
+		matchingResource, err = azasp.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
+	case "container_apps":
+		azcap := container_apps.AzContainerAppsPlugin{
+			SubscriptionID: subscriptionID,
+			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+		}
+
+		matchingResource, err = azcap.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
+	case "vm_scale_sets":
+		azvmssp := vm_scale_sets.AzVMScaleSetPlugin{
+			SubscriptionID: subscriptionID,
+			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+		}
+
+		matchingResource, err = azvmssp.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
+	case "aks":
+		azaksp := aks.AzAKSPlugin{
+			SubscriptionID: subscriptionID,
+			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+			NetworkMapping: doNetMapping,
+		}
+
+		matchingResource, err = azaksp.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
+	case "container_instances":
+		azcip := container_instances.AzContainerInstancesPlugin{
+			SubscriptionID: subscriptionID,
+			AzureConn:      azctrlr.AzureConn,
+			InvCache:       azctrlr.InvCache,
+		}
+
+		matchingResource, err = azcip.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
 	default:
 		msg := fmt.Sprintf("unknown Azure service provided: '%s'", cloudSvc)
 
@@ -87,3 +183,42 @@ func (azctrlr AzureController) SearchAzureSvc(subscriptionID, ipAddr, cloudSvc s
 
 	return *matchingResource, nil
 }
+
+func (azctrlr AzureController) GetAzureSvcInventory(subscriptionID, cloudSvc string) ([]generalResource.Resource, error) {
+	log.Debug("fetching ", cloudSvc, " inventory in subscription ", subscriptionID, " using Azure controller")
+
+	switch cloudSvc {
+	case "virtual_machines":
+		azvmp := virtual_machine.AzVirtualMachinePlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azvmp.GetResourceInventory()
+	case "load_balancer":
+		azlbp := load_balancer.AzLoadBalancerPlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azlbp.GetResourceInventory()
+	case "cdn":
+		azcdnp := azcdn.AzCDNPlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azcdnp.GetResourceInventory()
+	case "application_gateway":
+		azagp := application_gateway.AzApplicationGatewayPlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azagp.GetResourceInventory()
+	case "azure_firewall":
+		azfwp := azure_firewall.AzFirewallPlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azfwp.GetResourceInventory()
+	case "app_service":
+		azasp := app_service.AzAppServicePlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azasp.GetResourceInventory()
+	case "container_apps":
+		azcap := container_apps.AzContainerAppsPlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azcap.GetResourceInventory()
+	case "vm_scale_sets":
+		azvmssp := vm_scale_sets.AzVMScaleSetPlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azvmssp.GetResourceInventory()
+	case "aks":
+		azaksp := aks.AzAKSPlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azaksp.GetResourceInventory()
+	case "container_instances":
+		azcip := container_instances.AzContainerInstancesPlugin{SubscriptionID: subscriptionID, AzureConn: azctrlr.AzureConn, InvCache: azctrlr.InvCache}
+		return azcip.GetResourceInventory()
+	default:
+		return nil, fmt.Errorf("unknown Azure service provided: '%s'", cloudSvc)
+	}
+}
@@ -0,0 +1,171 @@
+package azureconnector
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	log "github.com/sirupsen/logrus"
+)
+
+type AzureConnector struct {
+	Credential    azcore.TokenCredential
+	ClientOptions *arm.ClientOptions
+}
+
+type AzureConnectorConfig struct {
+	// supported values: default, client-secret, client-certificate, workload-identity, managed-identity, cli
+	AuthMethod string
+	// the Entra ID (Azure AD) tenant to authenticate against; not to be confused with the subscription ID being searched
+	DirectoryID                        string
+	ClientID, ClientSecret             string
+	ClientCertPath, ClientCertPassword string
+	Cloud, ARMEndpoint                 string
+}
+
+func GetSupportedAuthMethods() []string {
+	return []string{
+		"default",
+		"client-secret",
+		"client-certificate",
+		"workload-identity",
+		"managed-identity",
+		"cli",
+	}
+}
+
+func New(connConfig AzureConnectorConfig) (AzureConnector, error) {
+	var ac AzureConnector
+
+	cloudConfig, err := GetCloudConfig(connConfig.Cloud, connConfig.ARMEndpoint)
+	if err != nil {
+		return ac, err
+	}
+
+	azCoreClientOpts := azcore.ClientOptions{Cloud: cloudConfig}
+
+	cred, err := ConnectToAzure(connConfig, azCoreClientOpts)
+	if err != nil {
+		return ac, err
+	}
+
+	ac = AzureConnector{
+		Credential:    cred,
+		ClientOptions: &arm.ClientOptions{ClientOptions: policy.ClientOptions{Cloud: cloudConfig}},
+	}
+
+	return ac, nil
+}
+
+func GetCloudConfig(cloudName, armEndpoint string) (cloud.Configuration, error) {
+	var cloudConfig cloud.Configuration
+
+	switch strings.ToLower(cloudName) {
+	case "", "public", "azurepublic":
+		cloudConfig = cloud.AzurePublic
+	case "usgovernment", "azureusgovernment":
+		cloudConfig = cloud.AzureGovernment
+	case "china", "azurechina":
+		cloudConfig = cloud.AzureChina
+	default:
+		return cloudConfig, fmt.Errorf("%s is not a supported Azure cloud", cloudName)
+	}
+
+	if armEndpoint != "" {
+		// the predefined configs share their service map, so we need our own copy before overriding anything in it
+		services := make(map[cloud.ServiceName]cloud.ServiceConfiguration, len(cloudConfig.Services))
+		for svcName, svcConfig := range cloudConfig.Services {
+			services[svcName] = svcConfig
+		}
+
+		armConfig := services[cloud.ResourceManager]
+		armConfig.Endpoint = armEndpoint
+		services[cloud.ResourceManager] = armConfig
+
+		cloudConfig.Services = services
+	}
+
+	return cloudConfig, nil
+}
+
+func NewClientCertificateCredential(connConfig AzureConnectorConfig, clientOpts azcore.ClientOptions) (*azidentity.ClientCertificateCredential, error) {
+	certData, err := os.ReadFile(connConfig.ClientCertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// PEM and PKCS#12 certs are both supported here; the password is only needed for the latter
+	certs, certKey, err := azidentity.ParseCertificates(certData, []byte(connConfig.ClientCertPassword))
+	if err != nil {
+		return nil, err
+	}
+
+	return azidentity.NewClientCertificateCredential(
+		connConfig.DirectoryID,
+		connConfig.ClientID,
+		certs,
+		certKey,
+		&azidentity.ClientCertificateCredentialOptions{ClientOptions: clientOpts},
+	)
+}
+
+func ConnectToAzure(connConfig AzureConnectorConfig, clientOpts azcore.ClientOptions) (azcore.TokenCredential, error) {
+	var cred azcore.TokenCredential
+	var err error
+
+	authMethod := connConfig.AuthMethod
+	if authMethod == "" {
+		authMethod = "default"
+	}
+
+	log.Debug("connecting to Azure using ", authMethod, " credentials")
+
+	switch authMethod {
+	case "default":
+		cred, err = azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			ClientOptions: clientOpts,
+			TenantID:      connConfig.DirectoryID,
+		})
+	case "client-secret":
+		cred, err = azidentity.NewClientSecretCredential(
+			connConfig.DirectoryID,
+			connConfig.ClientID,
+			connConfig.ClientSecret,
+			&azidentity.ClientSecretCredentialOptions{ClientOptions: clientOpts},
+		)
+	case "client-certificate":
+		cred, err = NewClientCertificateCredential(connConfig, clientOpts)
+	case "workload-identity":
+		// the token file path is left to the SDK, which reads it from AZURE_FEDERATED_TOKEN_FILE
+		cred, err = azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions: clientOpts,
+			ClientID:      connConfig.ClientID,
+			TenantID:      connConfig.DirectoryID,
+		})
+	case "managed-identity":
+		miOpts := azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOpts}
+		if connConfig.ClientID != "" {
+			// user-assigned identity; system-assigned identities don't need an ID
+			miOpts.ID = azidentity.ClientID(connConfig.ClientID)
+		}
+
+		cred, err = azidentity.NewManagedIdentityCredential(&miOpts)
+	case "cli":
+		cred, err = azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{TenantID: connConfig.DirectoryID})
+	default:
+		return cred, errors.New(authMethod + " is not a supported Azure authentication method")
+	}
+
+	if err != nil {
+		// the credential constructors return typed nil pointers on error, which would not compare as nil once wrapped in the interface
+		return nil, err
+	}
+
+	return cred, nil
+}
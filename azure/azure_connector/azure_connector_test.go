@@ -0,0 +1,77 @@
+package azureconnector_test
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+)
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		connConfig  azureconnector.AzureConnectorConfig
+		expectError bool
+	}{
+		{azureconnector.AzureConnectorConfig{AuthMethod: "cli"}, false},
+		{azureconnector.AzureConnectorConfig{AuthMethod: "cli", Cloud: "usgovernment"}, false},
+		{azureconnector.AzureConnectorConfig{AuthMethod: "managed-identity", ClientID: "00000000-0000-0000-0000-000000000000"}, false},
+		{azureconnector.AzureConnectorConfig{AuthMethod: "client-secret", DirectoryID: "00000000-0000-0000-0000-000000000000", ClientID: "00000000-0000-0000-0000-000000000000", ClientSecret: "not_a_real_secret"}, false},
+		{azureconnector.AzureConnectorConfig{AuthMethod: "client-secret"}, true},
+		{azureconnector.AzureConnectorConfig{AuthMethod: "client-certificate", ClientCertPath: "/this/path/does/not/exist.pem"}, true},
+		{azureconnector.AzureConnectorConfig{AuthMethod: "magic"}, true},
+		{azureconnector.AzureConnectorConfig{AuthMethod: "cli", Cloud: "mars"}, true},
+	}
+
+	for _, td := range tests {
+		testName := td.connConfig.AuthMethod + "_" + td.connConfig.Cloud
+
+		t.Run(testName, func(t *testing.T) {
+			ac, err := azureconnector.New(td.connConfig)
+
+			if td.expectError {
+				if err == nil {
+					t.Errorf("Azure connector creation should have failed for auth method %s in cloud %s, but did not", td.connConfig.AuthMethod, td.connConfig.Cloud)
+				}
+
+				if ac.Credential != nil {
+					t.Errorf("Azure connector creation failed, but returned a non-nil credential")
+				}
+			} else if err != nil || ac.Credential == nil {
+				t.Errorf("Azure connector creation failed for auth method %s; received error: %s", td.connConfig.AuthMethod, err)
+			}
+		})
+	}
+}
+
+func TestGetCloudConfig(t *testing.T) {
+	var tests = []struct {
+		cloudName, armEndpoint, expectedARMEndpoint string
+	}{
+		{"", "", cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint},
+		{"public", "", cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint},
+		{"usgovernment", "", cloud.AzureGovernment.Services[cloud.ResourceManager].Endpoint},
+		{"china", "", cloud.AzureChina.Services[cloud.ResourceManager].Endpoint},
+		{"public", "http://127.0.0.1:8080", "http://127.0.0.1:8080"},
+	}
+
+	for _, td := range tests {
+		testName := td.cloudName + "_" + td.armEndpoint
+
+		t.Run(testName, func(t *testing.T) {
+			cloudConfig, err := azureconnector.GetCloudConfig(td.cloudName, td.armEndpoint)
+			if err != nil {
+				t.Errorf("Fetching Azure cloud config failed; received error: %s", err)
+			}
+
+			armEndpoint := cloudConfig.Services[cloud.ResourceManager].Endpoint
+			if armEndpoint != td.expectedARMEndpoint {
+				t.Errorf("Fetching Azure cloud config failed; expected ARM endpoint %s, received %s", td.expectedARMEndpoint, armEndpoint)
+			}
+		})
+	}
+
+	// overriding the endpoint should never leak into the SDK's predefined configs
+	if cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint == "http://127.0.0.1:8080" {
+		t.Errorf("Overriding the ARM endpoint modified the SDK's predefined Azure Public cloud config")
+	}
+}
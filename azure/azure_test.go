@@ -22,6 +22,13 @@ func TestSearchAzureSvc(t *testing.T) {
 		{"virtual_machines", "1.1.1.1"},
 		{"load_balancer", "1.1.1.1"},
 		{"cdn", "1.1.1.1"},
+		{"application_gateway", "1.1.1.1"},
+		{"azure_firewall", "1.1.1.1"},
+		{"app_service", "1.1.1.1"},
+		{"container_apps", "1.1.1.1"},
+		{"vm_scale_sets", "1.1.1.1"},
+		{"aks", "1.1.1.1"},
+		{"container_instances", "1.1.1.1"},
 	}
 
 	for _, td := range tests {
@@ -31,7 +38,7 @@ func TestSearchAzureSvc(t *testing.T) {
 		resource := generalResource.Resource{}
 
 		t.Run(testName, func(t *testing.T) {
-			res, _ := ac.SearchAzureSvc("", td.ipAddr, td.cloudSvc, &resource)
+			res, _ := ac.SearchAzureSvc("", td.ipAddr, td.cloudSvc, false, &resource)
 
 			resType := reflect.TypeOf(res)
 			expectedType := "Resource"
@@ -58,7 +65,7 @@ func TestSearchAzureSvc_UnknownCloudSvc(t *testing.T) {
 		resource := generalResource.Resource{}
 
 		t.Run(testName, func(t *testing.T) {
-			_, err := ac.SearchAzureSvc("", td.ipAddr, td.cloudSvc, &resource)
+			_, err := ac.SearchAzureSvc("", td.ipAddr, td.cloudSvc, false, &resource)
 			if err == nil {
 				t.Errorf("Error was expected, but not seen, when performing general Azure search; using %s for unknown cloud service name", td.cloudSvc)
 			}
@@ -0,0 +1,65 @@
+package genericresource
+
+/*
+DEV NOTE:
+---
+Some properties aren't modeled by the typed SDK packages we're able to pull in, so they're only available as raw JSON objects, which we then need to pick apart ourselves.
+*/
+
+import (
+	"strings"
+)
+
+func GetStrProperty(properties any, key string) string {
+	var propVal string
+
+	propMap, ok := properties.(map[string]any)
+	if !ok {
+		return propVal
+	}
+
+	propVal, _ = propMap[key].(string)
+
+	return propVal
+}
+
+func GetMapProperty(properties any, key string) map[string]any {
+	var propVal map[string]any
+
+	propMap, ok := properties.(map[string]any)
+	if !ok {
+		return propVal
+	}
+
+	propVal, _ = propMap[key].(map[string]any)
+
+	return propVal
+}
+
+func GetStrSliceProperty(properties any, key string) []string {
+	var propVals []string
+
+	propMap, ok := properties.(map[string]any)
+	if !ok {
+		return propVals
+	}
+
+	// depending on the service, IP lists are returned either as a JSON array or as a CSV string
+	switch rawVal := propMap[key].(type) {
+	case []any:
+		for _, val := range rawVal {
+			if strVal, ok := val.(string); ok && strVal != "" {
+				propVals = append(propVals, strVal)
+			}
+		}
+	case string:
+		for _, val := range strings.Split(rawVal, ",") {
+			val = strings.TrimSpace(val)
+			if val != "" {
+				propVals = append(propVals, val)
+			}
+		}
+	}
+
+	return propVals
+}
@@ -0,0 +1,90 @@
+package genericresource_test
+
+import (
+	"reflect"
+	"testing"
+
+	genericresource "github.com/magneticstain/ip-2-cloudresource/azure/generic_resource"
+)
+
+func propertiesFactory() map[string]any {
+	return map[string]any{
+		"state":               "Running",
+		"inboundIpAddress":    "20.1.2.3",
+		"outboundIpAddresses": "20.1.2.4, 20.1.2.5,20.1.2.6",
+		"staticIp":            nil,
+		"ipList":              []any{"20.1.2.7", "", "20.1.2.8"},
+		"ipAddress":           map[string]any{"ip": "20.1.2.9", "type": "Public"},
+	}
+}
+
+func TestGetStrProperty(t *testing.T) {
+	var tests = []struct {
+		key, expectedVal string
+	}{
+		{"state", "Running"},
+		{"inboundIpAddress", "20.1.2.3"},
+		{"staticIp", ""},
+		{"notAProperty", ""},
+		{"ipList", ""},
+	}
+
+	props := propertiesFactory()
+	for _, td := range tests {
+		testName := td.key
+
+		t.Run(testName, func(t *testing.T) {
+			propVal := genericresource.GetStrProperty(props, td.key)
+
+			if propVal != td.expectedVal {
+				t.Errorf("Fetching generic Azure resource property failed; key: %s, expected: %s, received: %s", td.key, td.expectedVal, propVal)
+			}
+		})
+	}
+}
+
+func TestGetStrSliceProperty(t *testing.T) {
+	var tests = []struct {
+		key          string
+		expectedVals []string
+	}{
+		{"outboundIpAddresses", []string{"20.1.2.4", "20.1.2.5", "20.1.2.6"}},
+		{"ipList", []string{"20.1.2.7", "20.1.2.8"}},
+		{"staticIp", nil},
+		{"notAProperty", nil},
+	}
+
+	props := propertiesFactory()
+	for _, td := range tests {
+		testName := td.key
+
+		t.Run(testName, func(t *testing.T) {
+			propVals := genericresource.GetStrSliceProperty(props, td.key)
+
+			if !reflect.DeepEqual(propVals, td.expectedVals) {
+				t.Errorf("Fetching generic Azure resource property list failed; key: %s, expected: %v, received: %v", td.key, td.expectedVals, propVals)
+			}
+		})
+	}
+}
+
+func TestGetMapProperty(t *testing.T) {
+	props := propertiesFactory()
+
+	ipAddrProps := genericresource.GetMapProperty(props, "ipAddress")
+	if genericresource.GetStrProperty(ipAddrProps, "ip") != "20.1.2.9" {
+		t.Errorf("Fetching nested generic Azure resource property failed; received: %v", ipAddrProps)
+	}
+
+	missingProps := genericresource.GetMapProperty(props, "state")
+	if missingProps != nil {
+		t.Errorf("Expected nil when fetching non-object generic Azure resource property, received: %v", missingProps)
+	}
+}
+
+func TestGetStrProperty_InvalidProperties(t *testing.T) {
+	propVal := genericresource.GetStrProperty("not a map", "state")
+	if propVal != "" {
+		t.Errorf("Expected empty value when fetching property from invalid property set, received: %s", propVal)
+	}
+}
@@ -0,0 +1,203 @@
+package aks
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	log "github.com/sirupsen/logrus"
+
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+// the Azure cloud provider for Kubernetes tags each public IP it creates with the service(s) using it
+// older clusters use the bare `service` tag instead
+var k8sServiceTagKeys = []string{"k8s-azure-service", "service"}
+
+type AzAKSPlugin struct {
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	NetworkMapping bool
+	SubscriptionID string
+}
+
+func GetK8sServiceFromTags(tags map[string]*string) string {
+	for _, tagKey := range k8sServiceTagKeys {
+		if svcName, found := tags[tagKey]; found && svcName != nil {
+			return *svcName
+		}
+	}
+
+	return ""
+}
+
+func GetLoadBalancerIDFromIPConfig(ipConfigID string) string {
+	// EX: /subscriptions/<sub>/resourceGroups/<rg>/providers/Microsoft.Network/loadBalancers/kubernetes/frontendIPConfigurations/<id>
+	lbID, _, found := strings.Cut(ipConfigID, "/frontendIPConfigurations/")
+	if !found {
+		return ""
+	}
+
+	return lbID
+}
+
+func GetOutboundIPIDs(cluster *armcontainerservice.ManagedCluster) []string {
+	var outboundIPIDs []string
+
+	if cluster.Properties.NetworkProfile == nil || cluster.Properties.NetworkProfile.LoadBalancerProfile == nil {
+		return outboundIPIDs
+	}
+
+	for _, outboundIPRef := range cluster.Properties.NetworkProfile.LoadBalancerProfile.EffectiveOutboundIPs {
+		if outboundIPRef.ID != nil {
+			// resource IDs are case-insensitive, and AKS doesn't always match the casing used by the network API
+			outboundIPIDs = append(outboundIPIDs, strings.ToLower(*outboundIPRef.ID))
+		}
+	}
+
+	return outboundIPIDs
+}
+
+func (azaksp *AzAKSPlugin) ProcessPublicIP(cluster *armcontainerservice.ManagedCluster, publicIP *armnetwork.PublicIPAddress, outboundIPIDs []string) generalResource.Resource {
+	clusterStatus := ""
+	if cluster.Properties.PowerState != nil && cluster.Properties.PowerState.Code != nil {
+		clusterStatus = string(*cluster.Properties.PowerState.Code)
+	} else if cluster.Properties.ProvisioningState != nil {
+		clusterStatus = *cluster.Properties.ProvisioningState
+	}
+
+	// the cluster is the resource we're really after, but the frontend is what tells us which k8s service owns the IP
+	currentResource := generalResource.Resource{
+		Id:        *publicIP.ID,
+		RID:       *cluster.ID,
+		AccountID: azaksp.SubscriptionID,
+		Name:      *publicIP.Name,
+		Status:    clusterStatus,
+		CloudSvc:  "aks",
+	}
+
+	k8sSvc := GetK8sServiceFromTags(publicIP.Tags)
+	if k8sSvc != "" {
+		currentResource.Name = k8sSvc
+		currentResource.IPType = "inbound"
+	} else if slices.Contains(outboundIPIDs, strings.ToLower(*publicIP.ID)) {
+		currentResource.IPType = "outbound"
+	}
+
+	ipAddr := *publicIP.Properties.IPAddress
+	if publicIP.Properties.PublicIPAddressVersion != nil && *publicIP.Properties.PublicIPAddressVersion == armnetwork.IPVersionIPv6 {
+		currentResource.PublicIPv6Addrs = append(currentResource.PublicIPv6Addrs, ipAddr)
+	} else {
+		currentResource.PublicIPv4Addrs = append(currentResource.PublicIPv4Addrs, ipAddr)
+	}
+
+	if azaksp.NetworkMapping {
+		currentResource.NetworkMap = append(currentResource.NetworkMap, *publicIP.ID)
+
+		if publicIP.Properties.IPConfiguration != nil && publicIP.Properties.IPConfiguration.ID != nil {
+			lbID := GetLoadBalancerIDFromIPConfig(*publicIP.Properties.IPConfiguration.ID)
+			if lbID != "" {
+				currentResource.NetworkMap = append(currentResource.NetworkMap, lbID)
+			}
+		}
+
+		if k8sSvc != "" {
+			currentResource.NetworkMap = append(currentResource.NetworkMap, "k8s service: "+k8sSvc)
+		}
+	}
+
+	return currentResource
+}
+
+func (azaksp AzAKSPlugin) GetCacheKey() string {
+	// network maps are only included in the fetched resources when network mapping is enabled, so those get their own cache entry
+	cacheKey := "aks/" + azaksp.SubscriptionID
+	if azaksp.NetworkMapping {
+		cacheKey += "/network_map"
+	}
+
+	return cacheKey
+}
+
+func (azaksp *AzAKSPlugin) GetResources() ([]generalResource.Resource, error) {
+	var aksResources []generalResource.Resource
+
+	clusterClient, err := armcontainerservice.NewManagedClustersClient(azaksp.SubscriptionID, azaksp.AzureConn.Credential, azaksp.AzureConn.ClientOptions)
+	if err != nil {
+		return aksResources, err
+	}
+
+	pubIpAddrClient, err := armnetwork.NewPublicIPAddressesClient(azaksp.SubscriptionID, azaksp.AzureConn.Credential, azaksp.AzureConn.ClientOptions)
+	if err != nil {
+		return aksResources, err
+	}
+
+	ctx := context.Background()
+	clusterPager := clusterClient.NewListPager(nil)
+	for clusterPager.More() {
+		nextClusterSet, err := clusterPager.NextPage(ctx)
+		if err != nil {
+			return aksResources, err
+		}
+		clusterSet := nextClusterSet.Value
+		log.Debug("found [ ", len(clusterSet), " ] Azure Kubernetes Service clusters")
+
+		for _, cluster := range clusterSet {
+			log.Debug("AKS cluster found - ID: ", *cluster.ID, ", Name: ", *cluster.Name)
+
+			if cluster.Properties == nil || cluster.Properties.NodeResourceGroup == nil {
+				continue
+			}
+
+			outboundIPIDs := GetOutboundIPIDs(cluster)
+
+			// the load balancer and public IPs for each cluster are created in the cluster's node resource group (MC_*)
+			pubIpPager := pubIpAddrClient.NewListPager(*cluster.Properties.NodeResourceGroup, nil)
+			for pubIpPager.More() {
+				nextPubIpSet, err := pubIpPager.NextPage(ctx)
+				if err != nil {
+					return aksResources, err
+				}
+
+				for _, publicIP := range nextPubIpSet.Value {
+					if publicIP.Properties == nil || publicIP.Properties.IPAddress == nil {
+						continue
+					}
+
+					aksResources = append(aksResources, azaksp.ProcessPublicIP(cluster, publicIP, outboundIPIDs))
+				}
+			}
+		}
+	}
+
+	return aksResources, nil
+}
+
+func (azaksp AzAKSPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+	log.Debug("fetching and searching Azure Kubernetes Service resources")
+
+	fetchedResources, err := inventorycache.Fetch(azaksp.InvCache, azaksp.GetCacheKey(), azaksp.GetResources)
+	if err != nil {
+		return matchingResource, err
+	}
+
+	for _, aksResource := range fetchedResources {
+		if slices.Contains(aksResource.PublicIPv4Addrs, tgtIP) || slices.Contains(aksResource.PublicIPv6Addrs, tgtIP) {
+			matchingResource = &aksResource
+
+			log.Debug("IP found as AKS load balancer frontend -> ", matchingResource.RID, " (", matchingResource.Name, ") with network info ", matchingResource.NetworkMap)
+
+			break
+		}
+	}
+
+	return matchingResource, nil
+}
+
+func (azaksp AzAKSPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(azaksp.InvCache, azaksp.GetCacheKey(), azaksp.GetResources)
+}
@@ -0,0 +1,181 @@
+package aks_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/aks"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func azaksPlugFactory() plugin.AzAKSPlugin {
+	azaksPlug := plugin.AzAKSPlugin{NetworkMapping: true}
+
+	return azaksPlug
+}
+
+func strPtr(str string) *string {
+	return &str
+}
+
+func TestGetK8sServiceFromTags(t *testing.T) {
+	var tests = []struct {
+		tags           map[string]*string
+		expectedK8sSvc string
+	}{
+		{map[string]*string{"k8s-azure-service": strPtr("ingress-nginx/ingress-nginx-controller")}, "ingress-nginx/ingress-nginx-controller"},
+		{map[string]*string{"service": strPtr("default/web")}, "default/web"},
+		{map[string]*string{"aks-managed-type": strPtr("aks-slb-managed-outbound-ip")}, ""},
+		{nil, ""},
+	}
+
+	for _, td := range tests {
+		testName := td.expectedK8sSvc
+
+		t.Run(testName, func(t *testing.T) {
+			k8sSvc := plugin.GetK8sServiceFromTags(td.tags)
+
+			if k8sSvc != td.expectedK8sSvc {
+				t.Errorf("Fetching k8s service from AKS public IP tags failed; expected %s, received %s", td.expectedK8sSvc, k8sSvc)
+			}
+		})
+	}
+}
+
+func TestProcessPublicIP(t *testing.T) {
+	azaksPlug := azaksPlugFactory()
+
+	outboundIPID := "/subscriptions/x/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Network/publicIPAddresses/outbound-ip"
+	cluster := armcontainerservice.ManagedCluster{
+		ID:   strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/cluster"),
+		Name: strPtr("cluster"),
+		Properties: &armcontainerservice.ManagedClusterProperties{
+			ProvisioningState: strPtr("Succeeded"),
+			NetworkProfile: &armcontainerservice.NetworkProfile{
+				LoadBalancerProfile: &armcontainerservice.ManagedClusterLoadBalancerProfile{
+					EffectiveOutboundIPs: []*armcontainerservice.ResourceReference{{ID: &outboundIPID}},
+				},
+			},
+		},
+	}
+
+	svcIP := armnetwork.PublicIPAddress{
+		ID:   strPtr("/subscriptions/x/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Network/publicIPAddresses/kubernetes-abc123"),
+		Name: strPtr("kubernetes-abc123"),
+		Tags: map[string]*string{"k8s-azure-service": strPtr("default/web")},
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+			IPAddress: strPtr("20.1.2.3"),
+			IPConfiguration: &armnetwork.IPConfiguration{
+				ID: strPtr("/subscriptions/x/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Network/loadBalancers/kubernetes/frontendIPConfigurations/abc123"),
+			},
+		},
+	}
+	outboundIP := armnetwork.PublicIPAddress{
+		ID:         &outboundIPID,
+		Name:       strPtr("outbound-ip"),
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{IPAddress: strPtr("20.1.2.4")},
+	}
+
+	outboundIPIDs := plugin.GetOutboundIPIDs(&cluster)
+
+	svcResource := azaksPlug.ProcessPublicIP(&cluster, &svcIP, outboundIPIDs)
+	expectedNetworkMap := []string{
+		*svcIP.ID,
+		"/subscriptions/x/resourceGroups/MC_rg_cluster_eastus/providers/Microsoft.Network/loadBalancers/kubernetes",
+		"k8s service: default/web",
+	}
+	if svcResource.RID != *cluster.ID || svcResource.Name != "default/web" || svcResource.IPType != "inbound" {
+		t.Errorf("Processing AKS service public IP failed; received RID: %s, Name: %s, IP Type: %s", svcResource.RID, svcResource.Name, svcResource.IPType)
+	}
+	if !reflect.DeepEqual(svcResource.NetworkMap, expectedNetworkMap) {
+		t.Errorf("Processing AKS service public IP failed; expected network map %v, received %v", expectedNetworkMap, svcResource.NetworkMap)
+	}
+
+	outboundResource := azaksPlug.ProcessPublicIP(&cluster, &outboundIP, outboundIPIDs)
+	if outboundResource.IPType != "outbound" {
+		t.Errorf("Processing AKS outbound public IP failed; expected outbound IP type, received %s", outboundResource.IPType)
+	}
+}
+
+func TestGetCacheKey(t *testing.T) {
+	var tests = []struct {
+		networkMapping   bool
+		expectedCacheKey string
+	}{
+		{false, "aks/my-subscription"},
+		{true, "aks/my-subscription/network_map"},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("NetworkMapping=%t", td.networkMapping)
+
+		t.Run(testName, func(t *testing.T) {
+			plug := plugin.AzAKSPlugin{SubscriptionID: "my-subscription", NetworkMapping: td.networkMapping}
+
+			cacheKey := plug.GetCacheKey()
+			if cacheKey != td.expectedCacheKey {
+				t.Errorf("Generating AKS cache key failed; expected %s, received %s", td.expectedCacheKey, cacheKey)
+			}
+		})
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	azaksPlug := azaksPlugFactory()
+
+	aksResources, _ := azaksPlug.GetResources()
+
+	expectedType := "Resource"
+	for _, resource := range aksResources {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resources via Azure AKS Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	azaksPlug := azaksPlugFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+		{"18.161.22.61", "Resource"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "Resource"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
+	}
+
+	var matchingResource generalResource.Resource
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			matchedResource, _ := azaksPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedResourceType := reflect.TypeOf(*matchedResource)
+
+			if matchedResourceType.Name() != td.expectedType {
+				t.Errorf("Azure AKS search failed; expected %s after search, received %s", td.expectedType, matchedResourceType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	azaksp := azaksPlugFactory()
+
+	inventory, _ := azaksp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure AKS Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package app_service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	log "github.com/sirupsen/logrus"
+
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	genericresource "github.com/magneticstain/ip-2-cloudresource/azure/generic_resource"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type AzAppServicePlugin struct {
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	SubscriptionID string
+}
+
+func appendUniqueIPAddrs(ipAddrs []string, newIPAddrs ...string) []string {
+	for _, ipAddr := range newIPAddrs {
+		if ipAddr != "" && !slices.Contains(ipAddrs, ipAddr) {
+			ipAddrs = append(ipAddrs, ipAddr)
+		}
+	}
+
+	return ipAddrs
+}
+
+func splitIPAddrList(ipAddrList *string) []string {
+	var ipAddrs []string
+
+	// sites list their outbound IPs as a CSV string
+	if ipAddrList == nil {
+		return ipAddrs
+	}
+
+	for _, ipAddr := range strings.Split(*ipAddrList, ",") {
+		ipAddrs = append(ipAddrs, strings.TrimSpace(ipAddr))
+	}
+
+	return ipAddrs
+}
+
+func GetUntypedSiteProps(rawResp *http.Response) map[string]any {
+	// the SDK's site model doesn't include the inbound or IPv6 addresses, even though the API returns them, so we pull them from the raw response
+	untypedSiteProps := make(map[string]any)
+
+	if rawResp == nil {
+		return untypedSiteProps
+	}
+
+	rawPage, err := runtime.Payload(rawResp)
+	if err != nil {
+		log.Debug("unable to read raw Azure App Service site list: ", err)
+		return untypedSiteProps
+	}
+
+	var sitePage struct {
+		Value []struct {
+			ID         string         `json:"id"`
+			Properties map[string]any `json:"properties"`
+		} `json:"value"`
+	}
+	err = json.Unmarshal(rawPage, &sitePage)
+	if err != nil {
+		log.Debug("unable to parse raw Azure App Service site list: ", err)
+		return untypedSiteProps
+	}
+
+	for _, site := range sitePage.Value {
+		untypedSiteProps[site.ID] = site.Properties
+	}
+
+	return untypedSiteProps
+}
+
+func ParseSiteIPAddrs(siteProps *armappservice.SiteProperties, untypedSiteProps any) ([]string, []string) {
+	var inboundIPAddrs, outboundIPAddrs []string
+
+	inboundIPAddrs = appendUniqueIPAddrs(
+		inboundIPAddrs,
+		genericresource.GetStrProperty(untypedSiteProps, "inboundIpAddress"),
+		genericresource.GetStrProperty(untypedSiteProps, "inboundIpv6Address"),
+	)
+
+	// possible outbound IPs are a superset of the current outbound IPs and include any IPs the app may move to after a scaling event
+	if siteProps != nil {
+		outboundIPAddrs = appendUniqueIPAddrs(outboundIPAddrs, splitIPAddrList(siteProps.OutboundIPAddresses)...)
+		outboundIPAddrs = appendUniqueIPAddrs(outboundIPAddrs, splitIPAddrList(siteProps.PossibleOutboundIPAddresses)...)
+	}
+	for _, outboundKey := range []string{"outboundIpv6Addresses", "possibleOutboundIpv6Addresses"} {
+		outboundIPAddrs = appendUniqueIPAddrs(outboundIPAddrs, genericresource.GetStrSliceProperty(untypedSiteProps, outboundKey)...)
+	}
+
+	return inboundIPAddrs, outboundIPAddrs
+}
+
+func (azasp *AzAppServicePlugin) ProcessSite(site *armappservice.Site, untypedSiteProps any) AppServiceResource {
+	// App Service web apps and Function Apps are both sites; they're told apart by their kind
+	siteKind := "app"
+	if site.Kind != nil {
+		siteKind = *site.Kind
+	}
+	var siteStatus string
+	if site.Properties != nil && site.Properties.State != nil {
+		siteStatus = *site.Properties.State
+	}
+
+	log.Debug("Azure App Service site found - ID: ", *site.ID, ", Name: ", *site.Name, ", Kind: ", siteKind, ", Status: ", siteStatus)
+
+	inboundIPAddrs, outboundIPAddrs := ParseSiteIPAddrs(site.Properties, untypedSiteProps)
+
+	currentResource := AppServiceResource{
+		Resource: generalResource.Resource{
+			Id:        *site.ID,
+			RID:       *site.ID,
+			AccountID: azasp.SubscriptionID,
+			Name:      *site.Name,
+			Status:    siteStatus,
+			CloudSvc:  "app_service",
+		},
+		InboundIPAddrs:  inboundIPAddrs,
+		OutboundIPAddrs: outboundIPAddrs,
+	}
+
+	// unlike most services, the outbound IPs are a big part of why we'd want to search App Service, so they're included with the public IPs
+	for _, ipAddr := range appendUniqueIPAddrs(slices.Clone(inboundIPAddrs), outboundIPAddrs...) {
+		if strings.Contains(ipAddr, ":") {
+			currentResource.PublicIPv6Addrs = append(currentResource.PublicIPv6Addrs, ipAddr)
+		} else {
+			currentResource.PublicIPv4Addrs = append(currentResource.PublicIPv4Addrs, ipAddr)
+		}
+	}
+
+	return currentResource
+}
+
+func (azasp *AzAppServicePlugin) GetResources() ([]AppServiceResource, error) {
+	var siteResources []AppServiceResource
+
+	siteClient, err := armappservice.NewWebAppsClient(azasp.SubscriptionID, azasp.AzureConn.Credential, azasp.AzureConn.ClientOptions)
+	if err != nil {
+		return siteResources, err
+	}
+
+	ctx := context.Background()
+	sitePager := siteClient.NewListPager(nil)
+	for sitePager.More() {
+		var rawResp *http.Response
+
+		nextSiteSet, err := sitePager.NextPage(runtime.WithCaptureResponse(ctx, &rawResp))
+		if err != nil {
+			return siteResources, err
+		}
+		siteSet := nextSiteSet.Value
+		log.Debug("found [ ", len(siteSet), " ] Azure App Service sites")
+
+		untypedSiteProps := GetUntypedSiteProps(rawResp)
+		for _, site := range siteSet {
+			siteResources = append(siteResources, azasp.ProcessSite(site, untypedSiteProps[*site.ID]))
+		}
+	}
+
+	return siteResources, nil
+}
+
+func MatchIPDirection(appResource AppServiceResource, tgtIP string) string {
+	var ipDirection string
+
+	// inbound is checked first since an app's inbound IP may also show up in its list of outbound IPs
+	if slices.Contains(appResource.InboundIPAddrs, tgtIP) {
+		ipDirection = "inbound"
+	} else if slices.Contains(appResource.OutboundIPAddrs, tgtIP) {
+		ipDirection = "outbound"
+	}
+
+	return ipDirection
+}
+
+func (azasp AzAppServicePlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+	log.Debug("fetching and searching Azure App Service resources")
+
+	fetchedResources, err := inventorycache.Fetch(azasp.InvCache, "app_service/"+azasp.SubscriptionID, azasp.GetResources)
+	if err != nil {
+		return matchingResource, err
+	}
+
+	for _, siteResource := range fetchedResources {
+		ipDirection := MatchIPDirection(siteResource, tgtIP)
+		if ipDirection != "" {
+			matchingResource = &siteResource.Resource
+			matchingResource.IPType = ipDirection
+
+			log.Debug("IP found as App Service ", ipDirection, " IP -> ", matchingResource.RID)
+
+			break
+		}
+	}
+
+	return matchingResource, nil
+}
+
+func (azasp AzAppServicePlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	var inventory []generalResource.Resource
+
+	fetchedResources, err := inventorycache.Fetch(azasp.InvCache, "app_service/"+azasp.SubscriptionID, azasp.GetResources)
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, siteResource := range fetchedResources {
+		inventory = append(inventory, siteResource.Resource)
+	}
+
+	return inventory, nil
+}
@@ -0,0 +1,10 @@
+package app_service
+
+import (
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type AppServiceResource struct {
+	generalResource.Resource
+	InboundIPAddrs, OutboundIPAddrs []string
+}
@@ -0,0 +1,167 @@
+package app_service_test
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+
+	genericresource "github.com/magneticstain/ip-2-cloudresource/azure/generic_resource"
+	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/app_service"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func azasPlugFactory() plugin.AzAppServicePlugin {
+	azasPlug := plugin.AzAppServicePlugin{}
+
+	return azasPlug
+}
+
+func siteFactory() (*armappservice.Site, map[string]any) {
+	siteID := "/subscriptions/x/resourceGroups/rg/providers/Microsoft.Web/sites/my-func"
+	siteName := "my-func"
+	siteKind := "functionapp,linux"
+	siteState := "Running"
+	outboundIPAddrs := "20.1.2.4,20.1.2.5"
+	possibleOutboundIPAddrs := "20.1.2.4,20.1.2.5,20.1.2.6"
+
+	site := &armappservice.Site{
+		ID:   &siteID,
+		Name: &siteName,
+		Kind: &siteKind,
+		Properties: &armappservice.SiteProperties{
+			State:                       &siteState,
+			OutboundIPAddresses:         &outboundIPAddrs,
+			PossibleOutboundIPAddresses: &possibleOutboundIPAddrs,
+		},
+	}
+
+	untypedSiteProps := map[string]any{
+		"inboundIpAddress":      "20.1.2.3",
+		"outboundIpv6Addresses": "2603:1030:b:3::48",
+	}
+
+	return site, untypedSiteProps
+}
+
+func TestGetUntypedSiteProps(t *testing.T) {
+	rawPage := `{"value": [{"id": "/subscriptions/x/resourceGroups/rg/providers/Microsoft.Web/sites/my-app", "properties": {"inboundIpAddress": "20.1.2.3"}}]}`
+	rawResp := &http.Response{Body: io.NopCloser(strings.NewReader(rawPage))}
+
+	untypedSiteProps := plugin.GetUntypedSiteProps(rawResp)
+
+	siteProps := untypedSiteProps["/subscriptions/x/resourceGroups/rg/providers/Microsoft.Web/sites/my-app"]
+	if inboundIPAddr := genericresource.GetStrProperty(siteProps, "inboundIpAddress"); inboundIPAddr != "20.1.2.3" {
+		t.Errorf("Parsing raw Azure App Service site list failed; expected inbound IP 20.1.2.3, received %s", inboundIPAddr)
+	}
+
+	if untypedSiteProps := plugin.GetUntypedSiteProps(nil); len(untypedSiteProps) != 0 {
+		t.Errorf("Parsing missing Azure App Service site list failed; expected no sites, received %v", untypedSiteProps)
+	}
+}
+
+func TestProcessSite(t *testing.T) {
+	azasPlug := azasPlugFactory()
+
+	siteResource := azasPlug.ProcessSite(siteFactory())
+
+	expectedIPv4Addrs := []string{"20.1.2.3", "20.1.2.4", "20.1.2.5", "20.1.2.6"}
+	if !reflect.DeepEqual(siteResource.PublicIPv4Addrs, expectedIPv4Addrs) {
+		t.Errorf("Processing Azure App Service site failed; expected IPv4 addresses %v, received %v", expectedIPv4Addrs, siteResource.PublicIPv4Addrs)
+	}
+
+	expectedIPv6Addrs := []string{"2603:1030:b:3::48"}
+	if !reflect.DeepEqual(siteResource.PublicIPv6Addrs, expectedIPv6Addrs) {
+		t.Errorf("Processing Azure App Service site failed; expected IPv6 addresses %v, received %v", expectedIPv6Addrs, siteResource.PublicIPv6Addrs)
+	}
+
+	if siteResource.Status != "Running" {
+		t.Errorf("Processing Azure App Service site failed; expected Running status, received %s", siteResource.Status)
+	}
+}
+
+func TestMatchIPDirection(t *testing.T) {
+	azasPlug := azasPlugFactory()
+	siteResource := azasPlug.ProcessSite(siteFactory())
+
+	var tests = []struct {
+		ipAddr, expectedDirection string
+	}{
+		{"20.1.2.3", "inbound"},
+		{"20.1.2.4", "outbound"},
+		{"20.1.2.6", "outbound"},
+		{"2603:1030:b:3::48", "outbound"},
+		{"1.1.1.1", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			ipDirection := plugin.MatchIPDirection(siteResource, td.ipAddr)
+
+			if ipDirection != td.expectedDirection {
+				t.Errorf("Matching Azure App Service IP direction failed; IP: %s, expected: %s, received: %s", td.ipAddr, td.expectedDirection, ipDirection)
+			}
+		})
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	azasPlug := azasPlugFactory()
+
+	siteResources, _ := azasPlug.GetResources()
+
+	expectedType := "AppServiceResource"
+	for _, resource := range siteResources {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resources via Azure App Service Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	azasPlug := azasPlugFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+		{"18.161.22.61", "Resource"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "Resource"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
+	}
+
+	var matchingResource generalResource.Resource
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			matchedSite, _ := azasPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedSiteType := reflect.TypeOf(*matchedSite)
+
+			if matchedSiteType.Name() != td.expectedType {
+				t.Errorf("Azure App Service search failed; expected %s after search, received %s", td.expectedType, matchedSiteType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	azasp := azasPlugFactory()
+
+	inventory, _ := azasp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure App Service Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
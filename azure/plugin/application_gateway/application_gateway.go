@@ -0,0 +1,210 @@
+package application_gateway
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	log "github.com/sirupsen/logrus"
+
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	az_public_ip "github.com/magneticstain/ip-2-cloudresource/azure/public_ip"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+type AzApplicationGatewayPlugin struct {
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	NetworkMapping bool
+	SubscriptionID string
+}
+
+func GetBackendTargets(backendPool *armnetwork.ApplicationGatewayBackendAddressPool) []string {
+	var backendTgts []string
+
+	if backendPool.Properties == nil {
+		return backendTgts
+	}
+
+	// backend targets can be defined as IPs or FQDNs, or be attached directly to a NIC
+	for _, backendAddr := range backendPool.Properties.BackendAddresses {
+		if backendAddr.IPAddress != nil {
+			backendTgts = append(backendTgts, *backendAddr.IPAddress)
+		} else if backendAddr.Fqdn != nil {
+			backendTgts = append(backendTgts, *backendAddr.Fqdn)
+		}
+	}
+
+	for _, backendIpConfig := range backendPool.Properties.BackendIPConfigurations {
+		if backendIpConfig.ID != nil {
+			backendTgts = append(backendTgts, *backendIpConfig.ID)
+		}
+	}
+
+	return backendTgts
+}
+
+func GenerateNetworkMap(appGateway *armnetwork.ApplicationGateway) []string {
+	var networkMap, listenerNames, backendPoolNames, backendTgts []string
+
+	// listeners and backend pools are tied together by request routing rules, so we'll follow those to build the map
+	for _, listener := range appGateway.Properties.HTTPListeners {
+		listenerNames = append(listenerNames, *listener.Name)
+	}
+
+	for _, routingRule := range appGateway.Properties.RequestRoutingRules {
+		if routingRule.Properties == nil || routingRule.Properties.BackendAddressPool == nil {
+			// redirect-only rules don't have a backend pool
+			continue
+		}
+
+		for _, backendPool := range appGateway.Properties.BackendAddressPools {
+			if *backendPool.ID != *routingRule.Properties.BackendAddressPool.ID || slices.Contains(backendPoolNames, *backendPool.Name) {
+				continue
+			}
+
+			backendPoolNames = append(backendPoolNames, *backendPool.Name)
+			backendTgts = append(backendTgts, GetBackendTargets(backendPool)...)
+		}
+	}
+
+	networkMap = append(
+		networkMap,
+		utils.FormatStrSliceAsCSV(listenerNames),
+		utils.FormatStrSliceAsCSV(backendPoolNames),
+		utils.FormatStrSliceAsCSV(backendTgts),
+	)
+
+	return networkMap
+}
+
+func (azagp *AzApplicationGatewayPlugin) GatherPublicIPAddrData(appGateway *armnetwork.ApplicationGateway, ctx context.Context) ([]string, []string, error) {
+	var publicIPv4Addrs, publicIPv6Addrs []string
+
+	for _, frontendIpConfig := range appGateway.Properties.FrontendIPConfigurations {
+		if frontendIpConfig.Properties == nil || frontendIpConfig.Properties.PublicIPAddress == nil {
+			// private frontend
+			continue
+		}
+
+		// the frontend config only references the public IP resource, so we'll need to fetch the address ourselves
+		pubIPAddrData := armnetwork.PublicIPAddress{ID: frontendIpConfig.Properties.PublicIPAddress.ID}
+		publicIP, err := az_public_ip.GetPublicIPAddressProperties(azagp.AzureConn, &pubIPAddrData, ctx)
+		if err != nil {
+			return publicIPv4Addrs, publicIPv6Addrs, err
+		}
+
+		if publicIP.Properties.IPAddress == nil {
+			log.Debug("public IP [ ", *pubIPAddrData.ID, " ] attached to application gateway [ ", *appGateway.Name, " ] has not been allocated an address")
+			continue
+		}
+
+		if publicIP.Properties.PublicIPAddressVersion != nil && *publicIP.Properties.PublicIPAddressVersion == armnetwork.IPVersionIPv6 {
+			publicIPv6Addrs = append(publicIPv6Addrs, *publicIP.Properties.IPAddress)
+		} else {
+			publicIPv4Addrs = append(publicIPv4Addrs, *publicIP.Properties.IPAddress)
+		}
+	}
+
+	return publicIPv4Addrs, publicIPv6Addrs, nil
+}
+
+func (azagp AzApplicationGatewayPlugin) GetCacheKey() string {
+	// network maps are only included in the fetched resources when network mapping is enabled, so those get their own cache entry
+	cacheKey := "application_gateway/" + azagp.SubscriptionID
+	if azagp.NetworkMapping {
+		cacheKey += "/network_map"
+	}
+
+	return cacheKey
+}
+
+func (azagp *AzApplicationGatewayPlugin) GetResources() ([]generalResource.Resource, error) {
+	var agResources []generalResource.Resource
+	var currentResource generalResource.Resource
+	var agID, agName *string
+	var agStatus string
+
+	agClient, err := armnetwork.NewApplicationGatewaysClient(azagp.SubscriptionID, azagp.AzureConn.Credential, azagp.AzureConn.ClientOptions)
+	if err != nil {
+		return agResources, err
+	}
+
+	ctx := context.Background()
+	agPager := agClient.NewListAllPager(nil)
+	for agPager.More() {
+		nextAgSet, err := agPager.NextPage(ctx)
+		if err != nil {
+			return agResources, err
+		}
+		agSet := nextAgSet.Value
+		log.Debug("found [ ", len(agSet), " ] Azure application gateways")
+
+		for _, appGateway := range agSet {
+			agID = appGateway.ID
+			agName = appGateway.Name
+			agStatus = string(*appGateway.Properties.ProvisioningState)
+			if appGateway.Properties.OperationalState != nil {
+				agStatus = fmt.Sprintf("%s (%s)", agStatus, *appGateway.Properties.OperationalState)
+			}
+
+			log.Debug("Azure Application Gateway found - ID: ", *agID, ", Name: ", *agName, ", Status: ", agStatus)
+
+			publicIPv4Addrs, publicIPv6Addrs, err := azagp.GatherPublicIPAddrData(appGateway, ctx)
+			if err != nil {
+				return agResources, err
+			}
+
+			currentResource = generalResource.Resource{
+				Id:              *agID,
+				RID:             *agID,
+				AccountID:       azagp.SubscriptionID,
+				Name:            *agName,
+				Status:          agStatus,
+				CloudSvc:        "application_gateway",
+				PublicIPv4Addrs: publicIPv4Addrs,
+				PublicIPv6Addrs: publicIPv6Addrs,
+			}
+
+			if azagp.NetworkMapping {
+				// everything needed for the map is already included with the gateway, so there's no extra cost to building it here
+				currentResource.NetworkMap = GenerateNetworkMap(appGateway)
+			}
+
+			agResources = append(
+				agResources,
+				currentResource,
+			)
+		}
+	}
+
+	return agResources, nil
+}
+
+func (azagp AzApplicationGatewayPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+	log.Debug("fetching and searching Azure application gateway resources")
+
+	fetchedResources, err := inventorycache.Fetch(azagp.InvCache, azagp.GetCacheKey(), azagp.GetResources)
+	if err != nil {
+		return matchingResource, err
+	}
+
+	for _, agResource := range fetchedResources {
+		if slices.Contains(agResource.PublicIPv4Addrs, tgtIP) || slices.Contains(agResource.PublicIPv6Addrs, tgtIP) {
+			matchingResource = &agResource
+
+			log.Debug("IP found as Application Gateway -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+
+			break
+		}
+	}
+
+	return matchingResource, nil
+}
+
+func (azagp AzApplicationGatewayPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(azagp.InvCache, azagp.GetCacheKey(), azagp.GetResources)
+}
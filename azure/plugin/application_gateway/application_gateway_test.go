@@ -0,0 +1,147 @@
+package application_gateway_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/application_gateway"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func azagPlugFactory() plugin.AzApplicationGatewayPlugin {
+	azagPlug := plugin.AzApplicationGatewayPlugin{}
+
+	return azagPlug
+}
+
+func strPtr(str string) *string {
+	return &str
+}
+
+func TestGenerateNetworkMap(t *testing.T) {
+	backendPoolID := "/subscriptions/x/resourceGroups/rg/providers/Microsoft.Network/applicationGateways/agw/backendAddressPools/pool-a"
+	appGateway := armnetwork.ApplicationGateway{
+		Properties: &armnetwork.ApplicationGatewayPropertiesFormat{
+			HTTPListeners: []*armnetwork.ApplicationGatewayHTTPListener{
+				{Name: strPtr("listener-https")},
+			},
+			BackendAddressPools: []*armnetwork.ApplicationGatewayBackendAddressPool{
+				{
+					ID:   &backendPoolID,
+					Name: strPtr("pool-a"),
+					Properties: &armnetwork.ApplicationGatewayBackendAddressPoolPropertiesFormat{
+						BackendAddresses: []*armnetwork.ApplicationGatewayBackendAddress{
+							{IPAddress: strPtr("10.0.0.4")},
+							{Fqdn: strPtr("app.internal.example.com")},
+						},
+					},
+				},
+			},
+			RequestRoutingRules: []*armnetwork.ApplicationGatewayRequestRoutingRule{
+				{
+					Name: strPtr("rule-a"),
+					Properties: &armnetwork.ApplicationGatewayRequestRoutingRulePropertiesFormat{
+						BackendAddressPool: &armnetwork.SubResource{ID: &backendPoolID},
+					},
+				},
+				{
+					Name:       strPtr("redirect-rule"),
+					Properties: &armnetwork.ApplicationGatewayRequestRoutingRulePropertiesFormat{},
+				},
+			},
+		},
+	}
+
+	expectedNetworkMap := []string{
+		"[listener-https]",
+		"[pool-a]",
+		"[10.0.0.4,app.internal.example.com]",
+	}
+
+	networkMap := plugin.GenerateNetworkMap(&appGateway)
+	if !reflect.DeepEqual(networkMap, expectedNetworkMap) {
+		t.Errorf("Generating Azure Application Gateway network map failed; expected %v, received %v", expectedNetworkMap, networkMap)
+	}
+}
+
+func TestGetCacheKey(t *testing.T) {
+	var tests = []struct {
+		networkMapping   bool
+		expectedCacheKey string
+	}{
+		{false, "application_gateway/my-subscription"},
+		{true, "application_gateway/my-subscription/network_map"},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("NetworkMapping=%t", td.networkMapping)
+
+		t.Run(testName, func(t *testing.T) {
+			plug := plugin.AzApplicationGatewayPlugin{SubscriptionID: "my-subscription", NetworkMapping: td.networkMapping}
+
+			cacheKey := plug.GetCacheKey()
+			if cacheKey != td.expectedCacheKey {
+				t.Errorf("Generating Application Gateway cache key failed; expected %s, received %s", td.expectedCacheKey, cacheKey)
+			}
+		})
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	azagPlug := azagPlugFactory()
+
+	agResources, _ := azagPlug.GetResources()
+
+	expectedType := "Resource"
+	for _, resource := range agResources {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resources via Azure Application Gateway Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	azagPlug := azagPlugFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+		{"18.161.22.61", "Resource"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "Resource"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
+	}
+
+	var matchingResource generalResource.Resource
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			matchedAG, _ := azagPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedAGType := reflect.TypeOf(*matchedAG)
+
+			if matchedAGType.Name() != td.expectedType {
+				t.Errorf("Azure Application Gateway search failed; expected %s after search, received %s", td.expectedType, matchedAGType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	azagp := azagPlugFactory()
+
+	inventory, _ := azagp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure Application Gateway Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package azure_firewall
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	log "github.com/sirupsen/logrus"
+
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	az_public_ip "github.com/magneticstain/ip-2-cloudresource/azure/public_ip"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+type AzFirewallPlugin struct {
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	NetworkMapping bool
+	SubscriptionID string
+}
+
+func derefStrSlice(strPtrs []*string) []string {
+	var strs []string
+
+	for _, strPtr := range strPtrs {
+		if strPtr != nil {
+			strs = append(strs, *strPtr)
+		}
+	}
+
+	return strs
+}
+
+func FormatDNATRuleTranslation(ruleName string, destAddrs, destPorts []string, translatedAddr, translatedPort string) string {
+	// EX: allow-https: [20.1.2.3]:[443] => 10.0.0.4:8443
+	return fmt.Sprintf(
+		"%s: %s:%s => %s:%s",
+		ruleName,
+		utils.FormatStrSliceAsCSV(destAddrs),
+		utils.FormatStrSliceAsCSV(destPorts),
+		translatedAddr,
+		translatedPort,
+	)
+}
+
+func GetClassicDNATRuleTranslations(natRuleCollections []*armnetwork.AzureFirewallNatRuleCollection, tgtIP string) []string {
+	var translations []string
+
+	for _, natRuleCollection := range natRuleCollections {
+		if natRuleCollection.Properties == nil {
+			continue
+		}
+
+		for _, natRule := range natRuleCollection.Properties.Rules {
+			destAddrs := derefStrSlice(natRule.DestinationAddresses)
+			if !slices.Contains(destAddrs, tgtIP) {
+				continue
+			}
+
+			// translated FQDNs take the place of the translated address when set
+			var translatedAddr, translatedPort string
+			if natRule.TranslatedAddress != nil {
+				translatedAddr = *natRule.TranslatedAddress
+			} else if natRule.TranslatedFqdn != nil {
+				translatedAddr = *natRule.TranslatedFqdn
+			}
+			if natRule.TranslatedPort != nil {
+				translatedPort = *natRule.TranslatedPort
+			}
+
+			translations = append(translations, FormatDNATRuleTranslation(*natRule.Name, destAddrs, derefStrSlice(natRule.DestinationPorts), translatedAddr, translatedPort))
+		}
+	}
+
+	return translations
+}
+
+func GetPolicyDNATRuleTranslations(ruleCollectionGroup *armnetwork.FirewallPolicyRuleCollectionGroup, tgtIP string) []string {
+	var translations []string
+
+	if ruleCollectionGroup.Properties == nil {
+		return translations
+	}
+
+	for _, ruleCollection := range ruleCollectionGroup.Properties.RuleCollections {
+		natRuleCollection, isNatCollection := ruleCollection.(*armnetwork.FirewallPolicyNatRuleCollection)
+		if !isNatCollection {
+			continue
+		}
+
+		for _, rule := range natRuleCollection.Rules {
+			natRule, isNatRule := rule.(*armnetwork.NatRule)
+			if !isNatRule {
+				continue
+			}
+
+			destAddrs := derefStrSlice(natRule.DestinationAddresses)
+			if !slices.Contains(destAddrs, tgtIP) {
+				continue
+			}
+
+			var translatedAddr, translatedPort string
+			if natRule.TranslatedAddress != nil {
+				translatedAddr = *natRule.TranslatedAddress
+			} else if natRule.TranslatedFqdn != nil {
+				translatedAddr = *natRule.TranslatedFqdn
+			}
+			if natRule.TranslatedPort != nil {
+				translatedPort = *natRule.TranslatedPort
+			}
+
+			translations = append(translations, FormatDNATRuleTranslation(*natRule.Name, destAddrs, derefStrSlice(natRule.DestinationPorts), translatedAddr, translatedPort))
+		}
+	}
+
+	return translations
+}
+
+func (azfwp *AzFirewallPlugin) GetDNATRuleTranslations(firewallID, tgtIP string) ([]string, error) {
+	var translations []string
+
+	// DNAT rules can either be defined directly on the firewall (classic rules) or in an attached firewall policy, so we need to check both
+	parsedFirewallID, err := arm.ParseResourceID(firewallID)
+	if err != nil {
+		return translations, err
+	}
+
+	ctx := context.Background()
+
+	fwClient, err := armnetwork.NewAzureFirewallsClient(azfwp.SubscriptionID, azfwp.AzureConn.Credential, azfwp.AzureConn.ClientOptions)
+	if err != nil {
+		return translations, err
+	}
+
+	firewall, err := fwClient.Get(ctx, parsedFirewallID.ResourceGroupName, parsedFirewallID.Name, nil)
+	if err != nil {
+		return translations, err
+	}
+
+	translations = append(translations, GetClassicDNATRuleTranslations(firewall.Properties.NatRuleCollections, tgtIP)...)
+
+	if firewall.Properties.FirewallPolicy != nil && firewall.Properties.FirewallPolicy.ID != nil {
+		parsedPolicyID, err := arm.ParseResourceID(*firewall.Properties.FirewallPolicy.ID)
+		if err != nil {
+			return translations, err
+		}
+
+		log.Debug("fetching DNAT rules from firewall policy [ ", parsedPolicyID.Name, " ]")
+
+		// policies can live in a different subscription than the firewall itself
+		rcgClient, err := armnetwork.NewFirewallPolicyRuleCollectionGroupsClient(parsedPolicyID.SubscriptionID, azfwp.AzureConn.Credential, azfwp.AzureConn.ClientOptions)
+		if err != nil {
+			return translations, err
+		}
+
+		rcgPager := rcgClient.NewListPager(parsedPolicyID.ResourceGroupName, parsedPolicyID.Name, nil)
+		for rcgPager.More() {
+			nextRcgSet, err := rcgPager.NextPage(ctx)
+			if err != nil {
+				return translations, err
+			}
+
+			for _, ruleCollectionGroup := range nextRcgSet.Value {
+				translations = append(translations, GetPolicyDNATRuleTranslations(ruleCollectionGroup, tgtIP)...)
+			}
+		}
+	}
+
+	return translations, nil
+}
+
+func (azfwp *AzFirewallPlugin) GatherPublicIPAddrData(firewall *armnetwork.AzureFirewall, ctx context.Context) ([]string, []string, error) {
+	var publicIPv4Addrs, publicIPv6Addrs []string
+
+	ipConfigs := firewall.Properties.IPConfigurations
+	if firewall.Properties.ManagementIPConfiguration != nil {
+		ipConfigs = append(ipConfigs, firewall.Properties.ManagementIPConfiguration)
+	}
+
+	for _, ipConfig := range ipConfigs {
+		if ipConfig.Properties == nil || ipConfig.Properties.PublicIPAddress == nil {
+			continue
+		}
+
+		pubIPAddrData := armnetwork.PublicIPAddress{ID: ipConfig.Properties.PublicIPAddress.ID}
+		publicIP, err := az_public_ip.GetPublicIPAddressProperties(azfwp.AzureConn, &pubIPAddrData, ctx)
+		if err != nil {
+			return publicIPv4Addrs, publicIPv6Addrs, err
+		}
+
+		if publicIP.Properties.IPAddress == nil {
+			continue
+		}
+
+		if publicIP.Properties.PublicIPAddressVersion != nil && *publicIP.Properties.PublicIPAddressVersion == armnetwork.IPVersionIPv6 {
+			publicIPv6Addrs = append(publicIPv6Addrs, *publicIP.Properties.IPAddress)
+		} else {
+			publicIPv4Addrs = append(publicIPv4Addrs, *publicIP.Properties.IPAddress)
+		}
+	}
+
+	// firewalls deployed into a Virtual WAN hub don't use IP configs; their public IPs are listed directly instead
+	if firewall.Properties.HubIPAddresses != nil && firewall.Properties.HubIPAddresses.PublicIPs != nil {
+		for _, hubPublicIP := range firewall.Properties.HubIPAddresses.PublicIPs.Addresses {
+			if hubPublicIP.Address == nil {
+				continue
+			}
+
+			if strings.Contains(*hubPublicIP.Address, ":") {
+				publicIPv6Addrs = append(publicIPv6Addrs, *hubPublicIP.Address)
+			} else {
+				publicIPv4Addrs = append(publicIPv4Addrs, *hubPublicIP.Address)
+			}
+		}
+	}
+
+	return publicIPv4Addrs, publicIPv6Addrs, nil
+}
+
+func (azfwp *AzFirewallPlugin) GetResources() ([]generalResource.Resource, error) {
+	var fwResources []generalResource.Resource
+	var currentResource generalResource.Resource
+	var fwID, fwName *string
+	var fwStatus string
+
+	fwClient, err := armnetwork.NewAzureFirewallsClient(azfwp.SubscriptionID, azfwp.AzureConn.Credential, azfwp.AzureConn.ClientOptions)
+	if err != nil {
+		return fwResources, err
+	}
+
+	ctx := context.Background()
+	fwPager := fwClient.NewListAllPager(nil)
+	for fwPager.More() {
+		nextFwSet, err := fwPager.NextPage(ctx)
+		if err != nil {
+			return fwResources, err
+		}
+		fwSet := nextFwSet.Value
+		log.Debug("found [ ", len(fwSet), " ] Azure firewalls")
+
+		for _, firewall := range fwSet {
+			fwID = firewall.ID
+			fwName = firewall.Name
+			fwStatus = string(*firewall.Properties.ProvisioningState)
+
+			log.Debug("Azure Firewall found - ID: ", *fwID, ", Name: ", *fwName, ", Status: ", fwStatus)
+
+			publicIPv4Addrs, publicIPv6Addrs, err := azfwp.GatherPublicIPAddrData(firewall, ctx)
+			if err != nil {
+				return fwResources, err
+			}
+
+			currentResource = generalResource.Resource{
+				Id:              *fwID,
+				RID:             *fwID,
+				AccountID:       azfwp.SubscriptionID,
+				Name:            *fwName,
+				Status:          fwStatus,
+				CloudSvc:        "azure_firewall",
+				PublicIPv4Addrs: publicIPv4Addrs,
+				PublicIPv6Addrs: publicIPv6Addrs,
+			}
+
+			fwResources = append(
+				fwResources,
+				currentResource,
+			)
+		}
+	}
+
+	return fwResources, nil
+}
+
+func (azfwp AzFirewallPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+	log.Debug("fetching and searching Azure firewall resources")
+
+	fetchedResources, err := inventorycache.Fetch(azfwp.InvCache, "azure_firewall/"+azfwp.SubscriptionID, azfwp.GetResources)
+	if err != nil {
+		return matchingResource, err
+	}
+
+	for _, fwResource := range fetchedResources {
+		if slices.Contains(fwResource.PublicIPv4Addrs, tgtIP) || slices.Contains(fwResource.PublicIPv6Addrs, tgtIP) {
+			matchingResource = &fwResource
+
+			if azfwp.NetworkMapping {
+				// DNAT rules are the only way traffic sent to the firewall's public IP reaches anything behind it
+				dnatTranslations, err := azfwp.GetDNATRuleTranslations(fwResource.Id, tgtIP)
+				if err != nil {
+					return matchingResource, err
+				}
+
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, dnatTranslations...)
+			}
+
+			log.Debug("IP found as Azure Firewall -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+
+			break
+		}
+	}
+
+	return matchingResource, nil
+}
+
+func (azfwp AzFirewallPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(azfwp.InvCache, "azure_firewall/"+azfwp.SubscriptionID, azfwp.GetResources)
+}
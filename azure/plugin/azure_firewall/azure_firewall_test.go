@@ -0,0 +1,155 @@
+package azure_firewall_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/azure_firewall"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func azfwPlugFactory() plugin.AzFirewallPlugin {
+	azfwPlug := plugin.AzFirewallPlugin{}
+
+	return azfwPlug
+}
+
+func strPtr(str string) *string {
+	return &str
+}
+
+func TestGetClassicDNATRuleTranslations(t *testing.T) {
+	natRuleCollections := []*armnetwork.AzureFirewallNatRuleCollection{
+		{
+			Name: strPtr("dnat-collection"),
+			Properties: &armnetwork.AzureFirewallNatRuleCollectionProperties{
+				Rules: []*armnetwork.AzureFirewallNatRule{
+					{
+						Name:                 strPtr("allow-https"),
+						DestinationAddresses: []*string{strPtr("20.1.2.3")},
+						DestinationPorts:     []*string{strPtr("443")},
+						TranslatedAddress:    strPtr("10.0.0.4"),
+						TranslatedPort:       strPtr("8443"),
+					},
+					{
+						Name:                 strPtr("allow-ssh"),
+						DestinationAddresses: []*string{strPtr("20.1.2.4")},
+						DestinationPorts:     []*string{strPtr("22")},
+						TranslatedAddress:    strPtr("10.0.0.5"),
+						TranslatedPort:       strPtr("22"),
+					},
+				},
+			},
+		},
+	}
+
+	var tests = []struct {
+		tgtIP                string
+		expectedTranslations []string
+	}{
+		{"20.1.2.3", []string{"allow-https: [20.1.2.3]:[443] => 10.0.0.4:8443"}},
+		{"20.1.2.4", []string{"allow-ssh: [20.1.2.4]:[22] => 10.0.0.5:22"}},
+		{"1.1.1.1", nil},
+	}
+
+	for _, td := range tests {
+		testName := td.tgtIP
+
+		t.Run(testName, func(t *testing.T) {
+			translations := plugin.GetClassicDNATRuleTranslations(natRuleCollections, td.tgtIP)
+
+			if !reflect.DeepEqual(translations, td.expectedTranslations) {
+				t.Errorf("Fetching Azure Firewall DNAT translations failed; expected %v, received %v", td.expectedTranslations, translations)
+			}
+		})
+	}
+}
+
+func TestGetPolicyDNATRuleTranslations(t *testing.T) {
+	ruleCollectionGroup := armnetwork.FirewallPolicyRuleCollectionGroup{
+		Properties: &armnetwork.FirewallPolicyRuleCollectionGroupProperties{
+			RuleCollections: []armnetwork.FirewallPolicyRuleCollectionClassification{
+				&armnetwork.FirewallPolicyNatRuleCollection{
+					Name: strPtr("dnat-collection"),
+					Rules: []armnetwork.FirewallPolicyRuleClassification{
+						&armnetwork.NatRule{
+							Name:                 strPtr("allow-web"),
+							DestinationAddresses: []*string{strPtr("20.1.2.3")},
+							DestinationPorts:     []*string{strPtr("80")},
+							TranslatedFqdn:       strPtr("web.internal.example.com"),
+							TranslatedPort:       strPtr("8080"),
+						},
+					},
+				},
+				&armnetwork.FirewallPolicyFilterRuleCollection{
+					Name: strPtr("network-collection"),
+				},
+			},
+		},
+	}
+
+	expectedTranslations := []string{"allow-web: [20.1.2.3]:[80] => web.internal.example.com:8080"}
+
+	translations := plugin.GetPolicyDNATRuleTranslations(&ruleCollectionGroup, "20.1.2.3")
+	if !reflect.DeepEqual(translations, expectedTranslations) {
+		t.Errorf("Fetching Azure Firewall Policy DNAT translations failed; expected %v, received %v", expectedTranslations, translations)
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	azfwPlug := azfwPlugFactory()
+
+	fwResources, _ := azfwPlug.GetResources()
+
+	expectedType := "Resource"
+	for _, resource := range fwResources {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resources via Azure Firewall Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	azfwPlug := azfwPlugFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+		{"18.161.22.61", "Resource"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "Resource"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
+	}
+
+	var matchingResource generalResource.Resource
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			matchedFirewall, _ := azfwPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedFirewallType := reflect.TypeOf(*matchedFirewall)
+
+			if matchedFirewallType.Name() != td.expectedType {
+				t.Errorf("Azure Firewall search failed; expected %s after search, received %s", td.expectedType, matchedFirewallType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	azfwp := azfwPlugFactory()
+
+	inventory, _ := azfwp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure Firewall Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
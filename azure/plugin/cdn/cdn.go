@@ -2,21 +2,128 @@ package cdn
 
 import (
 	"context"
+	"slices"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cdn/armcdn"
 	log "github.com/sirupsen/logrus"
 
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type AzCDNPlugin struct {
-	AzureConn      azidentity.DefaultAzureCredential
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	NetworkMapping bool
 	SubscriptionID string
 }
 
+func GenerateNetworkMap(cdnEndpoint *armcdn.AFDEndpoint, routes []*armcdn.Route, origins []*armcdn.AFDOrigin) ([]string, error) {
+	var networkMap, routeNames, originGroupNames, originHostNames []string
+
+	for _, route := range routes {
+		routeNames = append(routeNames, *route.Name)
+
+		if route.Properties == nil || route.Properties.OriginGroup == nil || route.Properties.OriginGroup.ID == nil {
+			continue
+		}
+
+		parsedOriginGroupID, err := arm.ParseResourceID(*route.Properties.OriginGroup.ID)
+		if err != nil {
+			return networkMap, err
+		}
+
+		if !slices.Contains(originGroupNames, parsedOriginGroupID.Name) {
+			originGroupNames = append(originGroupNames, parsedOriginGroupID.Name)
+		}
+	}
+
+	for _, origin := range origins {
+		if origin.Properties != nil && origin.Properties.HostName != nil {
+			originHostNames = append(originHostNames, *origin.Properties.HostName)
+		}
+	}
+
+	networkMap = append(
+		networkMap,
+		*cdnEndpoint.Properties.HostName,
+		utils.FormatStrSliceAsCSV(routeNames),
+		utils.FormatStrSliceAsCSV(originGroupNames),
+		utils.FormatStrSliceAsCSV(originHostNames),
+	)
+
+	return networkMap, nil
+}
+
+func (azcdnp *AzCDNPlugin) MapNetworkPath(cdnEndpointID string) ([]string, error) {
+	var networkMap []string
+	var routes []*armcdn.Route
+	var origins []*armcdn.AFDOrigin
+
+	ctx := context.Background()
+
+	// EX: /subscriptions/<sub>/resourceGroups/<rg>/providers/Microsoft.Cdn/profiles/<profile>/afdEndpoints/<endpoint>
+	parsedEndpointID, err := arm.ParseResourceID(cdnEndpointID)
+	if err != nil {
+		return networkMap, err
+	}
+	resourceGroupName := parsedEndpointID.ResourceGroupName
+	profileName := parsedEndpointID.Parent.Name
+
+	afdClientFactory, err := armcdn.NewClientFactory(azcdnp.SubscriptionID, azcdnp.AzureConn.Credential, azcdnp.AzureConn.ClientOptions)
+	if err != nil {
+		return networkMap, err
+	}
+
+	cdnEndpoint, err := afdClientFactory.NewAFDEndpointsClient().Get(ctx, resourceGroupName, profileName, parsedEndpointID.Name, nil)
+	if err != nil {
+		return networkMap, err
+	}
+
+	routePager := afdClientFactory.NewRoutesClient().NewListByEndpointPager(resourceGroupName, profileName, parsedEndpointID.Name, nil)
+	for routePager.More() {
+		nextRouteSet, err := routePager.NextPage(ctx)
+		if err != nil {
+			return networkMap, err
+		}
+
+		routes = append(routes, nextRouteSet.Value...)
+	}
+
+	// multiple routes can share an origin group, so we only want to fetch the origins for each group once
+	var originGroupNames []string
+	for _, route := range routes {
+		if route.Properties == nil || route.Properties.OriginGroup == nil || route.Properties.OriginGroup.ID == nil {
+			continue
+		}
+
+		parsedOriginGroupID, err := arm.ParseResourceID(*route.Properties.OriginGroup.ID)
+		if err != nil {
+			return networkMap, err
+		}
+
+		if slices.Contains(originGroupNames, parsedOriginGroupID.Name) {
+			continue
+		}
+		originGroupNames = append(originGroupNames, parsedOriginGroupID.Name)
+
+		originPager := afdClientFactory.NewAFDOriginsClient().NewListByOriginGroupPager(resourceGroupName, profileName, parsedOriginGroupID.Name, nil)
+		for originPager.More() {
+			nextOriginSet, err := originPager.NextPage(ctx)
+			if err != nil {
+				return networkMap, err
+			}
+
+			origins = append(origins, nextOriginSet.Value...)
+		}
+	}
+
+	return GenerateNetworkMap(&cdnEndpoint.AFDEndpoint, routes, origins)
+}
+
 func (azcdnp *AzCDNPlugin) ProceesCdnEndpointSet(cdnEndpointSet []*armcdn.AFDEndpoint) ([]generalResource.Resource, error) {
 	var cdnResources []generalResource.Resource
 	var currentResource generalResource.Resource
@@ -60,6 +167,7 @@ func (azcdnp *AzCDNPlugin) ProceesCdnEndpointSet(cdnEndpointSet []*armcdn.AFDEnd
 			CloudSvc:        "cdn",
 			PublicIPv4Addrs: publicIPv4Addrs,
 			PublicIPv6Addrs: publicIPv6Addrs,
+			FQDNs:           []string{utils.NormalizeFQDN(*cdnFQDN)},
 		}
 
 		cdnResources = append(
@@ -74,7 +182,7 @@ func (azcdnp *AzCDNPlugin) ProceesCdnEndpointSet(cdnEndpointSet []*armcdn.AFDEnd
 func (azcdnp *AzCDNPlugin) GetResources() ([]generalResource.Resource, error) {
 	var cdnResources []generalResource.Resource
 
-	afdClientFactory, err := armcdn.NewClientFactory(azcdnp.SubscriptionID, &azcdnp.AzureConn, nil)
+	afdClientFactory, err := armcdn.NewClientFactory(azcdnp.SubscriptionID, azcdnp.AzureConn.Credential, azcdnp.AzureConn.ClientOptions)
 	if err != nil {
 		return cdnResources, err
 	}
@@ -127,22 +235,33 @@ func (azcdnp *AzCDNPlugin) GetResources() ([]generalResource.Resource, error) {
 func (azcdnp AzCDNPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
 	log.Debug("fetching and searching Azure Front Door CDN resources")
 
-	fetchedResources, err := azcdnp.GetResources()
+	fetchedResources, err := inventorycache.Fetch(azcdnp.InvCache, "cdn/"+azcdnp.SubscriptionID, azcdnp.GetResources)
 	if err != nil {
 		return matchingResource, err
 	}
 
 	for _, cdnResource := range fetchedResources {
-		for _, ipAddr := range cdnResource.PublicIPv4Addrs {
-			if ipAddr == tgtIP {
-				matchingResource = &cdnResource
+		if slices.Contains(cdnResource.PublicIPv4Addrs, tgtIP) || slices.Contains(cdnResource.PublicIPv6Addrs, tgtIP) {
+			matchingResource = &cdnResource
 
-				log.Debug("IP found as Front Door CDN Endpoint -> ", matchingResource.RID)
+			if azcdnp.NetworkMapping {
+				networkMap, err := azcdnp.MapNetworkPath(cdnResource.Id)
+				if err != nil {
+					return matchingResource, err
+				}
 
-				break
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, networkMap...)
 			}
+
+			log.Debug("IP found as Front Door CDN Endpoint -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+
+			break
 		}
 	}
 
 	return matchingResource, nil
 }
+
+func (azcdnp AzCDNPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(azcdnp.InvCache, "cdn/"+azcdnp.SubscriptionID, azcdnp.GetResources)
+}
@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cdn/armcdn"
+
 	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/cdn"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
@@ -14,6 +16,43 @@ func azcdnPlugFactory() plugin.AzCDNPlugin {
 	return azcdnPlug
 }
 
+func strPtr(str string) *string {
+	return &str
+}
+
+func TestGenerateNetworkMap(t *testing.T) {
+	profileID := "/subscriptions/x/resourceGroups/rg/providers/Microsoft.Cdn/profiles/my-afd"
+
+	cdnEndpoint := armcdn.AFDEndpoint{
+		Properties: &armcdn.AFDEndpointProperties{HostName: strPtr("my-endpoint-abc123.z01.azurefd.net")},
+	}
+	routes := []*armcdn.Route{
+		{
+			Name:       strPtr("default-route"),
+			Properties: &armcdn.RouteProperties{OriginGroup: &armcdn.ResourceReference{ID: strPtr(profileID + "/originGroups/web-origins")}},
+		},
+		{
+			Name:       strPtr("static-route"),
+			Properties: &armcdn.RouteProperties{OriginGroup: &armcdn.ResourceReference{ID: strPtr(profileID + "/originGroups/web-origins")}},
+		},
+	}
+	origins := []*armcdn.AFDOrigin{
+		{Properties: &armcdn.AFDOriginProperties{HostName: strPtr("web1.example.com")}},
+		{Properties: &armcdn.AFDOriginProperties{HostName: strPtr("web2.example.com")}},
+	}
+
+	expectedNetworkMap := []string{"my-endpoint-abc123.z01.azurefd.net", "[default-route,static-route]", "[web-origins]", "[web1.example.com,web2.example.com]"}
+
+	networkMap, err := plugin.GenerateNetworkMap(&cdnEndpoint, routes, origins)
+	if err != nil {
+		t.Errorf("Generating Azure Front Door network map failed; received error: %s", err)
+	}
+
+	if !reflect.DeepEqual(networkMap, expectedNetworkMap) {
+		t.Errorf("Generating Azure Front Door network map failed; expected %v, received %v", expectedNetworkMap, networkMap)
+	}
+}
+
 func TestGetResources(t *testing.T) {
 	azcdnPlug := azcdnPlugFactory()
 
@@ -55,3 +94,17 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceInventory(t *testing.T) {
+	azcdnp := azcdnPlugFactory()
+
+	inventory, _ := azcdnp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure CDN Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
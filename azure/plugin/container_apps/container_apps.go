@@ -0,0 +1,194 @@
+package container_apps
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v2"
+	log "github.com/sirupsen/logrus"
+
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type AzContainerAppsPlugin struct {
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	SubscriptionID string
+}
+
+func sortIPAddrsByVersion(resource *generalResource.Resource, ipAddrs []string) {
+	for _, ipAddr := range ipAddrs {
+		if strings.Contains(ipAddr, ":") {
+			resource.PublicIPv6Addrs = append(resource.PublicIPv6Addrs, ipAddr)
+		} else {
+			resource.PublicIPv4Addrs = append(resource.PublicIPv4Addrs, ipAddr)
+		}
+	}
+}
+
+func (azcap *AzContainerAppsPlugin) ProcessEnvironment(env *armappcontainers.ManagedEnvironment) ContainerAppsResource {
+	var envStatus string
+	if env.Properties.ProvisioningState != nil {
+		envStatus = string(*env.Properties.ProvisioningState)
+	}
+
+	log.Debug("Azure Container Apps environment found - ID: ", *env.ID, ", Name: ", *env.Name, ", Status: ", envStatus)
+
+	var inboundIPAddrs []string
+	if env.Properties.StaticIP != nil && *env.Properties.StaticIP != "" {
+		inboundIPAddrs = append(inboundIPAddrs, *env.Properties.StaticIP)
+	}
+
+	currentResource := ContainerAppsResource{
+		Resource: generalResource.Resource{
+			Id:        *env.ID,
+			RID:       *env.ID,
+			AccountID: azcap.SubscriptionID,
+			Name:      *env.Name,
+			Status:    envStatus,
+			CloudSvc:  "container_apps",
+		},
+		InboundIPAddrs: inboundIPAddrs,
+	}
+	sortIPAddrsByVersion(&currentResource.Resource, inboundIPAddrs)
+
+	return currentResource
+}
+
+func (azcap *AzContainerAppsPlugin) ProcessApp(app *armappcontainers.ContainerApp) ContainerAppsResource {
+	var appStatus string
+	if app.Properties.ProvisioningState != nil {
+		appStatus = string(*app.Properties.ProvisioningState)
+	}
+
+	log.Debug("Azure Container App found - ID: ", *app.ID, ", Name: ", *app.Name, ", Status: ", appStatus)
+
+	var outboundIPAddrs []string
+	for _, outboundIPAddr := range app.Properties.OutboundIPAddresses {
+		if outboundIPAddr != nil && *outboundIPAddr != "" {
+			outboundIPAddrs = append(outboundIPAddrs, *outboundIPAddr)
+		}
+	}
+
+	currentResource := ContainerAppsResource{
+		Resource: generalResource.Resource{
+			Id:        *app.ID,
+			RID:       *app.ID,
+			AccountID: azcap.SubscriptionID,
+			Name:      *app.Name,
+			Status:    appStatus,
+			CloudSvc:  "container_apps",
+		},
+		OutboundIPAddrs: outboundIPAddrs,
+	}
+	sortIPAddrsByVersion(&currentResource.Resource, outboundIPAddrs)
+
+	return currentResource
+}
+
+func (azcap *AzContainerAppsPlugin) GetResources() ([]ContainerAppsResource, error) {
+	var caResources []ContainerAppsResource
+
+	envClient, err := armappcontainers.NewManagedEnvironmentsClient(azcap.SubscriptionID, azcap.AzureConn.Credential, azcap.AzureConn.ClientOptions)
+	if err != nil {
+		return caResources, err
+	}
+
+	appClient, err := armappcontainers.NewContainerAppsClient(azcap.SubscriptionID, azcap.AzureConn.Credential, azcap.AzureConn.ClientOptions)
+	if err != nil {
+		return caResources, err
+	}
+
+	// inbound traffic is received by the environment, while outbound traffic is sent by the individual apps within it
+	ctx := context.Background()
+	envPager := envClient.NewListBySubscriptionPager(nil)
+	for envPager.More() {
+		nextEnvSet, err := envPager.NextPage(ctx)
+		if err != nil {
+			return caResources, err
+		}
+		envSet := nextEnvSet.Value
+		log.Debug("found [ ", len(envSet), " ] Azure Container Apps environments")
+
+		for _, env := range envSet {
+			if env.Properties == nil {
+				continue
+			}
+
+			caResources = append(caResources, azcap.ProcessEnvironment(env))
+		}
+	}
+
+	appPager := appClient.NewListBySubscriptionPager(nil)
+	for appPager.More() {
+		nextAppSet, err := appPager.NextPage(ctx)
+		if err != nil {
+			return caResources, err
+		}
+		appSet := nextAppSet.Value
+		log.Debug("found [ ", len(appSet), " ] Azure Container Apps")
+
+		for _, app := range appSet {
+			if app.Properties == nil {
+				continue
+			}
+
+			caResources = append(caResources, azcap.ProcessApp(app))
+		}
+	}
+
+	return caResources, nil
+}
+
+func MatchIPDirection(caResource ContainerAppsResource, tgtIP string) string {
+	var ipDirection string
+
+	if slices.Contains(caResource.InboundIPAddrs, tgtIP) {
+		ipDirection = "inbound"
+	} else if slices.Contains(caResource.OutboundIPAddrs, tgtIP) {
+		ipDirection = "outbound"
+	}
+
+	return ipDirection
+}
+
+func (azcap AzContainerAppsPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+	log.Debug("fetching and searching Azure Container Apps resources")
+
+	fetchedResources, err := inventorycache.Fetch(azcap.InvCache, "container_apps/"+azcap.SubscriptionID, azcap.GetResources)
+	if err != nil {
+		return matchingResource, err
+	}
+
+	for _, caResource := range fetchedResources {
+		ipDirection := MatchIPDirection(caResource, tgtIP)
+		if ipDirection != "" {
+			matchingResource = &caResource.Resource
+			matchingResource.IPType = ipDirection
+
+			log.Debug("IP found as Container Apps ", ipDirection, " IP -> ", matchingResource.RID)
+
+			break
+		}
+	}
+
+	return matchingResource, nil
+}
+
+func (azcap AzContainerAppsPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	var inventory []generalResource.Resource
+
+	fetchedResources, err := inventorycache.Fetch(azcap.InvCache, "container_apps/"+azcap.SubscriptionID, azcap.GetResources)
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, caResource := range fetchedResources {
+		inventory = append(inventory, caResource.Resource)
+	}
+
+	return inventory, nil
+}
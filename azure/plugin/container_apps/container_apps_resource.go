@@ -0,0 +1,10 @@
+package container_apps
+
+import (
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type ContainerAppsResource struct {
+	generalResource.Resource
+	InboundIPAddrs, OutboundIPAddrs []string
+}
@@ -0,0 +1,116 @@
+package container_apps_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v2"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/container_apps"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func azcaPlugFactory() plugin.AzContainerAppsPlugin {
+	azcaPlug := plugin.AzContainerAppsPlugin{}
+
+	return azcaPlug
+}
+
+func strPtr(str string) *string {
+	return &str
+}
+
+func TestMatchIPDirection(t *testing.T) {
+	azcaPlug := azcaPlugFactory()
+
+	envResource := azcaPlug.ProcessEnvironment(&armappcontainers.ManagedEnvironment{
+		ID:         strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/my-env"),
+		Name:       strPtr("my-env"),
+		Properties: &armappcontainers.ManagedEnvironmentProperties{StaticIP: strPtr("20.1.2.3")},
+	})
+	appResource := azcaPlug.ProcessApp(&armappcontainers.ContainerApp{
+		ID:         strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.App/containerApps/my-app"),
+		Name:       strPtr("my-app"),
+		Properties: &armappcontainers.ContainerAppProperties{OutboundIPAddresses: []*string{strPtr("20.1.2.4"), strPtr("20.1.2.5")}},
+	})
+
+	var tests = []struct {
+		caResource        plugin.ContainerAppsResource
+		ipAddr            string
+		expectedDirection string
+	}{
+		{envResource, "20.1.2.3", "inbound"},
+		{envResource, "20.1.2.4", ""},
+		{appResource, "20.1.2.4", "outbound"},
+		{appResource, "20.1.2.5", "outbound"},
+		{appResource, "20.1.2.3", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.caResource.Name + "_" + td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			ipDirection := plugin.MatchIPDirection(td.caResource, td.ipAddr)
+
+			if ipDirection != td.expectedDirection {
+				t.Errorf("Matching Azure Container Apps IP direction failed; IP: %s, expected: %s, received: %s", td.ipAddr, td.expectedDirection, ipDirection)
+			}
+		})
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	azcaPlug := azcaPlugFactory()
+
+	caResources, _ := azcaPlug.GetResources()
+
+	expectedType := "ContainerAppsResource"
+	for _, resource := range caResources {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resources via Azure Container Apps Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	azcaPlug := azcaPlugFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+		{"18.161.22.61", "Resource"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "Resource"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
+	}
+
+	var matchingResource generalResource.Resource
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			matchedResource, _ := azcaPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedResourceType := reflect.TypeOf(*matchedResource)
+
+			if matchedResourceType.Name() != td.expectedType {
+				t.Errorf("Azure Container Apps search failed; expected %s after search, received %s", td.expectedType, matchedResourceType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	azcap := azcaPlugFactory()
+
+	inventory, _ := azcap.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure Container Apps Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
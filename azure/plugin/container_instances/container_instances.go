@@ -0,0 +1,114 @@
+package container_instances
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerinstance/armcontainerinstance/v2"
+	log "github.com/sirupsen/logrus"
+
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type AzContainerInstancesPlugin struct {
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	SubscriptionID string
+}
+
+func (azcip *AzContainerInstancesPlugin) ProcessContainerGroup(containerGroup *armcontainerinstance.ContainerGroup) (generalResource.Resource, bool) {
+	var cgStatus string
+	if containerGroup.Properties.InstanceView != nil && containerGroup.Properties.InstanceView.State != nil {
+		cgStatus = *containerGroup.Properties.InstanceView.State
+	} else if containerGroup.Properties.ProvisioningState != nil {
+		// the instance view isn't always included when listing container groups
+		cgStatus = *containerGroup.Properties.ProvisioningState
+	}
+
+	log.Debug("Azure Container Instances container group found - ID: ", *containerGroup.ID, ", Name: ", *containerGroup.Name, ", Status: ", cgStatus)
+
+	currentResource := generalResource.Resource{
+		Id:        *containerGroup.ID,
+		RID:       *containerGroup.ID,
+		AccountID: azcip.SubscriptionID,
+		Name:      *containerGroup.Name,
+		Status:    cgStatus,
+		CloudSvc:  "container_instances",
+	}
+
+	// container groups deployed into a VNet only have a private IP, which we don't care about here
+	ipAddrProps := containerGroup.Properties.IPAddress
+	if ipAddrProps == nil || ipAddrProps.IP == nil || *ipAddrProps.IP == "" || ipAddrProps.Type == nil || *ipAddrProps.Type != armcontainerinstance.ContainerGroupIPAddressTypePublic {
+		return currentResource, false
+	}
+
+	ipAddr := *ipAddrProps.IP
+	if strings.Contains(ipAddr, ":") {
+		currentResource.PublicIPv6Addrs = append(currentResource.PublicIPv6Addrs, ipAddr)
+	} else {
+		currentResource.PublicIPv4Addrs = append(currentResource.PublicIPv4Addrs, ipAddr)
+	}
+
+	return currentResource, true
+}
+
+func (azcip *AzContainerInstancesPlugin) GetResources() ([]generalResource.Resource, error) {
+	var cgResources []generalResource.Resource
+
+	cgClient, err := armcontainerinstance.NewContainerGroupsClient(azcip.SubscriptionID, azcip.AzureConn.Credential, azcip.AzureConn.ClientOptions)
+	if err != nil {
+		return cgResources, err
+	}
+
+	ctx := context.Background()
+	cgPager := cgClient.NewListPager(nil)
+	for cgPager.More() {
+		nextCgSet, err := cgPager.NextPage(ctx)
+		if err != nil {
+			return cgResources, err
+		}
+		cgSet := nextCgSet.Value
+		log.Debug("found [ ", len(cgSet), " ] Azure Container Instances container groups")
+
+		for _, containerGroup := range cgSet {
+			if containerGroup.Properties == nil {
+				continue
+			}
+
+			cgResource, isPublic := azcip.ProcessContainerGroup(containerGroup)
+			if isPublic {
+				cgResources = append(cgResources, cgResource)
+			}
+		}
+	}
+
+	return cgResources, nil
+}
+
+func (azcip AzContainerInstancesPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+	log.Debug("fetching and searching Azure Container Instances resources")
+
+	fetchedResources, err := inventorycache.Fetch(azcip.InvCache, "container_instances/"+azcip.SubscriptionID, azcip.GetResources)
+	if err != nil {
+		return matchingResource, err
+	}
+
+	for _, cgResource := range fetchedResources {
+		if slices.Contains(cgResource.PublicIPv4Addrs, tgtIP) || slices.Contains(cgResource.PublicIPv6Addrs, tgtIP) {
+			matchingResource = &cgResource
+
+			log.Debug("IP found as Container Instances container group -> ", matchingResource.RID)
+
+			break
+		}
+	}
+
+	return matchingResource, nil
+}
+
+func (azcip AzContainerInstancesPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(azcip.InvCache, "container_instances/"+azcip.SubscriptionID, azcip.GetResources)
+}
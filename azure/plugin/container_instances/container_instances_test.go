@@ -0,0 +1,123 @@
+package container_instances_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerinstance/armcontainerinstance/v2"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/container_instances"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func azciPlugFactory() plugin.AzContainerInstancesPlugin {
+	azciPlug := plugin.AzContainerInstancesPlugin{}
+
+	return azciPlug
+}
+
+func strPtr(str string) *string {
+	return &str
+}
+
+func containerGroupFactory(ipType, ipAddr string) *armcontainerinstance.ContainerGroup {
+	cgIPType := armcontainerinstance.ContainerGroupIPAddressType(ipType)
+
+	return &armcontainerinstance.ContainerGroup{
+		ID:   strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.ContainerInstance/containerGroups/my-cg"),
+		Name: strPtr("my-cg"),
+		Properties: &armcontainerinstance.ContainerGroupPropertiesProperties{
+			ProvisioningState: strPtr("Succeeded"),
+			InstanceView:      &armcontainerinstance.ContainerGroupPropertiesInstanceView{State: strPtr("Running")},
+			IPAddress:         &armcontainerinstance.IPAddress{Type: &cgIPType, IP: &ipAddr},
+		},
+	}
+}
+
+func TestProcessContainerGroup(t *testing.T) {
+	var tests = []struct {
+		ipType, ipAddr   string
+		expectedIsPublic bool
+	}{
+		{"Public", "20.1.2.3", true},
+		{"Private", "10.0.0.4", false},
+		{"Public", "", false},
+	}
+
+	azciPlug := azciPlugFactory()
+	for _, td := range tests {
+		testName := td.ipType + "_" + td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			cgResource, isPublic := azciPlug.ProcessContainerGroup(containerGroupFactory(td.ipType, td.ipAddr))
+
+			if isPublic != td.expectedIsPublic {
+				t.Errorf("Processing Azure container group failed; expected public: %t, received: %t", td.expectedIsPublic, isPublic)
+			}
+
+			if isPublic && (len(cgResource.PublicIPv4Addrs) != 1 || cgResource.PublicIPv4Addrs[0] != td.ipAddr) {
+				t.Errorf("Processing Azure container group failed; expected IP %s, received %v", td.ipAddr, cgResource.PublicIPv4Addrs)
+			}
+
+			if cgResource.Status != "Running" {
+				t.Errorf("Processing Azure container group failed; expected Running status, received %s", cgResource.Status)
+			}
+		})
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	azciPlug := azciPlugFactory()
+
+	cgResources, _ := azciPlug.GetResources()
+
+	expectedType := "Resource"
+	for _, resource := range cgResources {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resources via Azure Container Instances Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	azciPlug := azciPlugFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+		{"18.161.22.61", "Resource"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "Resource"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
+	}
+
+	var matchingResource generalResource.Resource
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			matchedResource, _ := azciPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedResourceType := reflect.TypeOf(*matchedResource)
+
+			if matchedResourceType.Name() != td.expectedType {
+				t.Errorf("Azure Container Instances search failed; expected %s after search, received %s", td.expectedType, matchedResourceType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	azcip := azciPlugFactory()
+
+	inventory, _ := azcip.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure Container Instances Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
@@ -2,27 +2,162 @@ package load_balancer
 
 import (
 	"context"
+	"slices"
+	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 	log "github.com/sirupsen/logrus"
 
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
 	az_public_ip "github.com/magneticstain/ip-2-cloudresource/azure/public_ip"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type AzLoadBalancerPlugin struct {
-	AzureConn      azidentity.DefaultAzureCredential
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	NetworkMapping bool
 	SubscriptionID string
 }
 
+func GetBackendTargetName(backendIpConfigID string) string {
+	// backend IP configs belong to either a standalone NIC or a VMSS instance NIC; we want whichever is more meaningful to the user
+	if vmssPrefix, _, isVmss := strings.Cut(backendIpConfigID, "/virtualMachines/"); isVmss {
+		parsedVmssID, err := arm.ParseResourceID(vmssPrefix)
+		if err == nil {
+			return "vmss: " + parsedVmssID.Name
+		}
+	}
+
+	if nicID, _, isNic := strings.Cut(backendIpConfigID, "/ipConfigurations/"); isNic {
+		parsedNicID, err := arm.ParseResourceID(nicID)
+		if err == nil {
+			return "nic: " + parsedNicID.Name
+		}
+	}
+
+	return backendIpConfigID
+}
+
+func GetBackendTargets(backendPool *armnetwork.BackendAddressPool) []string {
+	var backendTgts []string
+
+	if backendPool.Properties == nil {
+		return backendTgts
+	}
+
+	for _, backendIpConfig := range backendPool.Properties.BackendIPConfigurations {
+		if backendIpConfig.ID == nil {
+			continue
+		}
+
+		backendTgt := GetBackendTargetName(*backendIpConfig.ID)
+		if !slices.Contains(backendTgts, backendTgt) {
+			// VMSS instances will all resolve to the same scale set
+			backendTgts = append(backendTgts, backendTgt)
+		}
+	}
+
+	// IP-based backend pools reference addresses directly instead of NICs
+	for _, backendAddr := range backendPool.Properties.LoadBalancerBackendAddresses {
+		if backendAddr.Properties != nil && backendAddr.Properties.IPAddress != nil {
+			backendTgts = append(backendTgts, *backendAddr.Properties.IPAddress)
+		}
+	}
+
+	return backendTgts
+}
+
+func GenerateNetworkMap(azlb *armnetwork.LoadBalancer, frontendConfig *armnetwork.FrontendIPConfiguration) []string {
+	var networkMap, ruleNames, backendPoolIDs, backendPoolNames, backendTgts []string
+
+	// rules tie frontends to backend pools, so we only want the rules attached to the matching frontend
+	for _, lbRule := range azlb.Properties.LoadBalancingRules {
+		if lbRule.Properties == nil || lbRule.Properties.FrontendIPConfiguration == nil || !strings.EqualFold(*lbRule.Properties.FrontendIPConfiguration.ID, *frontendConfig.ID) {
+			continue
+		}
+
+		ruleNames = append(ruleNames, *lbRule.Name)
+
+		rulePoolRefs := lbRule.Properties.BackendAddressPools
+		if lbRule.Properties.BackendAddressPool != nil {
+			rulePoolRefs = append(rulePoolRefs, lbRule.Properties.BackendAddressPool)
+		}
+		for _, poolRef := range rulePoolRefs {
+			if poolRef.ID != nil && !slices.Contains(backendPoolIDs, strings.ToLower(*poolRef.ID)) {
+				backendPoolIDs = append(backendPoolIDs, strings.ToLower(*poolRef.ID))
+			}
+		}
+	}
+
+	for _, backendPool := range azlb.Properties.BackendAddressPools {
+		if backendPool.ID == nil || !slices.Contains(backendPoolIDs, strings.ToLower(*backendPool.ID)) {
+			continue
+		}
+
+		backendPoolNames = append(backendPoolNames, *backendPool.Name)
+		backendTgts = append(backendTgts, GetBackendTargets(backendPool)...)
+	}
+
+	networkMap = append(
+		networkMap,
+		*frontendConfig.Name,
+		utils.FormatStrSliceAsCSV(ruleNames),
+		utils.FormatStrSliceAsCSV(backendPoolNames),
+		utils.FormatStrSliceAsCSV(backendTgts),
+	)
+
+	return networkMap
+}
+
+func (azlbp *AzLoadBalancerPlugin) MapNetworkPath(lbID, tgtIP string) ([]string, error) {
+	var networkMap []string
+
+	ctx := context.Background()
+
+	parsedLbID, err := arm.ParseResourceID(lbID)
+	if err != nil {
+		return networkMap, err
+	}
+
+	lbClient, err := armnetwork.NewLoadBalancersClient(azlbp.SubscriptionID, azlbp.AzureConn.Credential, azlbp.AzureConn.ClientOptions)
+	if err != nil {
+		return networkMap, err
+	}
+
+	azlb, err := lbClient.Get(ctx, parsedLbID.ResourceGroupName, parsedLbID.Name, nil)
+	if err != nil {
+		return networkMap, err
+	}
+
+	for _, frontendConfig := range azlb.Properties.FrontendIPConfigurations {
+		if frontendConfig.Properties == nil || frontendConfig.Properties.PublicIPAddress == nil {
+			continue
+		}
+
+		publicIP, err := az_public_ip.GetPublicIPAddressProperties(azlbp.AzureConn, frontendConfig.Properties.PublicIPAddress, ctx)
+		if err != nil {
+			return networkMap, err
+		}
+
+		if publicIP.Properties.IPAddress != nil && *publicIP.Properties.IPAddress == tgtIP {
+			return GenerateNetworkMap(&azlb.LoadBalancer, frontendConfig), nil
+		}
+	}
+
+	return networkMap, nil
+}
+
 func (azlbp *AzLoadBalancerPlugin) GetResources() ([]generalResource.Resource, error) {
 	var lbResources []generalResource.Resource
 	var currentResource generalResource.Resource
 	var lbID, lbName *string
 	var lbStatus string
 
-	lbClient, err := armnetwork.NewLoadBalancersClient(azlbp.SubscriptionID, &azlbp.AzureConn, nil)
+	lbClient, err := armnetwork.NewLoadBalancersClient(azlbp.SubscriptionID, azlbp.AzureConn.Credential, azlbp.AzureConn.ClientOptions)
 	if err != nil {
 		return lbResources, err
 	}
@@ -46,13 +181,22 @@ func (azlbp *AzLoadBalancerPlugin) GetResources() ([]generalResource.Resource, e
 
 			var publicIPv4Addrs []string
 			for _, lb_frontend_config := range azlb.Properties.FrontendIPConfigurations {
+				if lb_frontend_config.Properties == nil || lb_frontend_config.Properties.PublicIPAddress == nil {
+					// internal load balancer frontend
+					continue
+				}
 				pubIPAddrData := lb_frontend_config.Properties.PublicIPAddress
 
-				publicIP, err := az_public_ip.GetPublicIPAddressProperties(&azlbp.AzureConn, pubIPAddrData, ctx)
+				publicIP, err := az_public_ip.GetPublicIPAddressProperties(azlbp.AzureConn, pubIPAddrData, ctx)
 				if err != nil {
 					return lbResources, err
 				}
 
+				if publicIP.Properties == nil || publicIP.Properties.IPAddress == nil {
+					log.Debug("public IP [ ", *pubIPAddrData.ID, " ] attached to load balancer [ ", *lbName, " ] has not been allocated an address")
+					continue
+				}
+
 				publicIPv4Addrs = append(publicIPv4Addrs, *publicIP.Properties.IPAddress)
 			}
 
@@ -62,7 +206,7 @@ func (azlbp *AzLoadBalancerPlugin) GetResources() ([]generalResource.Resource, e
 				AccountID:       azlbp.SubscriptionID,
 				Name:            *lbName,
 				Status:          lbStatus,
-				CloudSvc:        "load_balancers",
+				CloudSvc:        "load_balancer",
 				PublicIPv4Addrs: publicIPv4Addrs,
 			}
 
@@ -79,22 +223,33 @@ func (azlbp *AzLoadBalancerPlugin) GetResources() ([]generalResource.Resource, e
 func (azlbp AzLoadBalancerPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
 	log.Debug("fetching and searching Azure load balancer resources")
 
-	fetchedResources, err := azlbp.GetResources()
+	fetchedResources, err := inventorycache.Fetch(azlbp.InvCache, "load_balancer/"+azlbp.SubscriptionID, azlbp.GetResources)
 	if err != nil {
 		return matchingResource, err
 	}
 
 	for _, lbResource := range fetchedResources {
-		for _, ipAddr := range lbResource.PublicIPv4Addrs {
-			if ipAddr == tgtIP {
-				matchingResource = &lbResource
+		if slices.Contains(lbResource.PublicIPv4Addrs, tgtIP) {
+			matchingResource = &lbResource
 
-				log.Debug("IP found as Load Balancer -> ", matchingResource.RID)
+			if azlbp.NetworkMapping {
+				networkMap, err := azlbp.MapNetworkPath(lbResource.Id, tgtIP)
+				if err != nil {
+					return matchingResource, err
+				}
 
-				break
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, networkMap...)
 			}
+
+			log.Debug("IP found as Load Balancer -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+
+			break
 		}
 	}
 
 	return matchingResource, nil
 }
+
+func (azlbp AzLoadBalancerPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(azlbp.InvCache, "load_balancer/"+azlbp.SubscriptionID, azlbp.GetResources)
+}
@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+
 	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/load_balancer"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
@@ -14,6 +16,87 @@ func azlbPlugFactory() plugin.AzLoadBalancerPlugin {
 	return azlbPlug
 }
 
+func strPtr(str string) *string {
+	return &str
+}
+
+func TestGetBackendTargetName(t *testing.T) {
+	var tests = []struct {
+		backendIpConfigID, expectedTgtName string
+	}{
+		{"/subscriptions/x/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/my-vm-nic/ipConfigurations/ipconfig1", "nic: my-vm-nic"},
+		{"/subscriptions/x/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines/0/networkInterfaces/my-vmss-nic/ipConfigurations/ipconfig1", "vmss: my-vmss"},
+		{"not_an_id", "not_an_id"},
+	}
+
+	for _, td := range tests {
+		testName := td.expectedTgtName
+
+		t.Run(testName, func(t *testing.T) {
+			tgtName := plugin.GetBackendTargetName(td.backendIpConfigID)
+
+			if tgtName != td.expectedTgtName {
+				t.Errorf("Fetching load balancer backend target name failed; expected %s, received %s", td.expectedTgtName, tgtName)
+			}
+		})
+	}
+}
+
+func TestGenerateNetworkMap(t *testing.T) {
+	lbID := "/subscriptions/x/resourceGroups/rg/providers/Microsoft.Network/loadBalancers/my-lb"
+	frontendConfig := armnetwork.FrontendIPConfiguration{
+		ID:   strPtr(lbID + "/frontendIPConfigurations/public-fe"),
+		Name: strPtr("public-fe"),
+	}
+
+	azlb := armnetwork.LoadBalancer{
+		ID: &lbID,
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{&frontendConfig},
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{
+				{
+					Name: strPtr("https"),
+					Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+						FrontendIPConfiguration: &armnetwork.SubResource{ID: frontendConfig.ID},
+						BackendAddressPool:      &armnetwork.SubResource{ID: strPtr(lbID + "/backendAddressPools/web-pool")},
+					},
+				},
+				{
+					Name: strPtr("internal-only"),
+					Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+						FrontendIPConfiguration: &armnetwork.SubResource{ID: strPtr(lbID + "/frontendIPConfigurations/private-fe")},
+						BackendAddressPool:      &armnetwork.SubResource{ID: strPtr(lbID + "/backendAddressPools/internal-pool")},
+					},
+				},
+			},
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
+				{
+					ID:   strPtr(lbID + "/backendAddressPools/web-pool"),
+					Name: strPtr("web-pool"),
+					Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+						BackendIPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+							{ID: strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/web-vmss/virtualMachines/0/networkInterfaces/nic/ipConfigurations/ipconfig1")},
+							{ID: strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/web-vmss/virtualMachines/1/networkInterfaces/nic/ipConfigurations/ipconfig1")},
+							{ID: strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/web-vm-nic/ipConfigurations/ipconfig1")},
+						},
+					},
+				},
+				{
+					ID:   strPtr(lbID + "/backendAddressPools/internal-pool"),
+					Name: strPtr("internal-pool"),
+				},
+			},
+		},
+	}
+
+	expectedNetworkMap := []string{"public-fe", "[https]", "[web-pool]", "[vmss: web-vmss,nic: web-vm-nic]"}
+
+	networkMap := plugin.GenerateNetworkMap(&azlb, &frontendConfig)
+	if !reflect.DeepEqual(networkMap, expectedNetworkMap) {
+		t.Errorf("Generating Azure load balancer network map failed; expected %v, received %v", expectedNetworkMap, networkMap)
+	}
+}
+
 func TestGetResources(t *testing.T) {
 	azlbPlug := azlbPlugFactory()
 
@@ -55,3 +138,17 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceInventory(t *testing.T) {
+	azlbp := azlbPlugFactory()
+
+	inventory, _ := azlbp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure Load Balancer Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
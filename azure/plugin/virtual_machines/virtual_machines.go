@@ -2,19 +2,24 @@ package virtual_machines
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 	log "github.com/sirupsen/logrus"
 
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
 	az_public_ip "github.com/magneticstain/ip-2-cloudresource/azure/public_ip"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 type AzVirtualMachinePlugin struct {
-	AzureConn      azidentity.DefaultAzureCredential
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	NetworkMapping bool
 	SubscriptionID string
 }
 
@@ -39,12 +44,136 @@ func GetVMStatus(vmClient *armcompute.VirtualMachinesClient, vm *armcompute.Virt
 	return vmStatus, nil
 }
 
+func GetNSGName(nsg *armnetwork.SecurityGroup) string {
+	if nsg == nil || nsg.ID == nil {
+		return ""
+	}
+
+	parsedNsgID, err := arm.ParseResourceID(*nsg.ID)
+	if err != nil {
+		return *nsg.ID
+	}
+
+	return parsedNsgID.Name
+}
+
+func FormatNetworkMapElmnt(resourceName, nsgName string) string {
+	if nsgName == "" {
+		return resourceName
+	}
+
+	// EX: my-vm-nic (nsg: my-vm-nsg)
+	return fmt.Sprintf("%s (nsg: %s)", resourceName, nsgName)
+}
+
+func GenerateNetworkMap(publicIPName string, nic *armnetwork.Interface, subnet *armnetwork.Subnet) ([]string, error) {
+	var networkMap []string
+
+	parsedSubnetID, err := arm.ParseResourceID(*subnet.ID)
+	if err != nil {
+		return networkMap, err
+	}
+
+	var nicNsg, subnetNsg *armnetwork.SecurityGroup
+	if nic.Properties != nil {
+		nicNsg = nic.Properties.NetworkSecurityGroup
+	}
+	if subnet.Properties != nil {
+		subnetNsg = subnet.Properties.NetworkSecurityGroup
+	}
+
+	// NSGs can be applied at both the NIC and subnet level, so we include them with each hop
+	networkMap = append(
+		networkMap,
+		publicIPName,
+		FormatNetworkMapElmnt(*nic.Name, GetNSGName(nicNsg)),
+		FormatNetworkMapElmnt(parsedSubnetID.Name, GetNSGName(subnetNsg)),
+		parsedSubnetID.Parent.Name,
+	)
+
+	return networkMap, nil
+}
+
+func (azvmp *AzVirtualMachinePlugin) MapNetworkPath(vmID, tgtIP string) ([]string, error) {
+	var networkMap []string
+
+	ctx := context.Background()
+
+	parsedVmID, err := arm.ParseResourceID(vmID)
+	if err != nil {
+		return networkMap, err
+	}
+
+	vmClient, err := armcompute.NewVirtualMachinesClient(azvmp.SubscriptionID, azvmp.AzureConn.Credential, azvmp.AzureConn.ClientOptions)
+	if err != nil {
+		return networkMap, err
+	}
+
+	vm, err := vmClient.Get(ctx, parsedVmID.ResourceGroupName, parsedVmID.Name, nil)
+	if err != nil {
+		return networkMap, err
+	}
+
+	nicClient, err := armnetwork.NewInterfacesClient(azvmp.SubscriptionID, azvmp.AzureConn.Credential, azvmp.AzureConn.ClientOptions)
+	if err != nil {
+		return networkMap, err
+	}
+
+	// we need to find the specific NIC IP config that the target IP is attached to, then follow it back to its VNet
+	for _, nicRef := range vm.Properties.NetworkProfile.NetworkInterfaces {
+		parsedNicID, err := arm.ParseResourceID(*nicRef.ID)
+		if err != nil {
+			return networkMap, err
+		}
+
+		nicData, err := nicClient.Get(ctx, parsedNicID.ResourceGroupName, parsedNicID.Name, nil)
+		if err != nil {
+			return networkMap, err
+		}
+
+		for _, nicIpAddrConfig := range nicData.Properties.IPConfigurations {
+			if nicIpAddrConfig.Properties.PublicIPAddress == nil || nicIpAddrConfig.Properties.Subnet == nil {
+				continue
+			}
+
+			publicIpProps, err := az_public_ip.GetPublicIPAddressProperties(azvmp.AzureConn, nicIpAddrConfig.Properties.PublicIPAddress, ctx)
+			if err != nil {
+				return networkMap, err
+			}
+
+			if publicIpProps.Properties.IPAddress == nil || *publicIpProps.Properties.IPAddress != tgtIP {
+				continue
+			}
+
+			parsedSubnetID, err := arm.ParseResourceID(*nicIpAddrConfig.Properties.Subnet.ID)
+			if err != nil {
+				return networkMap, err
+			}
+
+			// VNets can live in a different subscription than the VM when they're peered or shared
+			subnetClient, err := armnetwork.NewSubnetsClient(parsedSubnetID.SubscriptionID, azvmp.AzureConn.Credential, azvmp.AzureConn.ClientOptions)
+			if err != nil {
+				return networkMap, err
+			}
+
+			subnetData, err := subnetClient.Get(ctx, parsedSubnetID.ResourceGroupName, parsedSubnetID.Parent.Name, parsedSubnetID.Name, nil)
+			if err != nil {
+				return networkMap, err
+			}
+
+			return GenerateNetworkMap(*publicIpProps.Name, &nicData.Interface, &subnetData.Subnet)
+		}
+	}
+
+	return networkMap, nil
+}
+
 func (azvmp *AzVirtualMachinePlugin) GatherPublicIPAddrData(vmInstance *armcompute.VirtualMachine, IpVer armnetwork.IPVersion, ctx context.Context) ([]string, error) {
 	var publicIPAddrs []string
 	var publicIpVer *armnetwork.IPVersion
 	var ipAddr *string
 
-	nicClient, err := armnetwork.NewInterfacesClient(azvmp.SubscriptionID, &azvmp.AzureConn, nil)
+	nicClient, err := armnetwork.NewInterfacesClient(azvmp.SubscriptionID, azvmp.AzureConn.Credential, azvmp.AzureConn.ClientOptions)
 	if err != nil {
 		return publicIPAddrs, err
 	}
@@ -77,7 +206,7 @@ func (azvmp *AzVirtualMachinePlugin) GatherPublicIPAddrData(vmInstance *armcompu
 
 					log.Debug("public IP address properties not included with ", *vmInstance.ID, " / ", *vmInstance.Name, " public IP address data (this is common) - fetching it more directly from Azure...")
 
-					publicIpProps, err := az_public_ip.GetPublicIPAddressProperties(&azvmp.AzureConn, publicIpData, ctx)
+					publicIpProps, err := az_public_ip.GetPublicIPAddressProperties(azvmp.AzureConn, publicIpData, ctx)
 					if err != nil {
 						return publicIPAddrs, err
 					}
@@ -103,7 +232,7 @@ func (azvmp *AzVirtualMachinePlugin) GetResources() ([]generalResource.Resource,
 	var currentResource generalResource.Resource
 	var vmID, vmName *string
 
-	vmClient, err := armcompute.NewVirtualMachinesClient(azvmp.SubscriptionID, &azvmp.AzureConn, nil)
+	vmClient, err := armcompute.NewVirtualMachinesClient(azvmp.SubscriptionID, azvmp.AzureConn.Credential, azvmp.AzureConn.ClientOptions)
 	if err != nil {
 		return vmResources, err
 	}
@@ -164,22 +293,33 @@ func (azvmp *AzVirtualMachinePlugin) GetResources() ([]generalResource.Resource,
 func (azvmp AzVirtualMachinePlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
 	log.Debug("fetching and searching Azure virtual machine resources")
 
-	fetchedResources, err := azvmp.GetResources()
+	fetchedResources, err := inventorycache.Fetch(azvmp.InvCache, "virtual_machines/"+azvmp.SubscriptionID, azvmp.GetResources)
 	if err != nil {
 		return matchingResource, err
 	}
 
 	for _, vmResource := range fetchedResources {
-		for _, ipAddr := range vmResource.PublicIPv4Addrs {
-			if ipAddr == tgtIP {
-				matchingResource = &vmResource
+		if slices.Contains(vmResource.PublicIPv4Addrs, tgtIP) || slices.Contains(vmResource.PublicIPv6Addrs, tgtIP) {
+			matchingResource = &vmResource
 
-				log.Debug("IP found as Virtual Machine -> ", matchingResource.RID)
+			if azvmp.NetworkMapping {
+				networkMap, err := azvmp.MapNetworkPath(vmResource.Id, tgtIP)
+				if err != nil {
+					return matchingResource, err
+				}
 
-				break
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, networkMap...)
 			}
+
+			log.Debug("IP found as Virtual Machine -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+
+			break
 		}
 	}
 
 	return matchingResource, nil
 }
+
+func (azvmp AzVirtualMachinePlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(azvmp.InvCache, "virtual_machines/"+azvmp.SubscriptionID, azvmp.GetResources)
+}
@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+
 	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/virtual_machines"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
@@ -14,6 +16,61 @@ func azvmPlugFactory() plugin.AzVirtualMachinePlugin {
 	return azvmPlug
 }
 
+func strPtr(str string) *string {
+	return &str
+}
+
+func TestFormatNetworkMapElmnt(t *testing.T) {
+	var tests = []struct {
+		resourceName, nsgName, expectedElmnt string
+	}{
+		{"my-vm-nic", "my-vm-nsg", "my-vm-nic (nsg: my-vm-nsg)"},
+		{"my-vm-nic", "", "my-vm-nic"},
+	}
+
+	for _, td := range tests {
+		testName := td.expectedElmnt
+
+		t.Run(testName, func(t *testing.T) {
+			elmnt := plugin.FormatNetworkMapElmnt(td.resourceName, td.nsgName)
+
+			if elmnt != td.expectedElmnt {
+				t.Errorf("Formatting network map element failed; expected %s, received %s", td.expectedElmnt, elmnt)
+			}
+		})
+	}
+}
+
+func TestGenerateNetworkMap(t *testing.T) {
+	nic := armnetwork.Interface{
+		Name: strPtr("my-vm-nic"),
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{
+				ID: strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.Network/networkSecurityGroups/my-vm-nsg"),
+			},
+		},
+	}
+	subnet := armnetwork.Subnet{
+		ID: strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/default"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{
+				ID: strPtr("/subscriptions/x/resourceGroups/rg/providers/Microsoft.Network/networkSecurityGroups/default-subnet-nsg"),
+			},
+		},
+	}
+
+	expectedNetworkMap := []string{"my-vm-ip", "my-vm-nic (nsg: my-vm-nsg)", "default (nsg: default-subnet-nsg)", "my-vnet"}
+
+	networkMap, err := plugin.GenerateNetworkMap("my-vm-ip", &nic, &subnet)
+	if err != nil {
+		t.Errorf("Generating Azure VM network map failed; received error: %s", err)
+	}
+
+	if !reflect.DeepEqual(networkMap, expectedNetworkMap) {
+		t.Errorf("Generating Azure VM network map failed; expected %v, received %v", expectedNetworkMap, networkMap)
+	}
+}
+
 func TestGetResources(t *testing.T) {
 	azvmPlug := azvmPlugFactory()
 
@@ -55,3 +112,17 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceInventory(t *testing.T) {
+	azvmp := azvmPlugFactory()
+
+	inventory, _ := azvmp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure Virtual Machine Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
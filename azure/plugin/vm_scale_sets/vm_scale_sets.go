@@ -0,0 +1,164 @@
+package vm_scale_sets
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	log "github.com/sirupsen/logrus"
+
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type AzVMScaleSetPlugin struct {
+	AzureConn      azureconnector.AzureConnector
+	InvCache       *inventorycache.InventoryCache
+	SubscriptionID string
+}
+
+func ParseVMSSInstanceID(ipConfigID string) string {
+	// public IPs attached to scale set instances reference the instance's NIC IP config, e.g.:
+	// /subscriptions/<sub>/resourceGroups/<rg>/providers/Microsoft.Compute/virtualMachineScaleSets/<vmss>/virtualMachines/<idx>/networkInterfaces/<nic>/ipConfigurations/<ipconfig>
+	// the instance ID is everything before the NIC
+	instanceID, _, found := strings.Cut(ipConfigID, "/networkInterfaces/")
+	if !found || !strings.Contains(instanceID, "/virtualMachines/") {
+		return ""
+	}
+
+	return instanceID
+}
+
+func (azvmssp *AzVMScaleSetPlugin) GatherInstancePublicIPAddrData(vmss *armcompute.VirtualMachineScaleSet, pubIpAddrClient *armnetwork.PublicIPAddressesClient, ctx context.Context) (map[string]*generalResource.Resource, []string, error) {
+	instanceResources := make(map[string]*generalResource.Resource)
+	var instanceIDs []string
+
+	parsedVmssID, err := arm.ParseResourceID(*vmss.ID)
+	if err != nil {
+		return instanceResources, instanceIDs, err
+	}
+
+	vmssStatus := ""
+	if vmss.Properties != nil && vmss.Properties.ProvisioningState != nil {
+		vmssStatus = *vmss.Properties.ProvisioningState
+	}
+
+	// uniform scale set instances aren't returned when listing VMs, and their public IPs can only be fetched through the scale set itself
+	pubIpPager := pubIpAddrClient.NewListVirtualMachineScaleSetPublicIPAddressesPager(parsedVmssID.ResourceGroupName, *vmss.Name, nil)
+	for pubIpPager.More() {
+		nextPubIpSet, err := pubIpPager.NextPage(ctx)
+		if err != nil {
+			return instanceResources, instanceIDs, err
+		}
+
+		for _, publicIP := range nextPubIpSet.Value {
+			if publicIP.Properties == nil || publicIP.Properties.IPAddress == nil || publicIP.Properties.IPConfiguration == nil {
+				continue
+			}
+
+			instanceID := ParseVMSSInstanceID(*publicIP.Properties.IPConfiguration.ID)
+			if instanceID == "" {
+				log.Debug("could not determine scale set instance for public IP [ ", *publicIP.ID, " ]")
+				continue
+			}
+
+			instanceResource, exists := instanceResources[instanceID]
+			if !exists {
+				parsedInstanceID, err := arm.ParseResourceID(instanceID)
+				if err != nil {
+					return instanceResources, instanceIDs, err
+				}
+
+				instanceResource = &generalResource.Resource{
+					Id:        instanceID,
+					RID:       instanceID,
+					AccountID: azvmssp.SubscriptionID,
+					Name:      *vmss.Name + "_" + parsedInstanceID.Name,
+					Status:    vmssStatus,
+					CloudSvc:  "vm_scale_sets",
+				}
+				instanceResources[instanceID] = instanceResource
+				instanceIDs = append(instanceIDs, instanceID)
+			}
+
+			ipAddr := *publicIP.Properties.IPAddress
+			if publicIP.Properties.PublicIPAddressVersion != nil && *publicIP.Properties.PublicIPAddressVersion == armnetwork.IPVersionIPv6 {
+				instanceResource.PublicIPv6Addrs = append(instanceResource.PublicIPv6Addrs, ipAddr)
+			} else {
+				instanceResource.PublicIPv4Addrs = append(instanceResource.PublicIPv4Addrs, ipAddr)
+			}
+		}
+	}
+
+	return instanceResources, instanceIDs, nil
+}
+
+func (azvmssp *AzVMScaleSetPlugin) GetResources() ([]generalResource.Resource, error) {
+	var vmssResources []generalResource.Resource
+
+	vmssClient, err := armcompute.NewVirtualMachineScaleSetsClient(azvmssp.SubscriptionID, azvmssp.AzureConn.Credential, azvmssp.AzureConn.ClientOptions)
+	if err != nil {
+		return vmssResources, err
+	}
+
+	pubIpAddrClient, err := armnetwork.NewPublicIPAddressesClient(azvmssp.SubscriptionID, azvmssp.AzureConn.Credential, azvmssp.AzureConn.ClientOptions)
+	if err != nil {
+		return vmssResources, err
+	}
+
+	ctx := context.Background()
+	vmssPager := vmssClient.NewListAllPager(nil)
+	for vmssPager.More() {
+		nextVmssSet, err := vmssPager.NextPage(ctx)
+		if err != nil {
+			return vmssResources, err
+		}
+		vmssSet := nextVmssSet.Value
+		log.Debug("found [ ", len(vmssSet), " ] Azure virtual machine scale sets")
+
+		for _, vmss := range vmssSet {
+			log.Debug("Azure VM Scale Set found - ID: ", *vmss.ID, ", Name: ", *vmss.Name)
+
+			instanceResources, instanceIDs, err := azvmssp.GatherInstancePublicIPAddrData(vmss, pubIpAddrClient, ctx)
+			if err != nil {
+				return vmssResources, err
+			}
+
+			// instance IDs are tracked separately to keep the results in a stable order
+			for _, instanceID := range instanceIDs {
+				vmssResources = append(vmssResources, *instanceResources[instanceID])
+			}
+		}
+	}
+
+	return vmssResources, nil
+}
+
+func (azvmssp AzVMScaleSetPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+	log.Debug("fetching and searching Azure virtual machine scale set resources")
+
+	fetchedResources, err := inventorycache.Fetch(azvmssp.InvCache, "vm_scale_sets/"+azvmssp.SubscriptionID, azvmssp.GetResources)
+	if err != nil {
+		return matchingResource, err
+	}
+
+	for _, instanceResource := range fetchedResources {
+		if slices.Contains(instanceResource.PublicIPv4Addrs, tgtIP) || slices.Contains(instanceResource.PublicIPv6Addrs, tgtIP) {
+			matchingResource = &instanceResource
+
+			log.Debug("IP found as VM Scale Set instance -> ", matchingResource.RID)
+
+			break
+		}
+	}
+
+	return matchingResource, nil
+}
+
+func (azvmssp AzVMScaleSetPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(azvmssp.InvCache, "vm_scale_sets/"+azvmssp.SubscriptionID, azvmssp.GetResources)
+}
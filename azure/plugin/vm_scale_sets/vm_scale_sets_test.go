@@ -0,0 +1,99 @@
+package vm_scale_sets_test
+
+import (
+	"reflect"
+	"testing"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/vm_scale_sets"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func azvmssPlugFactory() plugin.AzVMScaleSetPlugin {
+	azvmssPlug := plugin.AzVMScaleSetPlugin{}
+
+	return azvmssPlug
+}
+
+func TestParseVMSSInstanceID(t *testing.T) {
+	var tests = []struct {
+		ipConfigID, expectedInstanceID string
+	}{
+		{
+			"/subscriptions/x/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/3/networkInterfaces/nic/ipConfigurations/ipconfig1",
+			"/subscriptions/x/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/3",
+		},
+		{
+			"/subscriptions/x/resourceGroups/rg/providers/Microsoft.Network/networkInterfaces/nic/ipConfigurations/ipconfig1",
+			"",
+		},
+		{"not_an_id", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.ipConfigID
+
+		t.Run(testName, func(t *testing.T) {
+			instanceID := plugin.ParseVMSSInstanceID(td.ipConfigID)
+
+			if instanceID != td.expectedInstanceID {
+				t.Errorf("Parsing VMSS instance ID failed; expected %s, received %s", td.expectedInstanceID, instanceID)
+			}
+		})
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	azvmssPlug := azvmssPlugFactory()
+
+	vmssResources, _ := azvmssPlug.GetResources()
+
+	expectedType := "Resource"
+	for _, resource := range vmssResources {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resources via Azure VM Scale Set Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	azvmssPlug := azvmssPlugFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+		{"18.161.22.61", "Resource"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "Resource"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
+	}
+
+	var matchingResource generalResource.Resource
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			matchedInstance, _ := azvmssPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedInstanceType := reflect.TypeOf(*matchedInstance)
+
+			if matchedInstanceType.Name() != td.expectedType {
+				t.Errorf("Azure VM Scale Set search failed; expected %s after search, received %s", td.expectedType, matchedInstanceType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	azvmssp := azvmssPlugFactory()
+
+	inventory, _ := azvmssp.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via Azure VM Scale Set Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
@@ -4,14 +4,15 @@ import (
 	"context"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 	log "github.com/sirupsen/logrus"
+
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
 )
 
 type AzPublicIPAddr struct{}
 
-func GetPublicIPAddressProperties(azureConn *azidentity.DefaultAzureCredential, publicIpData *armnetwork.PublicIPAddress, ctx context.Context) (armnetwork.PublicIPAddressesClientGetResponse, error) {
+func GetPublicIPAddressProperties(azureConn azureconnector.AzureConnector, publicIpData *armnetwork.PublicIPAddress, ctx context.Context) (armnetwork.PublicIPAddressesClientGetResponse, error) {
 	var pubIpAddrClient *armnetwork.PublicIPAddressesClient
 	var publicIpAddrProps armnetwork.PublicIPAddressesClientGetResponse
 	var err error
@@ -23,7 +24,7 @@ func GetPublicIPAddressProperties(azureConn *azidentity.DefaultAzureCredential,
 		return publicIpAddrProps, err
 	}
 
-	pubIpAddrClient, err = armnetwork.NewPublicIPAddressesClient(parsedPublicIpId.SubscriptionID, azureConn, nil)
+	pubIpAddrClient, err = armnetwork.NewPublicIPAddressesClient(parsedPublicIpId.SubscriptionID, azureConn.Credential, azureConn.ClientOptions)
 	if err != nil {
 		return publicIpAddrProps, err
 	}
@@ -5,8 +5,8 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
 	az_public_ip "github.com/magneticstain/ip-2-cloudresource/azure/public_ip"
 )
 
@@ -17,12 +17,12 @@ import (
 // }
 
 func TestGetPublicIPAddressProperties(t *testing.T) {
-	azCreds := azidentity.DefaultAzureCredential{}
+	azConn := azureconnector.AzureConnector{}
 
 	azPubIPMockID := "this_needs_to_be_set_or_theres_a_panic"
 	azPubIPObj := armnetwork.PublicIPAddress{ID: &azPubIPMockID}
 
-	azpipaProp, _ := az_public_ip.GetPublicIPAddressProperties(&azCreds, &azPubIPObj, context.Background())
+	azpipaProp, _ := az_public_ip.GetPublicIPAddressProperties(azConn, &azPubIPObj, context.Background())
 
 	expectedType := "PublicIPAddressesClientGetResponse"
 	resourceType := reflect.TypeOf(azpipaProp)
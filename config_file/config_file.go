@@ -0,0 +1,185 @@
+package configfile
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// a profile maps flag names (e.g. platform, org-search-role-name) to the values to use for them
+type Profile map[string]any
+
+type Config struct {
+	// used when no profile is selected with --profile
+	DefaultProfile string             `yaml:"default-profile"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+}
+
+func GetConfigDir() string {
+	// follows the XDG base directory spec, falling back to ~/.config if XDG_CONFIG_HOME isn't set
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+
+		configDir = filepath.Join(homeDir, ".config")
+	}
+
+	return filepath.Join(configDir, "ip2cr")
+}
+
+func GetDefaultPaths() []string {
+	configDir := GetConfigDir()
+	if configDir == "" {
+		return nil
+	}
+
+	return []string{
+		filepath.Join(configDir, "config.yaml"),
+		filepath.Join(configDir, "config.yml"),
+	}
+}
+
+func Find(configPath string) (string, error) {
+	// an explicitly set config file must exist, but the default ones are optional; an empty path is returned if none are found
+	if configPath != "" {
+		_, err := os.Stat(configPath)
+
+		return configPath, err
+	}
+
+	for _, defaultPath := range GetDefaultPaths() {
+		if _, err := os.Stat(defaultPath); err == nil {
+			return defaultPath, nil
+		}
+	}
+
+	return "", nil
+}
+
+func Read(configPath string) (Config, error) {
+	var cfg Config
+
+	rawConfig, err := os.ReadFile(configPath)
+	if err != nil {
+		return cfg, err
+	}
+
+	err = yaml.Unmarshal(rawConfig, &cfg)
+	if err != nil {
+		return cfg, fmt.Errorf("%s is not a valid config file: %w", configPath, err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg Config) GetProfileNames() []string {
+	var profileNames []string
+
+	for profileName := range cfg.Profiles {
+		profileNames = append(profileNames, profileName)
+	}
+	sort.Strings(profileNames)
+
+	return profileNames
+}
+
+func (cfg Config) GetProfile(profileName string) (Profile, error) {
+	// returns the default profile if no name is given, or an empty profile if there isn't a default either
+	if profileName == "" {
+		profileName = cfg.DefaultProfile
+	}
+	if profileName == "" {
+		return Profile{}, nil
+	}
+
+	profile, found := cfg.Profiles[profileName]
+	if !found {
+		return nil, fmt.Errorf("profile '%s' not found in config (available profiles: %s)", profileName, strings.Join(cfg.GetProfileNames(), ", "))
+	}
+
+	return profile, nil
+}
+
+func FormatValue(value any) (string, error) {
+	// converts a YAML value to the string the flag package expects; lists are joined as CSV, and maps as key=value pairs (e.g. for per-platform tenant IDs)
+	switch typedVal := value.(type) {
+	case string:
+		return typedVal, nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(typedVal), nil
+	case []any:
+		var listVals []string
+
+		for _, listVal := range typedVal {
+			fmtedVal, err := FormatValue(listVal)
+			if err != nil {
+				return "", err
+			}
+
+			listVals = append(listVals, fmtedVal)
+		}
+
+		return strings.Join(listVals, ","), nil
+	case map[string]any:
+		var mapVals []string
+
+		for mapKey, mapVal := range typedVal {
+			fmtedVal, err := FormatValue(mapVal)
+			if err != nil {
+				return "", err
+			}
+
+			mapVals = append(mapVals, mapKey+"="+fmtedVal)
+		}
+		sort.Strings(mapVals)
+
+		return strings.Join(mapVals, ","), nil
+	case Profile:
+		// the YAML decoder reuses the profile's map type for maps nested within it
+		return FormatValue(map[string]any(typedVal))
+	case nil:
+		return "", errors.New("value is empty")
+	}
+
+	return "", fmt.Errorf("unsupported value type %T", value)
+}
+
+func (profile Profile) Apply(flagSet *flag.FlagSet, disallowedFlags []string) error {
+	// sets each flag in the profile that wasn't set on the command line, so that CLI flags always take precedence
+	setFlags := map[string]bool{}
+	flagSet.Visit(func(setFlag *flag.Flag) {
+		setFlags[setFlag.Name] = true
+	})
+
+	for flagName, value := range profile {
+		if slices.Contains(disallowedFlags, flagName) || flagSet.Lookup(flagName) == nil {
+			return fmt.Errorf("'%s' is not a supported profile setting", flagName)
+		}
+
+		if setFlags[flagName] {
+			continue
+		}
+
+		fmtedVal, err := FormatValue(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for '%s': %w", flagName, err)
+		}
+
+		err = flagSet.Set(flagName, fmtedVal)
+		if err != nil {
+			return fmt.Errorf("invalid value for '%s': %w", flagName, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package configfile_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	configfile "github.com/magneticstain/ip-2-cloudresource/config_file"
+)
+
+const testConfig = `
+default-profile: prod
+profiles:
+  prod:
+    platform: aws
+    org-search: true
+    org-search-role-name: ip2cr-readonly
+    svc:
+      - ec2
+      - elbv2
+  multicloud:
+    platform: gcp
+    tenant-id:
+      gcp: my-project
+      azure: 00000000-0000-0000-0000-000000000000
+    snapshot-max-age: 12h
+    org-search-max-workers: 5
+`
+
+func configFactory(t *testing.T) string {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	err := os.WriteFile(configPath, []byte(testConfig), 0o600)
+	if err != nil {
+		t.Fatalf("Writing test config failed; received error: %s", err)
+	}
+
+	return configPath
+}
+
+type testFlags struct {
+	flagSet                                *flag.FlagSet
+	platform, tenantID, cloudSvc, roleName *string
+	orgSearch                              *bool
+	orgSearchMaxWorkers                    *int
+	snapshotMaxAge                         *time.Duration
+}
+
+func flagSetFactory() testFlags {
+	flagSet := flag.NewFlagSet("ip2cr", flag.ContinueOnError)
+
+	return testFlags{
+		flagSet:             flagSet,
+		platform:            flagSet.String("platform", "aws", ""),
+		tenantID:            flagSet.String("tenant-id", "", ""),
+		cloudSvc:            flagSet.String("svc", "all", ""),
+		roleName:            flagSet.String("org-search-role-name", "ip2cr", ""),
+		orgSearch:           flagSet.Bool("org-search", false, ""),
+		orgSearchMaxWorkers: flagSet.Int("org-search-max-workers", 10, ""),
+		snapshotMaxAge:      flagSet.Duration("snapshot-max-age", 24*time.Hour, ""),
+	}
+}
+
+func TestGetConfigDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+
+	configDir := configfile.GetConfigDir()
+	if configDir != "/tmp/xdg/ip2cr" {
+		t.Errorf("Getting config dir failed; expected /tmp/xdg/ip2cr, received %s", configDir)
+	}
+}
+
+func TestFind(t *testing.T) {
+	configPath := configFactory(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Dir(filepath.Dir(configPath)))
+
+	var tests = []struct {
+		name, configPath, expectedPath string
+		expectedErr                    bool
+	}{
+		{"ExplicitPath", configPath, configPath, false},
+		{"MissingExplicitPath", configPath + ".missing", "", true},
+		{"NoDefaultConfig", "", "", false},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			foundPath, err := configfile.Find(td.configPath)
+			if (err != nil) != td.expectedErr {
+				t.Fatalf("Finding config failed; expected error: %t, received: %v", td.expectedErr, err)
+			}
+
+			if !td.expectedErr && foundPath != td.expectedPath {
+				t.Errorf("Finding config failed; expected %s, received %s", td.expectedPath, foundPath)
+			}
+		})
+	}
+}
+
+func TestFind_XDGConfigDir(t *testing.T) {
+	xdgConfigHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+
+	expectedPath := filepath.Join(xdgConfigHome, "ip2cr", "config.yml")
+	_ = os.MkdirAll(filepath.Dir(expectedPath), 0o700)
+	_ = os.WriteFile(expectedPath, []byte(testConfig), 0o600)
+
+	foundPath, err := configfile.Find("")
+	if err != nil || foundPath != expectedPath {
+		t.Errorf("Finding config in XDG config dir failed; expected %s, received %s (error: %v)", expectedPath, foundPath, err)
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	var tests = []struct {
+		profileName, expectedPlatform string
+		expectedErr                   bool
+	}{
+		{"", "aws", false},
+		{"prod", "aws", false},
+		{"multicloud", "gcp", false},
+		{"missing", "", true},
+	}
+
+	cfg, err := configfile.Read(configFactory(t))
+	if err != nil {
+		t.Fatalf("Reading config failed; received error: %s", err)
+	}
+
+	for _, td := range tests {
+		t.Run(td.profileName, func(t *testing.T) {
+			profile, err := cfg.GetProfile(td.profileName)
+			if (err != nil) != td.expectedErr {
+				t.Fatalf("Getting profile failed; expected error: %t, received: %v", td.expectedErr, err)
+			}
+
+			if !td.expectedErr && profile["platform"] != td.expectedPlatform {
+				t.Errorf("Getting profile failed; expected platform %s, received %v", td.expectedPlatform, profile["platform"])
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	var tests = []struct {
+		value       any
+		expectedVal string
+		expectedErr bool
+	}{
+		{"ec2", "ec2", false},
+		{true, "true", false},
+		{5, "5", false},
+		{[]any{"ec2", "elbv2"}, "ec2,elbv2", false},
+		{map[string]any{"gcp": "my-project", "azure": "abc"}, "azure=abc,gcp=my-project", false},
+		{nil, "", true},
+	}
+
+	for _, td := range tests {
+		t.Run(td.expectedVal, func(t *testing.T) {
+			fmtedVal, err := configfile.FormatValue(td.value)
+			if (err != nil) != td.expectedErr {
+				t.Fatalf("Formatting value failed; expected error: %t, received: %v", td.expectedErr, err)
+			}
+
+			if fmtedVal != td.expectedVal {
+				t.Errorf("Formatting value failed; expected %s, received %s", td.expectedVal, fmtedVal)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	cfg, _ := configfile.Read(configFactory(t))
+
+	prodFlags := flagSetFactory()
+	_ = prodFlags.flagSet.Parse([]string{"-svc=cloudfront"})
+
+	prodProfile, _ := cfg.GetProfile("prod")
+	err := prodProfile.Apply(prodFlags.flagSet, []string{"profile"})
+	if err != nil {
+		t.Fatalf("Applying profile failed; received error: %s", err)
+	}
+
+	// flags set on the command line should win over the profile
+	if *prodFlags.cloudSvc != "cloudfront" || !*prodFlags.orgSearch || *prodFlags.roleName != "ip2cr-readonly" {
+		t.Errorf("Applying profile failed; received svc: %s, org-search: %t, role name: %s", *prodFlags.cloudSvc, *prodFlags.orgSearch, *prodFlags.roleName)
+	}
+
+	multicloudFlags := flagSetFactory()
+	_ = multicloudFlags.flagSet.Parse(nil)
+
+	multicloudProfile, _ := cfg.GetProfile("multicloud")
+	err = multicloudProfile.Apply(multicloudFlags.flagSet, []string{"profile"})
+	if err != nil {
+		t.Fatalf("Applying profile failed; received error: %s", err)
+	}
+
+	if *multicloudFlags.tenantID != "azure=00000000-0000-0000-0000-000000000000,gcp=my-project" || *multicloudFlags.orgSearchMaxWorkers != 5 || *multicloudFlags.snapshotMaxAge != 12*time.Hour {
+		t.Errorf("Applying profile failed; received tenant ID: %s, max workers: %d, snapshot max age: %s", *multicloudFlags.tenantID, *multicloudFlags.orgSearchMaxWorkers, *multicloudFlags.snapshotMaxAge)
+	}
+}
+
+func TestApply_InvalidSettings(t *testing.T) {
+	var tests = []struct {
+		name    string
+		profile configfile.Profile
+	}{
+		{"UnknownFlag", configfile.Profile{"not-a-flag": "value"}},
+		{"DisallowedFlag", configfile.Profile{"profile": "prod"}},
+		{"InvalidValue", configfile.Profile{"org-search-max-workers": "lots"}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			testFlags := flagSetFactory()
+			testFlags.flagSet.String("profile", "", "")
+
+			err := td.profile.Apply(testFlags.flagSet, []string{"profile"})
+			if err == nil {
+				t.Errorf("Applying invalid profile failed; expected error, received none")
+			}
+		})
+	}
+}
@@ -4,25 +4,76 @@ import (
 	"errors"
 	"fmt"
 
+	"cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
 	log "github.com/sirupsen/logrus"
 
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/cloud_asset"
+	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/cloud_nat"
 	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/cloud_sql"
 	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/compute"
+	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/gke"
 	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/load_balancing"
+	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/resource_manager"
+	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/vpn_gateway"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
-type GCPController struct{}
+type GCPController struct {
+	GCPConn gcpconnector.GCPConnector
+	// shared with plugins so resource inventories are only fetched once per project when searching for multiple IPs
+	InvCache *inventorycache.InventoryCache
+	// when set (e.g. via IP fuzzing), regional services only search resources in this region
+	Region string
+	// also match private (VPC) IPs for services that support it
+	MatchPrivateIPs bool
+}
+
+func New(connConfig gcpconnector.GCPConnectorConfig) (GCPController, error) {
+	gcpConn, err := gcpconnector.New(connConfig)
+	if err != nil {
+		return GCPController{}, err
+	}
+
+	gcpc := GCPController{GCPConn: gcpConn}
+
+	return gcpc, err
+}
 
 func GetSupportedSvcs() []string {
 	return []string{
 		"compute",
+		"gke",
 		"load_balancing",
 		"cloud_sql",
+		"cloud_nat",
+		"vpn_gateway",
 	}
 }
 
-func (gcpctrlr *GCPController) SearchGCPSvc(projectID, ipAddr, cloudSvc string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
+func (gcpctrlr *GCPController) FetchProjectIDs(parentID string) ([]string, error) {
+	var projectIDs []string
+
+	rmp := resource_manager.ResourceManagerPlugin{GCPConn: gcpctrlr.GCPConn, ParentID: parentID}
+	projects, err := rmp.GetResources()
+	if err != nil {
+		return projectIDs, err
+	}
+
+	for _, project := range projects {
+		if project.GetState() == resourcemanagerpb.Project_ACTIVE {
+			log.Debug("project found: ", project.GetProjectId(), " (", project.GetDisplayName(), ")")
+			projectIDs = append(projectIDs, project.GetProjectId())
+		} else {
+			log.Debug("project found, but not active: ", project.GetProjectId(), " (", project.GetDisplayName(), ")")
+		}
+	}
+
+	return projectIDs, nil
+}
+
+func (gcpctrlr *GCPController) SearchGCPSvc(projectID, ipAddr, cloudSvc string, doNetMapping bool, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
 	var err error
 
 	log.Debug("searching ", cloudSvc, " in GCP controller")
@@ -30,15 +81,32 @@ func (gcpctrlr *GCPController) SearchGCPSvc(projectID, ipAddr, cloudSvc string,
 	switch cloudSvc {
 	case "compute":
 		comp := compute.ComputePlugin{
-			ProjectID: projectID,
+			GCPConn:         gcpctrlr.GCPConn,
+			InvCache:        gcpctrlr.InvCache,
+			ProjectID:       projectID,
+			Region:          gcpctrlr.Region,
+			MatchPrivateIPs: gcpctrlr.MatchPrivateIPs,
 		}
 		_, err = comp.SearchResources(ipAddr, matchingResource)
 		if err != nil {
 			return *matchingResource, err
 		}
+	case "gke":
+		gkep := gke.GKEPlugin{
+			GCPConn:   gcpctrlr.GCPConn,
+			InvCache:  gcpctrlr.InvCache,
+			ProjectID: projectID,
+		}
+		_, err = gkep.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
 	case "load_balancing":
 		lbp := load_balancing.LoadBalancingPlugin{
-			ProjectID: projectID,
+			GCPConn:        gcpctrlr.GCPConn,
+			InvCache:       gcpctrlr.InvCache,
+			ProjectID:      projectID,
+			NetworkMapping: doNetMapping,
 		}
 		_, err = lbp.SearchResources(ipAddr, matchingResource)
 		if err != nil {
@@ -46,12 +114,46 @@ func (gcpctrlr *GCPController) SearchGCPSvc(projectID, ipAddr, cloudSvc string,
 		}
 	case "cloud_sql":
 		csqlp := cloud_sql.CloudSQLPlugin{
-			ProjectID: projectID,
+			GCPConn:         gcpctrlr.GCPConn,
+			InvCache:        gcpctrlr.InvCache,
+			ProjectID:       projectID,
+			Region:          gcpctrlr.Region,
+			MatchPrivateIPs: gcpctrlr.MatchPrivateIPs,
 		}
 		_, err = csqlp.SearchResources(ipAddr, matchingResource)
 		if err != nil {
 			return *matchingResource, err
 		}
+	case "cloud_nat":
+		cnatp := cloud_nat.CloudNATPlugin{
+			GCPConn:   gcpctrlr.GCPConn,
+			InvCache:  gcpctrlr.InvCache,
+			ProjectID: projectID,
+		}
+		_, err = cnatp.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
+	case "vpn_gateway":
+		vpngwp := vpn_gateway.VPNGatewayPlugin{
+			GCPConn:   gcpctrlr.GCPConn,
+			InvCache:  gcpctrlr.InvCache,
+			ProjectID: projectID,
+		}
+		_, err = vpngwp.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
+	case "cloud_asset":
+		// not included in the supported svcs list since it searches across all of them; projectID can also be a folder or org scope here
+		casp := cloud_asset.CloudAssetPlugin{
+			GCPConn: gcpctrlr.GCPConn,
+			Scope:   projectID,
+		}
+		_, err = casp.SearchResources(ipAddr, matchingResource)
+		if err != nil {
+			return *matchingResource, err
+		}
 	default:
 		msg := fmt.Sprintf("unknown GCP service provided: '%s'", cloudSvc)
 
@@ -60,3 +162,31 @@ func (gcpctrlr *GCPController) SearchGCPSvc(projectID, ipAddr, cloudSvc string,
 
 	return *matchingResource, nil
 }
+
+func (gcpctrlr *GCPController) GetGCPSvcInventory(projectID, cloudSvc string) ([]generalResource.Resource, error) {
+	log.Debug("fetching ", cloudSvc, " inventory in GCP controller")
+
+	switch cloudSvc {
+	case "compute":
+		comp := compute.ComputePlugin{GCPConn: gcpctrlr.GCPConn, InvCache: gcpctrlr.InvCache, ProjectID: projectID, Region: gcpctrlr.Region}
+		return comp.GetResourceInventory()
+	case "gke":
+		gkep := gke.GKEPlugin{GCPConn: gcpctrlr.GCPConn, InvCache: gcpctrlr.InvCache, ProjectID: projectID}
+		return gkep.GetResourceInventory()
+	case "load_balancing":
+		lbp := load_balancing.LoadBalancingPlugin{GCPConn: gcpctrlr.GCPConn, InvCache: gcpctrlr.InvCache, ProjectID: projectID}
+		return lbp.GetResourceInventory()
+	case "cloud_sql":
+		csqlp := cloud_sql.CloudSQLPlugin{GCPConn: gcpctrlr.GCPConn, InvCache: gcpctrlr.InvCache, ProjectID: projectID, Region: gcpctrlr.Region, MatchPrivateIPs: gcpctrlr.MatchPrivateIPs}
+		return csqlp.GetResourceInventory()
+	case "cloud_nat":
+		cnatp := cloud_nat.CloudNATPlugin{GCPConn: gcpctrlr.GCPConn, InvCache: gcpctrlr.InvCache, ProjectID: projectID}
+		return cnatp.GetResourceInventory()
+	case "vpn_gateway":
+		vpngwp := vpn_gateway.VPNGatewayPlugin{GCPConn: gcpctrlr.GCPConn, InvCache: gcpctrlr.InvCache, ProjectID: projectID}
+		return vpngwp.GetResourceInventory()
+	default:
+		// Cloud Asset Inventory can only be queried for a specific IP, so it can't be used to build an inventory
+		return nil, fmt.Errorf("unknown or unsupported GCP service provided for inventory: '%s'", cloudSvc)
+	}
+}
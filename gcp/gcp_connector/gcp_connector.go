@@ -0,0 +1,108 @@
+package gcpconnector
+
+import (
+	"context"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+type GCPConnector struct {
+	ClientOptions []option.ClientOption
+	// only set when the default credentials are overridden; see GetTokenSource()
+	TokenSource oauth2.TokenSource
+}
+
+type GCPConnectorConfig struct {
+	// email of the service account to impersonate; the base credentials need roles/iam.serviceAccountTokenCreator on it
+	ImpersonateServiceAccount string
+	// path to a service account key or external account config to use instead of ADC
+	CredentialsFile string
+	// project to bill API quota to, if different from the project being searched
+	QuotaProject string
+	// override the API endpoint for all clients, e.g. to target a local emulator
+	Endpoint string
+}
+
+func GetCredentialsFileTokenSource(ctx context.Context, credsFilePath string) (oauth2.TokenSource, error) {
+	credsData, err := os.ReadFile(credsFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := google.CredentialsFromJSON(ctx, credsData, cloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+
+	return creds.TokenSource, nil
+}
+
+func GetImpersonatedTokenSource(ctx context.Context, connConfig GCPConnectorConfig) (oauth2.TokenSource, error) {
+	var baseOpts []option.ClientOption
+
+	// the credentials file, if set, is used as the identity performing the impersonation
+	if connConfig.CredentialsFile != "" {
+		baseOpts = append(baseOpts, option.WithCredentialsFile(connConfig.CredentialsFile))
+	}
+
+	return impersonate.CredentialsTokenSource(
+		ctx,
+		impersonate.CredentialsConfig{
+			TargetPrincipal: connConfig.ImpersonateServiceAccount,
+			Scopes:          []string{cloudPlatformScope},
+		},
+		baseOpts...,
+	)
+}
+
+func New(connConfig GCPConnectorConfig) (GCPConnector, error) {
+	var gc GCPConnector
+	var err error
+
+	ctx := context.Background()
+
+	switch {
+	case connConfig.ImpersonateServiceAccount != "":
+		log.Debug("connecting to GCP by impersonating service account ", connConfig.ImpersonateServiceAccount)
+		gc.TokenSource, err = GetImpersonatedTokenSource(ctx, connConfig)
+	case connConfig.CredentialsFile != "":
+		log.Debug("connecting to GCP using credentials file ", connConfig.CredentialsFile)
+		gc.TokenSource, err = GetCredentialsFileTokenSource(ctx, connConfig.CredentialsFile)
+	default:
+		// no token source needed; clients will use ADC ( https://cloud.google.com/docs/authentication/application-default-credentials / https://archive.is/tSqC2 )
+		log.Debug("connecting to GCP using application default credentials")
+	}
+	if err != nil {
+		return gc, err
+	}
+
+	if gc.TokenSource != nil {
+		gc.ClientOptions = append(gc.ClientOptions, option.WithTokenSource(gc.TokenSource))
+	}
+
+	if connConfig.QuotaProject != "" {
+		gc.ClientOptions = append(gc.ClientOptions, option.WithQuotaProject(connConfig.QuotaProject))
+	}
+
+	if connConfig.Endpoint != "" {
+		gc.ClientOptions = append(gc.ClientOptions, option.WithEndpoint(connConfig.Endpoint))
+	}
+
+	return gc, nil
+}
+
+func (gc GCPConnector) GetTokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	// for clients outside of the Google SDKs (e.g. k8s) that need a raw token source instead of client options
+	if gc.TokenSource != nil {
+		return gc.TokenSource, nil
+	}
+
+	return google.DefaultTokenSource(ctx, cloudPlatformScope)
+}
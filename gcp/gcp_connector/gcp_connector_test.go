@@ -0,0 +1,44 @@
+package gcpconnector_test
+
+import (
+	"testing"
+
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+)
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		name                string
+		connConfig          gcpconnector.GCPConnectorConfig
+		expectedClientOpts  int
+		expectedTokenSource bool
+		expectError         bool
+	}{
+		{"default", gcpconnector.GCPConnectorConfig{}, 0, false, false},
+		{"quota_project", gcpconnector.GCPConnectorConfig{QuotaProject: "my-billing-project"}, 1, false, false},
+		{"quota_project_and_endpoint", gcpconnector.GCPConnectorConfig{QuotaProject: "my-billing-project", Endpoint: "http://127.0.0.1:8080"}, 2, false, false},
+		{"missing_creds_file", gcpconnector.GCPConnectorConfig{CredentialsFile: "/this/path/does/not/exist.json"}, 0, false, true},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			gc, err := gcpconnector.New(td.connConfig)
+
+			if td.expectError {
+				if err == nil {
+					t.Errorf("GCP connector creation should have failed for %s, but did not", td.name)
+				}
+			} else if err != nil {
+				t.Errorf("GCP connector creation failed for %s; received error: %s", td.name, err)
+			}
+
+			if len(gc.ClientOptions) != td.expectedClientOpts {
+				t.Errorf("GCP connector creation failed; expected %d client options, received %d", td.expectedClientOpts, len(gc.ClientOptions))
+			}
+
+			if (gc.TokenSource != nil) != td.expectedTokenSource {
+				t.Errorf("GCP connector creation failed; expected token source to be set: %t", td.expectedTokenSource)
+			}
+		})
+	}
+}
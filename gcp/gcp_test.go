@@ -20,8 +20,12 @@ func TestSearchGCPSvc(t *testing.T) {
 		cloudSvc, ipAddr string
 	}{
 		{"compute", "1.1.1.1"},
+		{"gke", "1.1.1.1"},
 		{"load_balancing", "1.1.1.1"},
 		{"cloud_sql", "1.1.1.1"},
+		{"cloud_nat", "1.1.1.1"},
+		{"vpn_gateway", "1.1.1.1"},
+		{"cloud_asset", "1.1.1.1"},
 	}
 
 	for _, td := range tests {
@@ -31,7 +35,7 @@ func TestSearchGCPSvc(t *testing.T) {
 		resource := generalResource.Resource{}
 
 		t.Run(testName, func(t *testing.T) {
-			res, _ := ac.SearchGCPSvc("", td.ipAddr, td.cloudSvc, &resource)
+			res, _ := ac.SearchGCPSvc("", td.ipAddr, td.cloudSvc, false, &resource)
 
 			resType := reflect.TypeOf(res)
 			expectedType := "Resource"
@@ -58,7 +62,7 @@ func TestSearchGCPSvc_UnknownCloudSvc(t *testing.T) {
 		resource := generalResource.Resource{}
 
 		t.Run(testName, func(t *testing.T) {
-			_, err := ac.SearchGCPSvc("", td.ipAddr, td.cloudSvc, &resource)
+			_, err := ac.SearchGCPSvc("", td.ipAddr, td.cloudSvc, false, &resource)
 			if err == nil {
 				t.Errorf("Error was expected, but not seen, when performing general GCP search; using %s for unknown cloud service name", td.cloudSvc)
 			}
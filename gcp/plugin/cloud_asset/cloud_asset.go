@@ -0,0 +1,167 @@
+package cloud_asset
+
+/*
+DEV NOTE:
+---
+Cloud Asset Inventory indexes resources across every project in an org (or folder), so a single free-text query for the IP can stand in for calling each service's API in each project. The IP addresses themselves live in each result's additional attributes, which vary by asset type, so we walk those to confirm the match rather than trusting the free-text search outright.
+
+https://cloud.google.com/asset-inventory/docs/searching-resources
+*/
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	gcpassetapi "cloud.google.com/go/asset/apiv1"
+	gcpassetpbapi "cloud.google.com/go/asset/apiv1/assetpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	selflink "github.com/magneticstain/ip-2-cloudresource/gcp/self_link"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type CloudAssetPlugin struct {
+	GCPConn gcpconnector.GCPConnector
+	// projects/<id>, folders/<id>, or organizations/<id>; bare IDs are treated as project IDs
+	Scope string
+}
+
+func GetSupportedAssetTypes() map[string]string {
+	// asset type => IP2CR cloud service
+	return map[string]string{
+		"compute.googleapis.com/Instance":             "compute",
+		"compute.googleapis.com/Address":              "load_balancing",
+		"compute.googleapis.com/GlobalAddress":        "load_balancing",
+		"compute.googleapis.com/ForwardingRule":       "load_balancing",
+		"compute.googleapis.com/GlobalForwardingRule": "load_balancing",
+		"compute.googleapis.com/Router":               "cloud_nat",
+		"compute.googleapis.com/VpnGateway":           "vpn_gateway",
+		"sqladmin.googleapis.com/Instance":            "cloud_sql",
+		"container.googleapis.com/Cluster":            "gke",
+	}
+}
+
+func NormalizeScope(scope string) string {
+	if scope != "" && !strings.Contains(scope, "/") {
+		return "projects/" + scope
+	}
+
+	return scope
+}
+
+func ValueContainsIP(val *structpb.Value, tgtIP string) bool {
+	switch attrVal := val.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		// some attributes store multiple addresses in a single CSV string
+		for _, attrStr := range strings.Split(attrVal.StringValue, ",") {
+			if strings.TrimSpace(attrStr) == tgtIP {
+				return true
+			}
+		}
+	case *structpb.Value_ListValue:
+		for _, listVal := range attrVal.ListValue.GetValues() {
+			if ValueContainsIP(listVal, tgtIP) {
+				return true
+			}
+		}
+	case *structpb.Value_StructValue:
+		return AttributesContainIP(attrVal.StructValue, tgtIP)
+	}
+
+	return false
+}
+
+func AttributesContainIP(attrs *structpb.Struct, tgtIP string) bool {
+	for _, attrVal := range attrs.GetFields() {
+		if ValueContainsIP(attrVal, tgtIP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ProcessSearchResult(searchResult *gcpassetpbapi.ResourceSearchResult) generalResource.Resource {
+	// EX: //compute.googleapis.com/projects/my-project/zones/us-central1-a/instances/my-vm
+	_, resourcePath, _ := strings.Cut(strings.TrimPrefix(searchResult.GetName(), "//"), "/")
+
+	cloudSvc, supported := GetSupportedAssetTypes()[searchResult.GetAssetType()]
+	if !supported {
+		cloudSvc = searchResult.GetAssetType()
+	}
+
+	return generalResource.Resource{
+		Id:        selflink.GetResourceName(searchResult.GetName()),
+		RID:       strings.TrimPrefix(searchResult.GetName(), "//"),
+		AccountID: selflink.GetProject(resourcePath),
+		Name:      searchResult.GetDisplayName(),
+		Status:    searchResult.GetState(),
+		CloudSvc:  cloudSvc,
+	}
+}
+
+func (casp CloudAssetPlugin) GetResources(tgtIP string) ([]*gcpassetpbapi.ResourceSearchResult, error) {
+	var searchResults []*gcpassetpbapi.ResourceSearchResult
+	var assetTypes []string
+
+	ctx := context.Background()
+
+	assetClient, err := gcpassetapi.NewRESTClient(ctx, casp.GCPConn.ClientOptions...)
+	if err != nil {
+		return searchResults, err
+	}
+	defer assetClient.Close()
+
+	for assetType := range GetSupportedAssetTypes() {
+		assetTypes = append(assetTypes, assetType)
+	}
+
+	// unlike the other plugins, we can't list everything and filter locally since an org could have millions of assets
+	req := &gcpassetpbapi.SearchAllResourcesRequest{
+		Scope:      NormalizeScope(casp.Scope),
+		Query:      fmt.Sprintf("%q", tgtIP),
+		AssetTypes: assetTypes,
+	}
+
+	resultList := assetClient.SearchAllResources(ctx, req)
+	for {
+		searchResult, err := resultList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return searchResults, err
+		}
+
+		log.Debug("cloud asset found - Name: ", searchResult.GetName(), ", Type: ", searchResult.GetAssetType())
+
+		searchResults = append(searchResults, searchResult)
+	}
+
+	return searchResults, nil
+}
+
+func (casp CloudAssetPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
+	log.Debug("searching cloud asset inventory in scope ", NormalizeScope(casp.Scope))
+
+	searchResults, err := casp.GetResources(tgtIP)
+	if err != nil {
+		return *matchingResource, err
+	}
+
+	for _, searchResult := range searchResults {
+		if AttributesContainIP(searchResult.GetAdditionalAttributes(), tgtIP) {
+			*matchingResource = ProcessSearchResult(searchResult)
+
+			log.Debug("IP found as ", searchResult.GetAssetType(), " asset -> ", matchingResource.RID)
+
+			break
+		}
+	}
+
+	return *matchingResource, nil
+}
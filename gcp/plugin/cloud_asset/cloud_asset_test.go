@@ -0,0 +1,151 @@
+package cloud_asset_test
+
+import (
+	"reflect"
+	"testing"
+
+	gcpassetpbapi "cloud.google.com/go/asset/apiv1/assetpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/cloud_asset"
+)
+
+func caPlugFactory() plugin.CloudAssetPlugin {
+	caPlug := plugin.CloudAssetPlugin{}
+
+	return caPlug
+}
+
+func TestNormalizeScope(t *testing.T) {
+	var tests = []struct {
+		scope, expectedScope string
+	}{
+		{"my-project", "projects/my-project"},
+		{"projects/my-project", "projects/my-project"},
+		{"folders/987654321098", "folders/987654321098"},
+		{"organizations/123456789012", "organizations/123456789012"},
+		{"", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.scope
+
+		t.Run(testName, func(t *testing.T) {
+			scope := plugin.NormalizeScope(td.scope)
+
+			if scope != td.expectedScope {
+				t.Errorf("Normalizing GCP asset search scope failed; expected %s, received %s", td.expectedScope, scope)
+			}
+		})
+	}
+}
+
+func TestAttributesContainIP(t *testing.T) {
+	attrs, err := structpb.NewStruct(map[string]interface{}{
+		"address":     "34.1.2.3",
+		"ipAddresses": "10.0.0.2,35.4.5.6",
+		"externalIPs": []interface{}{"2600:1900:4000:1::"},
+		"networkInterfaces": map[string]interface{}{
+			"accessConfigs": []interface{}{map[string]interface{}{"natIP": "34.9.8.7"}},
+		},
+		"addressCount": 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		ipAddr   string
+		expected bool
+	}{
+		{"34.1.2.3", true},
+		{"35.4.5.6", true},
+		{"2600:1900:4000:1::", true},
+		{"34.9.8.7", true},
+		{"34.1.2.33", false},
+		{"1.1.1.1", false},
+		{"3", false},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			found := plugin.AttributesContainIP(attrs, td.ipAddr)
+
+			if found != td.expected {
+				t.Errorf("Searching GCP asset attributes for IP failed; expected %t, received %t", td.expected, found)
+			}
+		})
+	}
+}
+
+func TestProcessSearchResult(t *testing.T) {
+	var tests = []struct {
+		searchResult                                 *gcpassetpbapi.ResourceSearchResult
+		expectedId, expectedAcctID, expectedCloudSvc string
+	}{
+		{
+			&gcpassetpbapi.ResourceSearchResult{
+				Name:        "//compute.googleapis.com/projects/my-project/zones/us-central1-a/instances/my-vm",
+				AssetType:   "compute.googleapis.com/Instance",
+				DisplayName: "my-vm",
+				State:       "RUNNING",
+			},
+			"my-vm", "my-project", "compute",
+		},
+		{
+			&gcpassetpbapi.ResourceSearchResult{
+				Name:        "//compute.googleapis.com/projects/lb-project/global/forwardingRules/my-fr",
+				AssetType:   "compute.googleapis.com/GlobalForwardingRule",
+				DisplayName: "my-fr",
+			},
+			"my-fr", "lb-project", "load_balancing",
+		},
+		{
+			&gcpassetpbapi.ResourceSearchResult{
+				Name:        "//sqladmin.googleapis.com/projects/db-project/instances/my-db",
+				AssetType:   "sqladmin.googleapis.com/Instance",
+				DisplayName: "my-db",
+			},
+			"my-db", "db-project", "cloud_sql",
+		},
+		{
+			&gcpassetpbapi.ResourceSearchResult{
+				Name:      "//storage.googleapis.com/projects/_/buckets/my-bucket",
+				AssetType: "storage.googleapis.com/Bucket",
+			},
+			"my-bucket", "_", "storage.googleapis.com/Bucket",
+		},
+	}
+
+	for _, td := range tests {
+		testName := td.searchResult.GetName()
+
+		t.Run(testName, func(t *testing.T) {
+			resource := plugin.ProcessSearchResult(td.searchResult)
+
+			if resource.Id != td.expectedId || resource.AccountID != td.expectedAcctID || resource.CloudSvc != td.expectedCloudSvc {
+				t.Errorf(
+					"Processing GCP asset search result failed; expected %s/%s/%s, received %s/%s/%s",
+					td.expectedId, td.expectedAcctID, td.expectedCloudSvc,
+					resource.Id, resource.AccountID, resource.CloudSvc,
+				)
+			}
+		})
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	caPlug := caPlugFactory()
+
+	searchResults, _ := caPlug.GetResources("1.1.1.1")
+
+	expectedType := "ResourceSearchResult"
+	for _, searchResult := range searchResults {
+		searchResultType := reflect.TypeOf(searchResult).Elem()
+		if searchResultType.Name() != expectedType {
+			t.Errorf("Fetching resources via GCP Cloud Asset Plugin failed; wanted %s type, received %s", expectedType, searchResultType.Name())
+		}
+	}
+}
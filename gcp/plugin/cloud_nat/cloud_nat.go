@@ -0,0 +1,170 @@
+package cloud_nat
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	gcpcomputeapi "cloud.google.com/go/compute/apiv1"
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
+
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	selflink "github.com/magneticstain/ip-2-cloudresource/gcp/self_link"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+type CloudNATPlugin struct {
+	GCPConn   gcpconnector.GCPConnector
+	InvCache  *inventorycache.InventoryCache
+	ProjectID string
+}
+
+func GetNATIPAddrs(natStatus *gcpcomputepbapi.RouterStatusNatStatus) []string {
+	var natIPAddrs []string
+
+	// draining IPs are still in use by existing connections, so we include them as well
+	for _, natIPAddrSet := range [][]string{
+		natStatus.GetAutoAllocatedNatIps(),
+		natStatus.GetUserAllocatedNatIps(),
+		natStatus.GetDrainAutoAllocatedNatIps(),
+		natStatus.GetDrainUserAllocatedNatIps(),
+	} {
+		for _, natIPAddr := range natIPAddrSet {
+			if !slices.Contains(natIPAddrs, natIPAddr) {
+				natIPAddrs = append(natIPAddrs, natIPAddr)
+			}
+		}
+	}
+
+	return natIPAddrs
+}
+
+func (cnatp CloudNATPlugin) ProcessNATStatus(router *gcpcomputepbapi.Router, natStatus *gcpcomputepbapi.RouterStatusNatStatus) (generalResource.Resource, error) {
+	natResource := generalResource.Resource{
+		Id:        strconv.FormatUint(router.GetId(), 10),
+		RID:       selflink.GetResourcePath(router.GetSelfLink()),
+		AccountID: cnatp.ProjectID,
+		Name:      fmt.Sprintf("%s (router: %s)", natStatus.GetName(), router.GetName()),
+		CloudSvc:  "cloud_nat",
+		// NAT IPs are only ever used as the source of egress traffic
+		IPType: "outbound",
+	}
+
+	for _, natIPAddr := range GetNATIPAddrs(natStatus) {
+		ipVer, err := utils.DetermineIpAddrVersion(natIPAddr)
+		if err != nil {
+			return natResource, err
+		}
+
+		switch ipVer {
+		case 4:
+			natResource.PublicIPv4Addrs = append(natResource.PublicIPv4Addrs, natIPAddr)
+		case 6:
+			natResource.PublicIPv6Addrs = append(natResource.PublicIPv6Addrs, natIPAddr)
+		}
+	}
+
+	return natResource, nil
+}
+
+func (cnatp CloudNATPlugin) GetRouterNATResources(ctx context.Context, routerClient *gcpcomputeapi.RoutersClient, router *gcpcomputepbapi.Router) ([]generalResource.Resource, error) {
+	var natResources []generalResource.Resource
+
+	// the router config only includes manually reserved NAT IPs (as address self-links); auto-allocated IPs are only available via the router's status
+	routerStatus, err := routerClient.GetRouterStatus(ctx, &gcpcomputepbapi.GetRouterStatusRouterRequest{
+		Project: cnatp.ProjectID,
+		Region:  selflink.GetResourceName(router.GetRegion()),
+		Router:  router.GetName(),
+	})
+	if err != nil {
+		return natResources, err
+	}
+
+	for _, natStatus := range routerStatus.GetResult().GetNatStatus() {
+		natResource, err := cnatp.ProcessNATStatus(router, natStatus)
+		if err != nil {
+			return natResources, err
+		}
+
+		log.Debug("Cloud NAT gateway found - Name: ", natResource.Name, ", Router: ", natResource.RID)
+
+		natResources = append(natResources, natResource)
+	}
+
+	return natResources, nil
+}
+
+func (cnatp CloudNATPlugin) GetResources() ([]generalResource.Resource, error) {
+	var natResources []generalResource.Resource
+
+	ctx := context.Background()
+
+	routerClient, err := gcpcomputeapi.NewRoutersRESTClient(ctx, cnatp.GCPConn.ClientOptions...)
+	if err != nil {
+		return natResources, err
+	}
+	defer routerClient.Close()
+
+	req := &gcpcomputepbapi.AggregatedListRoutersRequest{
+		Project:              cnatp.ProjectID,
+		ReturnPartialSuccess: proto.Bool(true),
+	}
+
+	routerList := routerClient.AggregatedList(ctx, req)
+	for {
+		routerListPair, err := routerList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return natResources, err
+		}
+
+		for _, router := range routerListPair.Value.GetRouters() {
+			if len(router.GetNats()) == 0 {
+				// routers without NAT configs (e.g. those only used for BGP) don't hold any external IPs
+				continue
+			}
+
+			routerNATResources, err := cnatp.GetRouterNATResources(ctx, routerClient, router)
+			if err != nil {
+				return natResources, err
+			}
+
+			natResources = append(natResources, routerNATResources...)
+		}
+	}
+
+	return natResources, nil
+}
+
+func (cnatp CloudNATPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
+	log.Debug("fetching and searching Cloud NAT resources")
+
+	natResources, err := inventorycache.Fetch(cnatp.InvCache, "cloud_nat/"+cnatp.ProjectID, cnatp.GetResources)
+	if err != nil {
+		return *matchingResource, err
+	}
+
+	for _, natResource := range natResources {
+		if slices.Contains(natResource.PublicIPv4Addrs, tgtIP) || slices.Contains(natResource.PublicIPv6Addrs, tgtIP) {
+			*matchingResource = natResource
+
+			log.Debug("IP found as Cloud NAT gateway -> ", matchingResource.Name, " on router ", matchingResource.RID)
+
+			break
+		}
+	}
+
+	return *matchingResource, nil
+}
+
+func (cnatp CloudNATPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(cnatp.InvCache, "cloud_nat/"+cnatp.ProjectID, cnatp.GetResources)
+}
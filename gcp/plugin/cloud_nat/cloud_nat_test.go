@@ -0,0 +1,119 @@
+package cloud_nat_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/protobuf/proto"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/cloud_nat"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func cnatPlugFactory() plugin.CloudNATPlugin {
+	cnatPlug := plugin.CloudNATPlugin{ProjectID: "my-project"}
+
+	return cnatPlug
+}
+
+func natStatusFactory() *gcpcomputepbapi.RouterStatusNatStatus {
+	return &gcpcomputepbapi.RouterStatusNatStatus{
+		Name:                     proto.String("egress-nat"),
+		AutoAllocatedNatIps:      []string{"34.1.2.3", "34.1.2.4"},
+		UserAllocatedNatIps:      []string{"35.1.2.3"},
+		DrainUserAllocatedNatIps: []string{"35.1.2.4", "34.1.2.3"},
+	}
+}
+
+func TestGetNATIPAddrs(t *testing.T) {
+	natIPAddrs := plugin.GetNATIPAddrs(natStatusFactory())
+
+	expectedNATIPAddrs := []string{"34.1.2.3", "34.1.2.4", "35.1.2.3", "35.1.2.4"}
+	if !slices.Equal(natIPAddrs, expectedNATIPAddrs) {
+		t.Errorf("Gathering Cloud NAT IPs failed; expected %s, received %s", expectedNATIPAddrs, natIPAddrs)
+	}
+}
+
+func TestProcessNATStatus(t *testing.T) {
+	cnatPlug := cnatPlugFactory()
+
+	router := &gcpcomputepbapi.Router{
+		Id:       proto.Uint64(1234),
+		Name:     proto.String("egress-router"),
+		SelfLink: proto.String("https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1/routers/egress-router"),
+	}
+
+	natResource, err := cnatPlug.ProcessNATStatus(router, natStatusFactory())
+	if err != nil {
+		t.Errorf("Processing Cloud NAT status failed; received error: %s", err)
+	}
+
+	expectedRID := "projects/my-project/regions/us-central1/routers/egress-router"
+	if natResource.RID != expectedRID || natResource.Name != "egress-nat (router: egress-router)" {
+		t.Errorf("Processing Cloud NAT status failed; expected %s, received %s (%s)", expectedRID, natResource.RID, natResource.Name)
+	}
+
+	if natResource.IPType != "outbound" || len(natResource.PublicIPv4Addrs) != 4 {
+		t.Errorf("Processing Cloud NAT status failed; expected 4 outbound IPs, received %d %s IPs", len(natResource.PublicIPv4Addrs), natResource.IPType)
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	cnatPlug := cnatPlugFactory()
+
+	natResources, _ := cnatPlug.GetResources()
+
+	expectedType := "Resource"
+	for _, natResource := range natResources {
+		natResourceType := reflect.TypeOf(natResource)
+		if natResourceType.Name() != expectedType {
+			t.Errorf("Fetching resources via Cloud NAT Plugin failed; wanted %s type, received %s", expectedType, natResourceType.Name())
+		}
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	cnatPlug := cnatPlugFactory()
+
+	var tests = []struct {
+		ipAddr string
+	}{
+		{"1.1.1.1"},
+		{"1234.45.9666.1"},
+		{"18.161.22.61"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21"},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			var matchingResource generalResource.Resource
+
+			res, _ := cnatPlug.SearchResources(td.ipAddr, &matchingResource)
+
+			expectedType := "Resource"
+			resType := reflect.TypeOf(res)
+			if resType.Name() != expectedType {
+				t.Errorf("Cloud NAT search failed; expected %s after search, received %s", expectedType, resType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	cnatPlug := cnatPlugFactory()
+
+	inventory, _ := cnatPlug.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via GCP Cloud NAT Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
@@ -19,106 +19,155 @@ import (
 
 	"google.golang.org/api/sqladmin/v1"
 
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type CloudSQLPlugin struct {
+	GCPConn   gcpconnector.GCPConnector
+	InvCache  *inventorycache.InventoryCache
 	ProjectID string
+	Region    string
+	// also match the private (VPC) IPs of instances
+	MatchPrivateIPs bool
 }
 
-func (csqlp CloudSQLPlugin) GetResources() ([]generalResource.Resource, error) {
-	var csqlResources []generalResource.Resource
+func GetConnectionName(csqlInstance *sqladmin.DatabaseInstance) string {
+	// connection names are already project-qualified, e.g. my-project:us-central1:my-db
+	if csqlInstance.ConnectionName != "" {
+		return csqlInstance.ConnectionName
+	}
 
-	ctx := context.Background()
+	return fmt.Sprintf("%s:%s:%s", csqlInstance.Project, csqlInstance.Region, csqlInstance.Name)
+}
 
-	sqlAdminSvc, err := sqladmin.NewService(ctx)
-	if err != nil {
-		return csqlResources, err
+func (csqlp CloudSQLPlugin) GetCacheKey() string {
+	// private IPs are only included in the fetched resources when they're being matched, so those get their own cache entry
+	cacheKey := "cloud_sql/" + csqlp.ProjectID + "/" + csqlp.Region
+	if csqlp.MatchPrivateIPs {
+		cacheKey += "/private"
 	}
 
-	csqlInstListCall := sqlAdminSvc.Instances.List(csqlp.ProjectID)
-	csqlInstListResp, err := csqlInstListCall.Do()
-	if err != nil {
-		return csqlResources, err
+	return cacheKey
+}
+
+func (csqlp CloudSQLPlugin) ProcessInstance(csqlInstance *sqladmin.DatabaseInstance) (CloudSQLResource, error) {
+	csqlResource := CloudSQLResource{
+		Resource: generalResource.Resource{
+			Id:        csqlInstance.Name,
+			RID:       GetConnectionName(csqlInstance),
+			AccountID: csqlp.ProjectID,
+			Name:      csqlInstance.Name,
+			Status:    csqlInstance.State,
+			CloudSvc:  "cloud_sql",
+		},
+		IPTypes: make(map[string]string),
 	}
 
-	for _, csqlInstance := range csqlInstListResp.Items {
-		instanceName := csqlInstance.Name
-		instanceStatus := csqlInstance.State
-		instanceIpAddrs := csqlInstance.IpAddresses
+	for _, ipMapping := range csqlInstance.IpAddresses {
+		ipAddr := ipMapping.IpAddress
 
-		log.Debug("load balancer endpoint found - Name: ", instanceName, ", Status: ", instanceStatus)
+		if ipMapping.Type == "PRIVATE" {
+			if csqlp.MatchPrivateIPs {
+				csqlResource.IPTypes[ipAddr] = ipMapping.Type
+			}
 
-		currentResource := generalResource.Resource{
-			Name:           instanceName,
-			Status:         instanceStatus,
-			CloudSvc:       "cloudsql",
-			AccountAliases: []string{csqlp.ProjectID},
+			continue
 		}
 
-		for _, ipAddrMap := range instanceIpAddrs {
-			ipAddr := ipAddrMap.IpAddress
+		ipVer, err := utils.DetermineIpAddrVersion(ipAddr)
+		if err != nil {
+			return csqlResource, err
+		}
 
-			ipVer, err := utils.DetermineIpAddrVersion(ipAddr)
+		switch ipVer {
+		case 4:
+			csqlResource.PublicIPv4Addrs = append(csqlResource.PublicIPv4Addrs, ipAddr)
+		case 6:
+			csqlResource.PublicIPv6Addrs = append(csqlResource.PublicIPv6Addrs, ipAddr)
+		default:
+			return csqlResource, fmt.Errorf("invalid IP version found for GCP Cloud SQL instance; IP: %s, Version: IPv%d", ipAddr, ipVer)
+		}
+
+		// PRIMARY IPs accept connections, while OUTGOING IPs are only used as the source of traffic from the instance
+		csqlResource.IPTypes[ipAddr] = ipMapping.Type
+	}
+
+	return csqlResource, nil
+}
+
+func (csqlp CloudSQLPlugin) GetResources() ([]CloudSQLResource, error) {
+	var csqlResources []CloudSQLResource
+
+	ctx := context.Background()
+
+	sqlAdminSvc, err := sqladmin.NewService(ctx, csqlp.GCPConn.ClientOptions...)
+	if err != nil {
+		return csqlResources, err
+	}
+
+	csqlInstListCall := sqlAdminSvc.Instances.List(csqlp.ProjectID)
+	if csqlp.Region != "" {
+		log.Debug("limiting Cloud SQL instance search to region ", csqlp.Region)
+		csqlInstListCall = csqlInstListCall.Filter("region:" + csqlp.Region)
+	}
+
+	err = csqlInstListCall.Pages(ctx, func(csqlInstListResp *sqladmin.InstancesListResponse) error {
+		for _, csqlInstance := range csqlInstListResp.Items {
+			csqlResource, err := csqlp.ProcessInstance(csqlInstance)
 			if err != nil {
-				return csqlResources, err
+				return err
 			}
 
-			switch ipVer {
-			case 4:
-				currentResource.PublicIPv4Addrs = append(currentResource.PublicIPv4Addrs, ipAddr)
-			case 6:
-				currentResource.PublicIPv6Addrs = append(currentResource.PublicIPv6Addrs, ipAddr)
-			default:
-				return csqlResources, fmt.Errorf("invalid IP version found for GCP CloudSQL instance; IP: %s, Version: IPv%d", ipAddr, ipVer)
-			}
+			log.Debug("Cloud SQL instance found - Name: ", csqlResource.Name, ", Status: ", csqlResource.Status, ", Connection Name: ", csqlResource.RID)
+
+			csqlResources = append(csqlResources, csqlResource)
 		}
 
-		csqlResources = append(
-			csqlResources,
-			currentResource,
-		)
+		return nil
+	})
+	if err != nil {
+		return csqlResources, err
 	}
 
 	return csqlResources, nil
 }
 
 func (csqlp CloudSQLPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
-	log.Debug("fetching and searching cloudsql resources")
+	log.Debug("fetching and searching Cloud SQL resources")
 
-	fetchedResources, err := csqlp.GetResources()
+	fetchedResources, err := inventorycache.Fetch(csqlp.InvCache, csqlp.GetCacheKey(), csqlp.GetResources)
 	if err != nil {
 		return *matchingResource, err
 	}
 
 	for _, csqlResource := range fetchedResources {
-		ridSlug := fmt.Sprintf("%s/%s", csqlResource.Id, csqlResource.Name)
+		if ipType, found := csqlResource.IPTypes[tgtIP]; found {
+			*matchingResource = csqlResource.Resource
+			matchingResource.IPType = ipType
 
-		for _, ipv4Addr := range csqlResource.PublicIPv4Addrs {
-			if ipv4Addr == tgtIP {
-				matchingResource.RID = ridSlug
-				matchingResource.CloudSvc = "cloud_sql"
+			log.Debug("IP found as Cloud SQL instance -> ", matchingResource.RID, " (IP type: ", ipType, ")")
 
-				break
-			}
+			break
 		}
+	}
 
-		for _, ipv6Addr := range csqlResource.PublicIPv6Addrs {
-			if ipv6Addr == tgtIP {
-				matchingResource.RID = ridSlug
-				matchingResource.CloudSvc = "cloud_sql"
+	return *matchingResource, nil
+}
 
-				break
-			}
-		}
+func (csqlp CloudSQLPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	var inventory []generalResource.Resource
 
-		if matchingResource.RID != "" {
-			log.Debug("IP found as CloudSQL instance -> ", matchingResource.RID)
+	fetchedResources, err := inventorycache.Fetch(csqlp.InvCache, csqlp.GetCacheKey(), csqlp.GetResources)
+	if err != nil {
+		return inventory, err
+	}
 
-			break
-		}
+	for _, csqlResource := range fetchedResources {
+		inventory = append(inventory, csqlResource.Resource)
 	}
 
-	return *matchingResource, nil
+	return inventory, nil
 }
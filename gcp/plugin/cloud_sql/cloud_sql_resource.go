@@ -0,0 +1,11 @@
+package cloud_sql
+
+import (
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type CloudSQLResource struct {
+	generalResource.Resource
+	// IP => IP type (PRIMARY, OUTGOING, or PRIVATE); private IPs are only tracked here since they aren't public
+	IPTypes map[string]string
+}
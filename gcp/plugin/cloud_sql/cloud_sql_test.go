@@ -1,9 +1,13 @@
 package cloud_sql_test
 
 import (
+	"fmt"
 	"reflect"
+	"slices"
 	"testing"
 
+	"google.golang.org/api/sqladmin/v1"
+
 	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/cloud_sql"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
@@ -14,12 +18,116 @@ func csqlPlugFactory() plugin.CloudSQLPlugin {
 	return csqlPlug
 }
 
+func csqlInstanceFactory() *sqladmin.DatabaseInstance {
+	return &sqladmin.DatabaseInstance{
+		Name:           "my-db",
+		Project:        "my-project",
+		Region:         "us-central1",
+		ConnectionName: "my-project:us-central1:my-db",
+		State:          "RUNNABLE",
+		IpAddresses: []*sqladmin.IpMapping{
+			{IpAddress: "34.1.2.3", Type: "PRIMARY"},
+			{IpAddress: "34.1.2.4", Type: "OUTGOING"},
+			{IpAddress: "10.10.0.3", Type: "PRIVATE"},
+		},
+	}
+}
+
+func TestGetConnectionName(t *testing.T) {
+	var tests = []struct {
+		csqlInstance           *sqladmin.DatabaseInstance
+		expectedConnectionName string
+	}{
+		{csqlInstanceFactory(), "my-project:us-central1:my-db"},
+		{&sqladmin.DatabaseInstance{Name: "old-db", Project: "my-project", Region: "europe-west1"}, "my-project:europe-west1:old-db"},
+	}
+
+	for _, td := range tests {
+		testName := td.csqlInstance.Name
+
+		t.Run(testName, func(t *testing.T) {
+			connectionName := plugin.GetConnectionName(td.csqlInstance)
+
+			if connectionName != td.expectedConnectionName {
+				t.Errorf("Generating Cloud SQL connection name failed; expected %s, received %s", td.expectedConnectionName, connectionName)
+			}
+		})
+	}
+}
+
+func TestGetCacheKey(t *testing.T) {
+	var tests = []struct {
+		matchPrivateIPs  bool
+		expectedCacheKey string
+	}{
+		{false, "cloud_sql/my-project/us-central1"},
+		{true, "cloud_sql/my-project/us-central1/private"},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("MatchPrivateIPs=%t", td.matchPrivateIPs)
+
+		t.Run(testName, func(t *testing.T) {
+			csqlPlug := plugin.CloudSQLPlugin{ProjectID: "my-project", Region: "us-central1", MatchPrivateIPs: td.matchPrivateIPs}
+
+			cacheKey := csqlPlug.GetCacheKey()
+			if cacheKey != td.expectedCacheKey {
+				t.Errorf("Generating Cloud SQL cache key failed; expected %s, received %s", td.expectedCacheKey, cacheKey)
+			}
+		})
+	}
+}
+
+func TestProcessInstance(t *testing.T) {
+	var tests = []struct {
+		matchPrivateIPs bool
+		expectedIPTypes map[string]string
+	}{
+		{false, map[string]string{"34.1.2.3": "PRIMARY", "34.1.2.4": "OUTGOING"}},
+		{true, map[string]string{"34.1.2.3": "PRIMARY", "34.1.2.4": "OUTGOING", "10.10.0.3": "PRIVATE"}},
+	}
+
+	for _, td := range tests {
+		testName := "private_ips_disabled"
+		if td.matchPrivateIPs {
+			testName = "private_ips_enabled"
+		}
+
+		t.Run(testName, func(t *testing.T) {
+			csqlPlug := plugin.CloudSQLPlugin{ProjectID: "my-project", MatchPrivateIPs: td.matchPrivateIPs}
+
+			csqlResource, err := csqlPlug.ProcessInstance(csqlInstanceFactory())
+			if err != nil {
+				t.Errorf("Processing Cloud SQL instance failed; received error: %s", err)
+			}
+
+			if csqlResource.RID != "my-project:us-central1:my-db" || csqlResource.CloudSvc != "cloud_sql" {
+				t.Errorf("Processing Cloud SQL instance failed; received RID %s for %s service", csqlResource.RID, csqlResource.CloudSvc)
+			}
+
+			if len(csqlResource.IPTypes) != len(td.expectedIPTypes) {
+				t.Errorf("Processing Cloud SQL instance failed; expected IP types %v, received %v", td.expectedIPTypes, csqlResource.IPTypes)
+			}
+			for ipAddr, expectedIPType := range td.expectedIPTypes {
+				if csqlResource.IPTypes[ipAddr] != expectedIPType {
+					t.Errorf("Processing Cloud SQL instance failed; expected %s to be %s IP, received %s", ipAddr, expectedIPType, csqlResource.IPTypes[ipAddr])
+				}
+			}
+
+			// private IPs should never be reported as public
+			if slices.Contains(csqlResource.PublicIPv4Addrs, "10.10.0.3") {
+				t.Errorf("Processing Cloud SQL instance failed; private IP included in public IPs: %s", csqlResource.PublicIPv4Addrs)
+			}
+		})
+	}
+}
+
 func TestGetResources(t *testing.T) {
 	csqlPlug := csqlPlugFactory()
 
 	csqlResources, _ := csqlPlug.GetResources()
 
-	expectedType := "Resource"
+	expectedType := "CloudSQLResource"
 	for _, resource := range csqlResources {
 		resourceType := reflect.TypeOf(resource)
 		if resourceType.Name() != expectedType {
@@ -55,3 +163,17 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceInventory(t *testing.T) {
+	csqlPlug := csqlPlugFactory()
+
+	inventory, _ := csqlPlug.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via GCP Cloud SQL Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
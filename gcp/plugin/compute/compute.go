@@ -3,19 +3,50 @@ package compute
 import (
 	"context"
 	"fmt"
+	"net"
+	"slices"
 	"strconv"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	gcpcomputeapi "cloud.google.com/go/compute/apiv1"
 	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
 	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
 
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	selflink "github.com/magneticstain/ip-2-cloudresource/gcp/self_link"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
+// GKE labels each node VM with the name of the cluster it belongs to
+const GKENodeLabel = "goog-k8s-cluster-name"
+
 type ComputePlugin struct {
+	GCPConn   gcpconnector.GCPConnector
+	InvCache  *inventorycache.InventoryCache
 	ProjectID string
+	Region    string
+	// also match internal IPs and alias IP ranges
+	MatchPrivateIPs bool
+}
+
+func GenerateZoneFilter(region string) string {
+	// aggregated lists can't be scoped to a region directly, but the zone URLs of each instance include it
+	// EX: https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a
+	if region == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("zone eq .*/zones/%s-[a-z]$", region)
+}
+
+func IsGKENode(instance *gcpcomputepbapi.Instance) bool {
+	_, found := instance.GetLabels()[GKENodeLabel]
+
+	return found
 }
 
 func CheckComputeIP(computeResource, matchingResource *generalResource.Resource, tgtIp string, ipVer int) (*generalResource.Resource, bool) {
@@ -31,47 +62,103 @@ func CheckComputeIP(computeResource, matchingResource *generalResource.Resource,
 		ipSet = computeResource.PublicIPv6Addrs
 	}
 
-	for _, ipAddr := range ipSet {
-		if ipAddr == tgtIp {
-			matchingResource.RID = fmt.Sprintf("%s/%s/%s", computeResource.AccountID, computeResource.Id, computeResource.Name)
-			matchingResource.CloudSvc = "compute"
+	if slices.Contains(ipSet, tgtIp) {
+		*matchingResource = *computeResource
+		matchingResource.CloudSvc = "compute"
 
-			found = true
+		found = true
+	}
 
-			break
+	return matchingResource, found
+}
+
+func GetPublicIPAddrsFromNetworkInterface(networkIface *gcpcomputepbapi.NetworkInterface) ([]string, []string) {
+	var publicIPv4Addrs, publicIPv6Addrs []string
+
+	// external IPv6 addresses are usually set in their own access configs, but we check both to be safe
+	accessConfigs := append(networkIface.GetAccessConfigs(), networkIface.GetIpv6AccessConfigs()...)
+	for _, accessConfig := range accessConfigs {
+		if accessConfig.GetNatIP() != "" {
+			publicIPv4Addrs = append(publicIPv4Addrs, accessConfig.GetNatIP())
+		}
+
+		if accessConfig.GetExternalIpv6() != "" {
+			publicIPv6Addrs = append(publicIPv6Addrs, accessConfig.GetExternalIpv6())
 		}
 	}
 
-	return matchingResource, found
+	return publicIPv4Addrs, publicIPv6Addrs
 }
 
 func GetPublicIPAddrsFromInstance(computeInstance *gcpcomputepbapi.Instance) ([]string, []string) {
 	var publicIPv4Addrs, publicIPv6Addrs []string
 
 	for _, networkIface := range computeInstance.GetNetworkInterfaces() {
-		for _, accessConfig := range networkIface.AccessConfigs {
-			if accessConfig.NatIP != nil {
-				publicIPv4Addrs = append(publicIPv4Addrs, *accessConfig.NatIP)
-			}
+		ifaceIPv4Addrs, ifaceIPv6Addrs := GetPublicIPAddrsFromNetworkInterface(networkIface)
 
-			if accessConfig.ExternalIpv6 != nil {
-				publicIPv6Addrs = append(publicIPv6Addrs, *accessConfig.ExternalIpv6)
-			}
-		}
+		publicIPv4Addrs = append(publicIPv4Addrs, ifaceIPv4Addrs...)
+		publicIPv6Addrs = append(publicIPv6Addrs, ifaceIPv6Addrs...)
 	}
 
 	return publicIPv4Addrs, publicIPv6Addrs
 }
 
-func (comp ComputePlugin) GetResources() ([]generalResource.Resource, error) {
+func AliasIPRangeContainsIP(aliasIPRange, tgtIP string) bool {
+	// alias ranges for a single IP may be listed without a prefix length
+	if !strings.Contains(aliasIPRange, "/") {
+		return aliasIPRange == tgtIP
+	}
+
+	_, aliasNet, err := net.ParseCIDR(aliasIPRange)
+	if err != nil {
+		return false
+	}
+
+	return aliasNet.Contains(net.ParseIP(tgtIP))
+}
+
+func MatchNetworkInterfaceIP(networkIface *gcpcomputepbapi.NetworkInterface, tgtIP string, matchPrivateIPs bool) (string, bool) {
+	// returns the type of IP that was matched on the interface
+	publicIPv4Addrs, publicIPv6Addrs := GetPublicIPAddrsFromNetworkInterface(networkIface)
+	if slices.Contains(publicIPv4Addrs, tgtIP) || slices.Contains(publicIPv6Addrs, tgtIP) {
+		return "external", true
+	}
+
+	if !matchPrivateIPs {
+		return "", false
+	}
+
+	if networkIface.GetNetworkIP() == tgtIP || networkIface.GetIpv6Address() == tgtIP {
+		return "internal", true
+	}
+
+	for _, aliasIPRange := range networkIface.GetAliasIpRanges() {
+		if AliasIPRangeContainsIP(aliasIPRange.GetIpCidrRange(), tgtIP) {
+			return "alias range", true
+		}
+	}
+
+	return "", false
+}
+
+func GenerateNetworkMap(networkIface *gcpcomputepbapi.NetworkInterface) []string {
+	// EX: nic0 -> my-subnet -> my-vpc
+	return []string{
+		networkIface.GetName(),
+		selflink.GetResourceName(networkIface.GetSubnetwork()),
+		selflink.GetResourceName(networkIface.GetNetwork()),
+	}
+}
+
+func (comp ComputePlugin) GetResources() ([]ComputeResource, error) {
 	var computeClient *gcpcomputeapi.InstancesClient
 	var instanceList *gcpcomputeapi.InstancesScopedListPairIterator
-	var computeResources []generalResource.Resource
+	var computeResources []ComputeResource
 
 	// REF: https://cloud.google.com/compute/docs/samples/compute-instances-list-all#compute_instances_list_all-go
 	ctx := context.Background()
 
-	computeClient, err := gcpcomputeapi.NewInstancesRESTClient(ctx)
+	computeClient, err := gcpcomputeapi.NewInstancesRESTClient(ctx, comp.GCPConn.ClientOptions...)
 	if err != nil {
 		return computeResources, err
 	}
@@ -80,6 +167,10 @@ func (comp ComputePlugin) GetResources() ([]generalResource.Resource, error) {
 	req := &gcpcomputepbapi.AggregatedListInstancesRequest{
 		Project: comp.ProjectID,
 	}
+	if comp.Region != "" {
+		log.Debug("limiting compute instance search to region ", comp.Region)
+		req.Filter = proto.String(GenerateZoneFilter(comp.Region))
+	}
 
 	// normally, we would just return the iterator and allow the search function to iterate through each
 	// however, for some reason, passing the iterator back to the search function results in a memory error when trying to read it
@@ -96,21 +187,33 @@ func (comp ComputePlugin) GetResources() ([]generalResource.Resource, error) {
 
 		instances := instanceListPair.Value.Instances
 		for _, instance := range instances {
+			if IsGKENode(instance) {
+				// node VMs are attributed to their cluster by the GKE plugin instead
+				log.Debug("skipping GKE node VM: ", instance.GetName())
+				continue
+			}
+
 			instanceId := strconv.FormatUint(instance.GetId(), 10)
 			instanceName := instance.GetName()
 			instanceStatus := instance.GetStatus()
+			instanceZone := selflink.GetResourceName(instance.GetZone())
 			publicIPv4Addrs, publicIPv6Addrs := GetPublicIPAddrsFromInstance(instance)
 
-			log.Debug("compute instance found - ID: ", instanceId, ", Name: ", instanceName, ", Status: ", instanceStatus)
-
-			currentResource := generalResource.Resource{
-				Id:              instanceId,
-				AccountID:       comp.ProjectID,
-				Name:            instanceName,
-				Status:          instanceStatus,
-				CloudSvc:        "compute",
-				PublicIPv4Addrs: publicIPv4Addrs,
-				PublicIPv6Addrs: publicIPv6Addrs,
+			log.Debug("compute instance found - ID: ", instanceId, ", Name: ", instanceName, ", Status: ", instanceStatus, ", Zone: ", instanceZone)
+
+			currentResource := ComputeResource{
+				Resource: generalResource.Resource{
+					Id:              instanceId,
+					RID:             instance.GetSelfLink(),
+					AccountID:       comp.ProjectID,
+					Name:            instanceName,
+					Status:          instanceStatus,
+					CloudSvc:        "compute",
+					Location:        instanceZone,
+					PublicIPv4Addrs: publicIPv4Addrs,
+					PublicIPv6Addrs: publicIPv6Addrs,
+				},
+				NetworkInterfaces: instance.GetNetworkInterfaces(),
 			}
 
 			computeResources = append(
@@ -126,27 +229,43 @@ func (comp ComputePlugin) GetResources() ([]generalResource.Resource, error) {
 func (comp ComputePlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
 	log.Debug("fetching and searching compute resources")
 
-	fetchedResources, err := comp.GetResources()
+	fetchedResources, err := inventorycache.Fetch(comp.InvCache, "compute/"+comp.ProjectID+"/"+comp.Region, comp.GetResources)
 	if err != nil {
 		return *matchingResource, err
 	}
 
-	var found bool
 	for _, computeResource := range fetchedResources {
-		// IPv4 is checked first
-		matchingResource, found = CheckComputeIP(&computeResource, matchingResource, tgtIP, 4)
+		for _, networkIface := range computeResource.NetworkInterfaces {
+			ipType, found := MatchNetworkInterfaceIP(networkIface, tgtIP, comp.MatchPrivateIPs)
+			if !found {
+				continue
+			}
 
-		if !found {
-			// check IPv6 next
-			matchingResource, found = CheckComputeIP(&computeResource, matchingResource, tgtIP, 6)
-		}
+			*matchingResource = computeResource.Resource
+			matchingResource.IPType = ipType
+			// the interface's network info is already on hand, so there's no reason to hold it back for network mapping only
+			matchingResource.NetworkMap = GenerateNetworkMap(networkIface)
 
-		if found {
-			log.Debug("IP found as Compute VM -> ", matchingResource.RID)
+			log.Debug("IP found as Compute VM -> ", matchingResource.RID, " in zone ", computeResource.Location, " with network info ", matchingResource.NetworkMap)
 
-			break
+			return *matchingResource, nil
 		}
 	}
 
 	return *matchingResource, nil
 }
+
+func (comp ComputePlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	var inventory []generalResource.Resource
+
+	fetchedResources, err := inventorycache.Fetch(comp.InvCache, "compute/"+comp.ProjectID+"/"+comp.Region, comp.GetResources)
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, computeResource := range fetchedResources {
+		inventory = append(inventory, computeResource.Resource)
+	}
+
+	return inventory, nil
+}
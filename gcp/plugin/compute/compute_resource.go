@@ -0,0 +1,13 @@
+package compute
+
+import (
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type ComputeResource struct {
+	generalResource.Resource
+	// kept as-is so we can report the network and subnetwork of whichever interface the IP matched on
+	NetworkInterfaces []*gcpcomputepbapi.NetworkInterface
+}
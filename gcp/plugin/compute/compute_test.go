@@ -2,8 +2,12 @@ package compute_test
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/protobuf/proto"
+
 	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/compute"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
@@ -14,6 +18,50 @@ func compPlugFactory() plugin.ComputePlugin {
 	return compPlug
 }
 
+func TestGenerateZoneFilter(t *testing.T) {
+	var tests = []struct {
+		region, expectedFilter string
+	}{
+		{"us-central1", "zone eq .*/zones/us-central1-[a-z]$"},
+		{"europe-west4", "zone eq .*/zones/europe-west4-[a-z]$"},
+		{"", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.region
+
+		t.Run(testName, func(t *testing.T) {
+			filter := plugin.GenerateZoneFilter(td.region)
+
+			if filter != td.expectedFilter {
+				t.Errorf("Generating compute zone filter failed; expected %s, received %s", td.expectedFilter, filter)
+			}
+		})
+	}
+}
+
+func TestIsGKENode(t *testing.T) {
+	var tests = []struct {
+		name           string
+		instance       *gcpcomputepbapi.Instance
+		expectedResult bool
+	}{
+		{"gke-node", &gcpcomputepbapi.Instance{Labels: map[string]string{plugin.GKENodeLabel: "my-cluster"}}, true},
+		{"labeled-vm", &gcpcomputepbapi.Instance{Labels: map[string]string{"env": "prod"}}, false},
+		{"unlabeled-vm", &gcpcomputepbapi.Instance{}, false},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			isNode := plugin.IsGKENode(td.instance)
+
+			if isNode != td.expectedResult {
+				t.Errorf("GKE node check failed; expected %t, received %t", td.expectedResult, isNode)
+			}
+		})
+	}
+}
+
 func TestCheckComputeIP_ValidIPv4s(t *testing.T) {
 	var tests = []struct {
 		ipAddr, tgtIp string
@@ -95,12 +143,106 @@ func TestCheckComputeIP_InvalidIPs(t *testing.T) {
 	}
 }
 
+func networkIfaceFactory() *gcpcomputepbapi.NetworkInterface {
+	selfLinkPrefix := "https://www.googleapis.com/compute/v1/projects/my-project/"
+
+	return &gcpcomputepbapi.NetworkInterface{
+		Name:        proto.String("nic0"),
+		Network:     proto.String(selfLinkPrefix + "global/networks/my-vpc"),
+		Subnetwork:  proto.String(selfLinkPrefix + "regions/us-central1/subnetworks/my-subnet"),
+		NetworkIP:   proto.String("10.128.0.5"),
+		Ipv6Address: proto.String("fd20:a:b:c::5"),
+		AccessConfigs: []*gcpcomputepbapi.AccessConfig{
+			{NatIP: proto.String("34.1.2.3")},
+		},
+		Ipv6AccessConfigs: []*gcpcomputepbapi.AccessConfig{
+			{ExternalIpv6: proto.String("2600:1900:4000:1::")},
+		},
+		AliasIpRanges: []*gcpcomputepbapi.AliasIpRange{
+			{IpCidrRange: proto.String("10.4.0.0/24")},
+			{IpCidrRange: proto.String("10.8.0.9")},
+		},
+	}
+}
+
+func TestGetPublicIPAddrsFromNetworkInterface(t *testing.T) {
+	publicIPv4Addrs, publicIPv6Addrs := plugin.GetPublicIPAddrsFromNetworkInterface(networkIfaceFactory())
+
+	if !slices.Equal(publicIPv4Addrs, []string{"34.1.2.3"}) || !slices.Equal(publicIPv6Addrs, []string{"2600:1900:4000:1::"}) {
+		t.Errorf("Gathering public IPs from network interface failed; received IPv4 IPs %s and IPv6 IPs %s", publicIPv4Addrs, publicIPv6Addrs)
+	}
+}
+
+func TestAliasIPRangeContainsIP(t *testing.T) {
+	var tests = []struct {
+		aliasIPRange, tgtIP string
+		expected            bool
+	}{
+		{"10.4.0.0/24", "10.4.0.17", true},
+		{"10.4.0.0/24", "10.4.1.17", false},
+		{"10.8.0.9", "10.8.0.9", true},
+		{"10.8.0.9/32", "10.8.0.9", true},
+		{"10.8.0.9", "10.8.0.10", false},
+		{"10.4.0.0/99", "10.4.0.17", false},
+	}
+
+	for _, td := range tests {
+		testName := td.aliasIPRange + "_" + td.tgtIP
+
+		t.Run(testName, func(t *testing.T) {
+			found := plugin.AliasIPRangeContainsIP(td.aliasIPRange, td.tgtIP)
+
+			if found != td.expected {
+				t.Errorf("Checking alias IP range for IP failed; expected %t, received %t", td.expected, found)
+			}
+		})
+	}
+}
+
+func TestMatchNetworkInterfaceIP(t *testing.T) {
+	var tests = []struct {
+		tgtIP, expectedIPType string
+		matchPrivateIPs       bool
+		expectedFound         bool
+	}{
+		{"34.1.2.3", "external", false, true},
+		{"2600:1900:4000:1::", "external", false, true},
+		{"10.128.0.5", "", false, false},
+		{"10.128.0.5", "internal", true, true},
+		{"fd20:a:b:c::5", "internal", true, true},
+		{"10.4.0.200", "alias range", true, true},
+		{"10.8.0.9", "alias range", true, true},
+		{"1.1.1.1", "", true, false},
+	}
+
+	for _, td := range tests {
+		testName := td.tgtIP
+
+		t.Run(testName, func(t *testing.T) {
+			ipType, found := plugin.MatchNetworkInterfaceIP(networkIfaceFactory(), td.tgtIP, td.matchPrivateIPs)
+
+			if found != td.expectedFound || ipType != td.expectedIPType {
+				t.Errorf("Matching network interface IP failed; expected %t (%s), received %t (%s)", td.expectedFound, td.expectedIPType, found, ipType)
+			}
+		})
+	}
+}
+
+func TestGenerateNetworkMap(t *testing.T) {
+	networkMap := plugin.GenerateNetworkMap(networkIfaceFactory())
+
+	expectedNetworkMap := []string{"nic0", "my-subnet", "my-vpc"}
+	if !slices.Equal(networkMap, expectedNetworkMap) {
+		t.Errorf("Generating compute network map failed; expected %s, received %s", expectedNetworkMap, networkMap)
+	}
+}
+
 func TestGetResources(t *testing.T) {
 	computePlug := compPlugFactory()
 
 	computeResources, _ := computePlug.GetResources()
 
-	expectedType := "Resource"
+	expectedType := "ComputeResource"
 	for _, resource := range computeResources {
 		resourceType := reflect.TypeOf(resource)
 		if resourceType.Name() != expectedType {
@@ -136,3 +278,17 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceInventory(t *testing.T) {
+	compPlug := compPlugFactory()
+
+	inventory, _ := compPlug.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via GCP Compute Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package gke
+
+/*
+DEV NOTE:
+---
+GKE creates the LBs for k8s Services (type: LoadBalancer) and Ingresses on the cluster's behalf, so the LB itself only tells half of the story. To get the rest, we connect to each cluster's control plane with the same ADC identity used everywhere else and look for the Service or Ingress that owns the IP. If the identity doesn't have access to a cluster, we just skip that part of the search for it.
+
+https://cloud.google.com/kubernetes-engine/docs/how-to/api-server-authentication
+*/
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	gcpcomputeapi "cloud.google.com/go/compute/apiv1"
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	gcpcontainerapi "cloud.google.com/go/container/apiv1"
+	gcpcontainerpbapi "cloud.google.com/go/container/apiv1/containerpb"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/compute"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// cluster control planes that aren't reachable from where we're running would otherwise hang the search
+const k8sAPITimeout = 30 * time.Second
+
+type GKEPlugin struct {
+	GCPConn   gcpconnector.GCPConnector
+	InvCache  *inventorycache.InventoryCache
+	ProjectID string
+}
+
+func GetClusterPath(cluster *gcpcontainerpbapi.Cluster, projectID string) string {
+	// EX: projects/my-project/locations/us-central1/clusters/my-cluster
+	return fmt.Sprintf("projects/%s/locations/%s/clusters/%s", projectID, cluster.GetLocation(), cluster.GetName())
+}
+
+func GetClusterEndpoints(cluster *gcpcontainerpbapi.Cluster) []string {
+	var endpoints []string
+
+	// private clusters may list their public endpoint separately from the primary one
+	for _, endpoint := range []string{cluster.GetEndpoint(), cluster.GetPrivateClusterConfig().GetPublicEndpoint()} {
+		if endpoint != "" && !slices.Contains(endpoints, endpoint) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	return endpoints
+}
+
+func GetPublicEndpoint(cluster *gcpcontainerpbapi.Cluster) string {
+	// the primary endpoint of a private cluster is its private IP, so only the public endpoint listed in its private cluster config is public
+	if cluster.GetPrivateClusterConfig() != nil {
+		return cluster.GetPrivateClusterConfig().GetPublicEndpoint()
+	}
+
+	return cluster.GetEndpoint()
+}
+
+func GetK8sAPIEndpoint(cluster *gcpcontainerpbapi.Cluster) string {
+	// the primary endpoint of a private cluster is its private one, which usually isn't reachable from where we're running
+	if publicEndpoint := cluster.GetPrivateClusterConfig().GetPublicEndpoint(); publicEndpoint != "" {
+		return publicEndpoint
+	}
+
+	return cluster.GetEndpoint()
+}
+
+func LoadBalancerStatusHasIP(lbStatus []corev1.LoadBalancerIngress, tgtIP string) bool {
+	return slices.ContainsFunc(lbStatus, func(lbIngress corev1.LoadBalancerIngress) bool { return lbIngress.IP == tgtIP })
+}
+
+func ServiceHasIP(svc corev1.Service, tgtIP string) bool {
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer && len(svc.Spec.ExternalIPs) == 0 {
+		return false
+	}
+
+	return LoadBalancerStatusHasIP(svc.Status.LoadBalancer.Ingress, tgtIP) || slices.Contains(svc.Spec.ExternalIPs, tgtIP)
+}
+
+func IngressHasIP(ingress networkingv1.Ingress, tgtIP string) bool {
+	// ingress statuses use their own type, but it has the same shape as the service one
+	for _, lbIngress := range ingress.Status.LoadBalancer.Ingress {
+		if lbIngress.IP == tgtIP {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewK8sClient(ctx context.Context, gcpConn gcpconnector.GCPConnector, cluster *gcpcontainerpbapi.Cluster) (*kubernetes.Clientset, error) {
+	caCert, err := base64.StdEncoding.DecodeString(cluster.GetMasterAuth().GetClusterCaCertificate())
+	if err != nil {
+		return nil, err
+	}
+
+	tokenSrc, err := gcpConn.GetTokenSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sConfig := &rest.Config{
+		Host:            "https://" + GetK8sAPIEndpoint(cluster),
+		TLSClientConfig: rest.TLSClientConfig{CAData: caCert},
+		Timeout:         k8sAPITimeout,
+		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
+			return &oauth2.Transport{Source: tokenSrc, Base: rt}
+		},
+	}
+
+	return kubernetes.NewForConfig(k8sConfig)
+}
+
+func ListK8sObjects(ctx context.Context, k8sClient kubernetes.Interface) (K8sObjects, error) {
+	var k8sObjects K8sObjects
+
+	svcs, err := k8sClient.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return k8sObjects, err
+	}
+	k8sObjects.Services = svcs.Items
+
+	ingresses, err := k8sClient.NetworkingV1().Ingresses(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return k8sObjects, err
+	}
+	k8sObjects.Ingresses = ingresses.Items
+
+	return k8sObjects, nil
+}
+
+func FindK8sObjectByIP(k8sObjects K8sObjects, tgtIP string) (string, string, string) {
+	// returns the kind, namespace, and name of the matching object
+	for _, svc := range k8sObjects.Services {
+		if ServiceHasIP(svc, tgtIP) {
+			return "Service", svc.Namespace, svc.Name
+		}
+	}
+
+	for _, ingress := range k8sObjects.Ingresses {
+		if IngressHasIP(ingress, tgtIP) {
+			return "Ingress", ingress.Namespace, ingress.Name
+		}
+	}
+
+	return "", "", ""
+}
+
+func (gkep GKEPlugin) GetClusters(ctx context.Context) ([]*gcpcontainerpbapi.Cluster, error) {
+	var clusters []*gcpcontainerpbapi.Cluster
+
+	clusterClient, err := gcpcontainerapi.NewClusterManagerClient(ctx, gkep.GCPConn.ClientOptions...)
+	if err != nil {
+		return clusters, err
+	}
+	defer clusterClient.Close()
+
+	// the "-" location returns clusters from all zones and regions
+	clusterList, err := clusterClient.ListClusters(ctx, &gcpcontainerpbapi.ListClustersRequest{
+		Parent: fmt.Sprintf("projects/%s/locations/-", gkep.ProjectID),
+	})
+	if err != nil {
+		return clusters, err
+	}
+
+	if len(clusterList.GetMissingZones()) > 0 {
+		log.Warn("unable to list GKE clusters in some zones: ", clusterList.GetMissingZones())
+	}
+
+	return clusterList.GetClusters(), nil
+}
+
+func (gkep GKEPlugin) GetNodeIPAddrs(ctx context.Context) (map[string][]string, error) {
+	// cluster name => node IPs
+	nodeIPAddrs := make(map[string][]string)
+
+	computeClient, err := gcpcomputeapi.NewInstancesRESTClient(ctx, gkep.GCPConn.ClientOptions...)
+	if err != nil {
+		return nodeIPAddrs, err
+	}
+	defer computeClient.Close()
+
+	req := &gcpcomputepbapi.AggregatedListInstancesRequest{
+		Project:              gkep.ProjectID,
+		Filter:               proto.String(fmt.Sprintf("labels.%s eq .*", compute.GKENodeLabel)),
+		ReturnPartialSuccess: proto.Bool(true),
+	}
+
+	instanceList := computeClient.AggregatedList(ctx, req)
+	for {
+		instanceListPair, err := instanceList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return nodeIPAddrs, err
+		}
+
+		for _, instance := range instanceListPair.Value.GetInstances() {
+			clusterName := instance.GetLabels()[compute.GKENodeLabel]
+			publicIPv4Addrs, publicIPv6Addrs := compute.GetPublicIPAddrsFromInstance(instance)
+
+			nodeIPAddrs[clusterName] = append(nodeIPAddrs[clusterName], publicIPv4Addrs...)
+			nodeIPAddrs[clusterName] = append(nodeIPAddrs[clusterName], publicIPv6Addrs...)
+		}
+	}
+
+	return nodeIPAddrs, nil
+}
+
+func (gkep GKEPlugin) ProcessCluster(cluster *gcpcontainerpbapi.Cluster) generalResource.Resource {
+	return generalResource.Resource{
+		Id:        cluster.GetId(),
+		RID:       GetClusterPath(cluster, gkep.ProjectID),
+		AccountID: gkep.ProjectID,
+		Name:      cluster.GetName(),
+		Status:    cluster.GetStatus().String(),
+		CloudSvc:  "gke",
+	}
+}
+
+func (gkep GKEPlugin) SearchK8sObjects(ctx context.Context, cluster *gcpcontainerpbapi.Cluster, tgtIP string, matchingResource *generalResource.Resource) (bool, error) {
+	// bulk searches would otherwise list every object in every cluster once per IP
+	k8sObjects, err := inventorycache.Fetch(gkep.InvCache, "gke/k8s/"+GetClusterPath(cluster, gkep.ProjectID), func() (K8sObjects, error) {
+		k8sClient, err := NewK8sClient(ctx, gkep.GCPConn, cluster)
+		if err != nil {
+			return K8sObjects{}, err
+		}
+
+		return ListK8sObjects(ctx, k8sClient)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	kind, namespace, name := FindK8sObjectByIP(k8sObjects, tgtIP)
+	if kind == "" {
+		return false, nil
+	}
+
+	*matchingResource = gkep.ProcessCluster(cluster)
+	matchingResource.RID = fmt.Sprintf("%s/namespaces/%s/%ss/%s", matchingResource.RID, namespace, strings.ToLower(kind), name)
+	matchingResource.Name = fmt.Sprintf("%s/%s", namespace, name)
+	matchingResource.IPType = kind + " load balancer"
+	matchingResource.NetworkMap = []string{cluster.GetName(), fmt.Sprintf("%s: %s/%s", kind, namespace, name)}
+
+	return true, nil
+}
+
+func (gkep GKEPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
+	log.Debug("fetching and searching GKE resources")
+
+	ctx := context.Background()
+
+	clusters, err := inventorycache.Fetch(gkep.InvCache, "gke/clusters/"+gkep.ProjectID, func() ([]*gcpcontainerpbapi.Cluster, error) {
+		return gkep.GetClusters(ctx)
+	})
+	if err != nil || len(clusters) == 0 {
+		return *matchingResource, err
+	}
+
+	for _, cluster := range clusters {
+		log.Debug("GKE cluster found - Name: ", cluster.GetName(), ", Location: ", cluster.GetLocation(), ", Status: ", cluster.GetStatus())
+
+		if slices.Contains(GetClusterEndpoints(cluster), tgtIP) {
+			*matchingResource = gkep.ProcessCluster(cluster)
+			matchingResource.IPType = "control plane"
+
+			log.Debug("IP found as GKE control plane -> ", matchingResource.RID)
+
+			return *matchingResource, nil
+		}
+	}
+
+	nodeIPAddrs, err := inventorycache.Fetch(gkep.InvCache, "gke/nodes/"+gkep.ProjectID, func() (map[string][]string, error) {
+		return gkep.GetNodeIPAddrs(ctx)
+	})
+	if err != nil {
+		return *matchingResource, err
+	}
+
+	for _, cluster := range clusters {
+		if slices.Contains(nodeIPAddrs[cluster.GetName()], tgtIP) {
+			*matchingResource = gkep.ProcessCluster(cluster)
+			matchingResource.IPType = "node"
+
+			log.Debug("IP found as GKE node -> ", matchingResource.RID)
+
+			return *matchingResource, nil
+		}
+	}
+
+	for _, cluster := range clusters {
+		found, err := gkep.SearchK8sObjects(ctx, cluster, tgtIP, matchingResource)
+		if err != nil {
+			// missing cluster credentials shouldn't stop us from searching the other clusters or services
+			log.Warn("unable to search k8s objects in GKE cluster [ ", cluster.GetName(), " ]: ", err)
+			continue
+		} else if found {
+			log.Debug("IP found as GKE ", matchingResource.IPType, " -> ", matchingResource.RID)
+
+			break
+		}
+	}
+
+	return *matchingResource, nil
+}
+
+func (gkep GKEPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	// includes each cluster's public control plane endpoint and node IPs; Kubernetes load balancer IPs aren't included since listing them requires access to every cluster
+	var inventory []generalResource.Resource
+
+	ctx := context.Background()
+
+	clusters, err := inventorycache.Fetch(gkep.InvCache, "gke/clusters/"+gkep.ProjectID, func() ([]*gcpcontainerpbapi.Cluster, error) {
+		return gkep.GetClusters(ctx)
+	})
+	if err != nil || len(clusters) == 0 {
+		return inventory, err
+	}
+
+	nodeIPAddrs, err := inventorycache.Fetch(gkep.InvCache, "gke/nodes/"+gkep.ProjectID, func() (map[string][]string, error) {
+		return gkep.GetNodeIPAddrs(ctx)
+	})
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, cluster := range clusters {
+		currentResource := gkep.ProcessCluster(cluster)
+
+		for _, ipAddr := range append([]string{GetPublicEndpoint(cluster)}, nodeIPAddrs[cluster.GetName()]...) {
+			ipVer, err := utils.DetermineIpAddrVersion(ipAddr)
+			if err != nil {
+				continue
+			}
+
+			if ipVer == 4 {
+				currentResource.PublicIPv4Addrs = append(currentResource.PublicIPv4Addrs, ipAddr)
+			} else {
+				currentResource.PublicIPv6Addrs = append(currentResource.PublicIPv6Addrs, ipAddr)
+			}
+		}
+
+		inventory = append(inventory, currentResource)
+	}
+
+	return inventory, nil
+}
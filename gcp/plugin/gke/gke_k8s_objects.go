@@ -0,0 +1,12 @@
+package gke
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// the k8s objects in a cluster that can own a load balancer IP
+type K8sObjects struct {
+	Services  []corev1.Service
+	Ingresses []networkingv1.Ingress
+}
@@ -0,0 +1,272 @@
+package gke_test
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	gcpcontainerpbapi "cloud.google.com/go/container/apiv1/containerpb"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/gke"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func gkePlugFactory() plugin.GKEPlugin {
+	gkePlug := plugin.GKEPlugin{ProjectID: "my-project"}
+
+	return gkePlug
+}
+
+func lbServiceFactory(namespace, name, ipAddr string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: ipAddr}}},
+		},
+	}
+}
+
+func ingressFactory(namespace, name, ipAddr string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status: networkingv1.IngressStatus{
+			LoadBalancer: networkingv1.IngressLoadBalancerStatus{Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: ipAddr}}},
+		},
+	}
+}
+
+func TestGetClusterPath(t *testing.T) {
+	cluster := &gcpcontainerpbapi.Cluster{Name: "my-cluster", Location: "us-central1"}
+
+	clusterPath := plugin.GetClusterPath(cluster, "my-project")
+
+	expectedClusterPath := "projects/my-project/locations/us-central1/clusters/my-cluster"
+	if clusterPath != expectedClusterPath {
+		t.Errorf("Generating GKE cluster path failed; expected %s, received %s", expectedClusterPath, clusterPath)
+	}
+}
+
+func TestGetClusterEndpoints(t *testing.T) {
+	var tests = []struct {
+		cluster           *gcpcontainerpbapi.Cluster
+		expectedEndpoints []string
+	}{
+		{&gcpcontainerpbapi.Cluster{Name: "public", Endpoint: "34.1.2.3"}, []string{"34.1.2.3"}},
+		{
+			&gcpcontainerpbapi.Cluster{
+				Name:                 "private",
+				Endpoint:             "10.0.0.2",
+				PrivateClusterConfig: &gcpcontainerpbapi.PrivateClusterConfig{PrivateEndpoint: "10.0.0.2", PublicEndpoint: "34.1.2.4"},
+			},
+			[]string{"10.0.0.2", "34.1.2.4"},
+		},
+		{
+			&gcpcontainerpbapi.Cluster{
+				Name:                 "duplicate",
+				Endpoint:             "34.1.2.5",
+				PrivateClusterConfig: &gcpcontainerpbapi.PrivateClusterConfig{PublicEndpoint: "34.1.2.5"},
+			},
+			[]string{"34.1.2.5"},
+		},
+	}
+
+	for _, td := range tests {
+		testName := td.cluster.GetName()
+
+		t.Run(testName, func(t *testing.T) {
+			endpoints := plugin.GetClusterEndpoints(td.cluster)
+
+			if !slices.Equal(endpoints, td.expectedEndpoints) {
+				t.Errorf("Gathering GKE cluster endpoints failed; expected %s, received %s", td.expectedEndpoints, endpoints)
+			}
+		})
+	}
+}
+
+func TestGetPublicEndpoint(t *testing.T) {
+	var tests = []struct {
+		cluster          *gcpcontainerpbapi.Cluster
+		expectedEndpoint string
+	}{
+		{&gcpcontainerpbapi.Cluster{Name: "public", Endpoint: "34.1.2.3"}, "34.1.2.3"},
+		{
+			&gcpcontainerpbapi.Cluster{
+				Name:                 "private",
+				Endpoint:             "10.0.0.2",
+				PrivateClusterConfig: &gcpcontainerpbapi.PrivateClusterConfig{PrivateEndpoint: "10.0.0.2", PublicEndpoint: "34.1.2.4"},
+			},
+			"34.1.2.4",
+		},
+		{
+			&gcpcontainerpbapi.Cluster{
+				Name:                 "private-only",
+				Endpoint:             "10.0.0.3",
+				PrivateClusterConfig: &gcpcontainerpbapi.PrivateClusterConfig{PrivateEndpoint: "10.0.0.3"},
+			},
+			"",
+		},
+	}
+
+	for _, td := range tests {
+		testName := td.cluster.GetName()
+
+		t.Run(testName, func(t *testing.T) {
+			endpoint := plugin.GetPublicEndpoint(td.cluster)
+
+			if endpoint != td.expectedEndpoint {
+				t.Errorf("Selecting GKE public endpoint failed; expected %s, received %s", td.expectedEndpoint, endpoint)
+			}
+		})
+	}
+}
+
+func TestGetK8sAPIEndpoint(t *testing.T) {
+	var tests = []struct {
+		cluster          *gcpcontainerpbapi.Cluster
+		expectedEndpoint string
+	}{
+		{&gcpcontainerpbapi.Cluster{Name: "public", Endpoint: "34.1.2.3"}, "34.1.2.3"},
+		{
+			&gcpcontainerpbapi.Cluster{
+				Name:                 "private",
+				Endpoint:             "10.0.0.2",
+				PrivateClusterConfig: &gcpcontainerpbapi.PrivateClusterConfig{PrivateEndpoint: "10.0.0.2", PublicEndpoint: "34.1.2.4"},
+			},
+			"34.1.2.4",
+		},
+		{
+			&gcpcontainerpbapi.Cluster{
+				Name:                 "private-only",
+				Endpoint:             "10.0.0.3",
+				PrivateClusterConfig: &gcpcontainerpbapi.PrivateClusterConfig{PrivateEndpoint: "10.0.0.3"},
+			},
+			"10.0.0.3",
+		},
+	}
+
+	for _, td := range tests {
+		testName := td.cluster.GetName()
+
+		t.Run(testName, func(t *testing.T) {
+			endpoint := plugin.GetK8sAPIEndpoint(td.cluster)
+
+			if endpoint != td.expectedEndpoint {
+				t.Errorf("Selecting GKE API endpoint failed; expected %s, received %s", td.expectedEndpoint, endpoint)
+			}
+		})
+	}
+}
+
+func TestServiceHasIP(t *testing.T) {
+	clusterIPSvc := corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "10.4.0.10"}}
+	externalIPSvc := corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ExternalIPs: []string{"35.1.2.3"}}}
+
+	var tests = []struct {
+		name, ipAddr string
+		svc          corev1.Service
+		expected     bool
+	}{
+		{"lb_match", "34.1.2.3", *lbServiceFactory("default", "web", "34.1.2.3"), true},
+		{"lb_no_match", "34.1.2.4", *lbServiceFactory("default", "web", "34.1.2.3"), false},
+		{"cluster_ip", "10.4.0.10", clusterIPSvc, false},
+		{"external_ip", "35.1.2.3", externalIPSvc, true},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			found := plugin.ServiceHasIP(td.svc, td.ipAddr)
+
+			if found != td.expected {
+				t.Errorf("Checking k8s service for IP failed; expected %t, received %t", td.expected, found)
+			}
+		})
+	}
+}
+
+func TestFindK8sObjectByIP(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(
+		lbServiceFactory("default", "web", "34.1.2.3"),
+		lbServiceFactory("payments", "api", "34.1.2.4"),
+		ingressFactory("storefront", "shop", "34.1.2.5"),
+	)
+
+	k8sObjects, err := plugin.ListK8sObjects(context.Background(), k8sClient)
+	if err != nil {
+		t.Fatalf("Listing k8s objects failed; received error: %s", err)
+	}
+
+	var tests = []struct {
+		ipAddr, expectedKind, expectedNamespace, expectedName string
+	}{
+		{"34.1.2.3", "Service", "default", "web"},
+		{"34.1.2.4", "Service", "payments", "api"},
+		{"34.1.2.5", "Ingress", "storefront", "shop"},
+		{"1.1.1.1", "", "", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			kind, namespace, name := plugin.FindK8sObjectByIP(k8sObjects, td.ipAddr)
+
+			if kind != td.expectedKind || namespace != td.expectedNamespace || name != td.expectedName {
+				t.Errorf(
+					"Searching k8s objects for IP failed; expected %s %s/%s, received %s %s/%s",
+					td.expectedKind, td.expectedNamespace, td.expectedName,
+					kind, namespace, name,
+				)
+			}
+		})
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	gkePlug := gkePlugFactory()
+
+	var tests = []struct {
+		ipAddr string
+	}{
+		{"1.1.1.1"},
+		{"1234.45.9666.1"},
+		{"18.161.22.61"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21"},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			var matchingResource generalResource.Resource
+
+			res, _ := gkePlug.SearchResources(td.ipAddr, &matchingResource)
+
+			expectedType := "Resource"
+			resType := reflect.TypeOf(res)
+			if resType.Name() != expectedType {
+				t.Errorf("GKE search failed; expected %s after search, received %s", expectedType, resType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	gkePlug := gkePlugFactory()
+
+	inventory, _ := gkePlug.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via GCP GKE Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
@@ -3,84 +3,282 @@ package load_balancing
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	gcpcomputeapi "cloud.google.com/go/compute/apiv1"
 	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
 	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
 
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	selflink "github.com/magneticstain/ip-2-cloudresource/gcp/self_link"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type LoadBalancingPlugin struct {
-	ProjectID string
+	GCPConn        gcpconnector.GCPConnector
+	InvCache       *inventorycache.InventoryCache
+	NetworkMapping bool
+	ProjectID      string
 }
 
-func (lbp LoadBalancingPlugin) GetResources() ([]generalResource.Resource, error) {
-	var gaClient *gcpcomputeapi.GlobalAddressesClient
-	var lbGlobalAddrList *gcpcomputeapi.AddressIterator
-	var lbResources []generalResource.Resource
+func AddIPAddrToResource(lbResource *LoadBalancingResource, ipAddr string) error {
+	// for some god-awful reason, no value is being returned when calling the addr.GetIpVersion() method
+	// as such, we will need to determine it ourselves :(
+	ipVer, err := utils.DetermineIpAddrVersion(ipAddr)
+	if err != nil {
+		return err
+	}
 
-	ctx := context.Background()
+	switch ipVer {
+	case 4:
+		lbResource.PublicIPv4Addrs = append(lbResource.PublicIPv4Addrs, ipAddr)
+	case 6:
+		lbResource.PublicIPv6Addrs = append(lbResource.PublicIPv6Addrs, ipAddr)
+	default:
+		return fmt.Errorf("invalid IP version found for GCP LB; IP: %s, Version: IPv%d", ipAddr, ipVer)
+	}
+
+	return nil
+}
+
+func GenerateNetworkMap(lbResource LoadBalancingResource) []string {
+	// EX: my-fwd-rule -> my-target-proxy
+	var networkMap []string
+
+	for _, lbLink := range []string{lbResource.ForwardingRule, lbResource.Target} {
+		if lbLink != "" {
+			networkMap = append(networkMap, selflink.GetResourceName(lbLink))
+		}
+	}
+
+	return networkMap
+}
+
+func (lbp LoadBalancingPlugin) ProcessForwardingRule(fwdRule *gcpcomputepbapi.ForwardingRule, region string) (LoadBalancingResource, bool, error) {
+	var lbResource LoadBalancingResource
 
-	gaClient, err := gcpcomputeapi.NewGlobalAddressesRESTClient(ctx)
+	// internal LBs only ever have private IPs
+	if strings.HasPrefix(fwdRule.GetLoadBalancingScheme(), "INTERNAL") || fwdRule.GetIPAddress() == "" {
+		return lbResource, false, nil
+	}
+
+	// classic VPN gateways receive traffic via forwarding rules too, but those are covered by the vpn_gateway plugin
+	if selflink.GetResourceType(fwdRule.GetTarget()) == "targetVpnGateways" {
+		return lbResource, false, nil
+	}
+
+	// target-based rules point at a proxy or pool, while newer passthrough LBs point straight at a backend service
+	lbTarget := fwdRule.GetTarget()
+	if lbTarget == "" {
+		lbTarget = fwdRule.GetBackendService()
+	}
+
+	lbResource = LoadBalancingResource{
+		Resource: generalResource.Resource{
+			Id:        strconv.FormatUint(fwdRule.GetId(), 10),
+			RID:       selflink.GetResourcePath(fwdRule.GetSelfLink()),
+			AccountID: lbp.ProjectID,
+			Name:      fwdRule.GetName(),
+			CloudSvc:  "load_balancing",
+			Location:  region,
+		},
+		ForwardingRule: fwdRule.GetSelfLink(),
+		Target:         lbTarget,
+		Region:         region,
+	}
+
+	err := AddIPAddrToResource(&lbResource, fwdRule.GetIPAddress())
 	if err != nil {
-		return lbResources, err
+		return lbResource, false, err
 	}
-	defer gaClient.Close()
 
-	req := &gcpcomputepbapi.ListGlobalAddressesRequest{
-		Project: lbp.ProjectID,
+	return lbResource, true, nil
+}
+
+func IsLBAddressUser(addrUser string, vpnFwdRules []string) bool {
+	// reserved addresses are also used by Cloud NAT routers, VPN gateways, and VM instances, which are covered by their own plugins
+	userType := selflink.GetResourceType(addrUser)
+
+	switch {
+	case userType == "forwardingRules":
+		return !slices.Contains(vpnFwdRules, selflink.GetResourcePath(addrUser))
+	case strings.HasPrefix(userType, "target") && strings.HasSuffix(userType, "Proxies"):
+		return true
 	}
 
-	lbGlobalAddrList = gaClient.List(ctx, req)
+	return false
+}
 
+func (lbp LoadBalancingPlugin) ProcessAddress(addr *gcpcomputepbapi.Address, region string, vpnFwdRules []string) (LoadBalancingResource, bool, error) {
+	var lbResource LoadBalancingResource
+
+	if addr.GetAddressType() != gcpcomputepbapi.Address_EXTERNAL.String() || addr.GetAddress() == "" {
+		return lbResource, false, nil
+	}
+
+	lbResource = LoadBalancingResource{
+		Resource: generalResource.Resource{
+			Id:        strconv.FormatUint(addr.GetId(), 10),
+			RID:       selflink.GetResourcePath(addr.GetSelfLink()),
+			AccountID: lbp.ProjectID,
+			Name:      addr.GetName(),
+			Status:    addr.GetStatus(),
+			CloudSvc:  "load_balancing",
+			Location:  region,
+		},
+		Region: region,
+	}
+
+	// reserved addresses list whatever is using them; only those used by an LB are ours, which also rules out unattached addresses
+	for _, addrUser := range addr.GetUsers() {
+		if !IsLBAddressUser(addrUser, vpnFwdRules) {
+			continue
+		}
+
+		if selflink.GetResourceType(addrUser) == "forwardingRules" {
+			lbResource.ForwardingRule = addrUser
+		} else if lbResource.Target == "" {
+			lbResource.Target = addrUser
+		}
+	}
+	if lbResource.ForwardingRule == "" && lbResource.Target == "" {
+		return lbResource, false, nil
+	}
+
+	err := AddIPAddrToResource(&lbResource, addr.GetAddress())
+	if err != nil {
+		return lbResource, false, err
+	}
+
+	return lbResource, true, nil
+}
+
+func (lbp LoadBalancingPlugin) GetForwardingRuleResources(ctx context.Context) ([]LoadBalancingResource, []string, error) {
+	// returns the LB forwarding rules, along with the resource paths of classic VPN forwarding rules so their reserved addresses can be skipped
+	var lbResources []LoadBalancingResource
+	var vpnFwdRules []string
+
+	frClient, err := gcpcomputeapi.NewForwardingRulesRESTClient(ctx, lbp.GCPConn.ClientOptions...)
+	if err != nil {
+		return lbResources, vpnFwdRules, err
+	}
+	defer frClient.Close()
+
+	// aggregated lists include both regional and global forwarding rules
+	req := &gcpcomputepbapi.AggregatedListForwardingRulesRequest{
+		Project:              lbp.ProjectID,
+		ReturnPartialSuccess: proto.Bool(true),
+	}
+
+	frList := frClient.AggregatedList(ctx, req)
 	for {
-		addr, err := lbGlobalAddrList.Next()
+		frListPair, err := frList.Next()
 		if err == iterator.Done {
 			break
 		} else if err != nil {
-			return lbResources, err
+			return lbResources, vpnFwdRules, err
 		}
 
-		lbId := strconv.FormatUint(addr.GetId(), 10)
-		lbName := addr.GetName()
-		lbStatus := addr.GetStatus()
-		lbIpAddr := addr.GetAddress()
+		region := selflink.GetRegionFromScope(frListPair.Key)
+		for _, fwdRule := range frListPair.Value.GetForwardingRules() {
+			if selflink.GetResourceType(fwdRule.GetTarget()) == "targetVpnGateways" {
+				vpnFwdRules = append(vpnFwdRules, selflink.GetResourcePath(fwdRule.GetSelfLink()))
+			}
+
+			lbResource, isPublic, err := lbp.ProcessForwardingRule(fwdRule, region)
+			if err != nil {
+				return lbResources, vpnFwdRules, err
+			} else if !isPublic {
+				continue
+			}
 
-		log.Debug("load balancer endpoint found - ID: ", lbId, ", Name: ", lbName, ", Status: ", lbStatus, ", IP: ", lbIpAddr)
+			log.Debug("load balancer forwarding rule found - ID: ", lbResource.Id, ", Name: ", lbResource.Name, ", Region: ", region, ", Target: ", lbResource.Target)
 
-		currentResource := generalResource.Resource{
-			Id:             lbId,
-			Name:           lbName,
-			Status:         lbStatus,
-			CloudSvc:       "load_balancing",
-			AccountAliases: []string{lbp.ProjectID},
+			lbResources = append(lbResources, lbResource)
 		}
+	}
+
+	return lbResources, vpnFwdRules, nil
+}
 
-		// for some god-awful reason, no value is being returned when calling the addr.GetIpVersion() method
-		// as such, we will need to determine it ourselves :(
-		ipVer, err := utils.DetermineIpAddrVersion(lbIpAddr)
-		if err != nil {
+func (lbp LoadBalancingPlugin) GetAddressResources(ctx context.Context, vpnFwdRules []string) ([]LoadBalancingResource, error) {
+	var lbResources []LoadBalancingResource
+
+	addrClient, err := gcpcomputeapi.NewAddressesRESTClient(ctx, lbp.GCPConn.ClientOptions...)
+	if err != nil {
+		return lbResources, err
+	}
+	defer addrClient.Close()
+
+	req := &gcpcomputepbapi.AggregatedListAddressesRequest{
+		Project:              lbp.ProjectID,
+		ReturnPartialSuccess: proto.Bool(true),
+	}
+
+	addrList := addrClient.AggregatedList(ctx, req)
+	for {
+		addrListPair, err := addrList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
 			return lbResources, err
 		}
 
-		switch ipVer {
-		case 4:
-			currentResource.PublicIPv4Addrs = append(currentResource.PublicIPv4Addrs, lbIpAddr)
-		case 6:
-			currentResource.PublicIPv6Addrs = append(currentResource.PublicIPv6Addrs, lbIpAddr)
-		default:
-			return lbResources, fmt.Errorf("invalid IP version found for GCP LB; IP: %s, Version: IPv%d", lbIpAddr, ipVer)
+		region := selflink.GetRegionFromScope(addrListPair.Key)
+		for _, addr := range addrListPair.Value.GetAddresses() {
+			lbResource, isLBAddr, err := lbp.ProcessAddress(addr, region, vpnFwdRules)
+			if err != nil {
+				return lbResources, err
+			} else if !isLBAddr {
+				continue
+			}
+
+			log.Debug("load balancer endpoint found - ID: ", lbResource.Id, ", Name: ", lbResource.Name, ", Status: ", lbResource.Status, ", Region: ", region)
+
+			lbResources = append(lbResources, lbResource)
 		}
+	}
 
-		lbResources = append(
-			lbResources,
-			currentResource,
-		)
+	return lbResources, nil
+}
+
+func (lbp LoadBalancingPlugin) GetResources() ([]LoadBalancingResource, error) {
+	var lbResources []LoadBalancingResource
+	var fwdRuleIPAddrs []string
+
+	ctx := context.Background()
+
+	fwdRuleResources, vpnFwdRules, err := lbp.GetForwardingRuleResources(ctx)
+	if err != nil {
+		return lbResources, err
+	}
+	lbResources = append(lbResources, fwdRuleResources...)
+
+	for _, fwdRuleResource := range fwdRuleResources {
+		fwdRuleIPAddrs = append(fwdRuleIPAddrs, fwdRuleResource.PublicIPv4Addrs...)
+		fwdRuleIPAddrs = append(fwdRuleIPAddrs, fwdRuleResource.PublicIPv6Addrs...)
+	}
+
+	addrResources, err := lbp.GetAddressResources(ctx, vpnFwdRules)
+	if err != nil {
+		return lbResources, err
+	}
+
+	// reserved addresses that are attached to a forwarding rule are already covered by the forwarding rule itself
+	for _, addrResource := range addrResources {
+		addrIPAddrs := append(addrResource.PublicIPv4Addrs, addrResource.PublicIPv6Addrs...)
+		if slices.ContainsFunc(addrIPAddrs, func(ipAddr string) bool { return slices.Contains(fwdRuleIPAddrs, ipAddr) }) {
+			continue
+		}
+
+		lbResources = append(lbResources, addrResource)
 	}
 
 	return lbResources, nil
@@ -89,34 +287,28 @@ func (lbp LoadBalancingPlugin) GetResources() ([]generalResource.Resource, error
 func (lbp LoadBalancingPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
 	log.Debug("fetching and searching load balancing resources")
 
-	fetchedResources, err := lbp.GetResources()
+	fetchedResources, err := inventorycache.Fetch(lbp.InvCache, "load_balancing/"+lbp.ProjectID, lbp.GetResources)
 	if err != nil {
 		return *matchingResource, err
 	}
 
 	for _, lbResource := range fetchedResources {
-		ridSlug := fmt.Sprintf("%s/%s", lbResource.Id, lbResource.Name)
+		if slices.Contains(lbResource.PublicIPv4Addrs, tgtIP) || slices.Contains(lbResource.PublicIPv6Addrs, tgtIP) {
+			*matchingResource = lbResource.Resource
+			// the forwarding rule and target are already on hand, so there's no reason to hold them back for network mapping only
+			matchingResource.NetworkMap = GenerateNetworkMap(lbResource)
 
-		for _, ipv4Addr := range lbResource.PublicIPv4Addrs {
-			if ipv4Addr == tgtIP {
-				matchingResource.RID = ridSlug
-				matchingResource.CloudSvc = "load_balancing"
+			if lbp.NetworkMapping && lbResource.ForwardingRule != "" {
+				// the full path starts with the forwarding rule and target too
+				networkMap, err := lbp.MapNetworkPath(lbResource)
+				if err != nil {
+					return *matchingResource, err
+				}
 
-				break
+				matchingResource.NetworkMap = networkMap
 			}
-		}
 
-		for _, ipv6Addr := range lbResource.PublicIPv6Addrs {
-			if ipv6Addr == tgtIP {
-				matchingResource.RID = ridSlug
-				matchingResource.CloudSvc = "load_balancing"
-
-				break
-			}
-		}
-
-		if matchingResource.RID != "" {
-			log.Debug("IP found as Load Balancer -> ", matchingResource.RID)
+			log.Debug("IP found as Load Balancer -> ", matchingResource.RID, " in region ", lbResource.Region, " with target ", lbResource.Target, " and network info ", matchingResource.NetworkMap)
 
 			break
 		}
@@ -124,3 +316,18 @@ func (lbp LoadBalancingPlugin) SearchResources(tgtIP string, matchingResource *g
 
 	return *matchingResource, nil
 }
+
+func (lbp LoadBalancingPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	var inventory []generalResource.Resource
+
+	fetchedResources, err := inventorycache.Fetch(lbp.InvCache, "load_balancing/"+lbp.ProjectID, lbp.GetResources)
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, lbResource := range fetchedResources {
+		inventory = append(inventory, lbResource.Resource)
+	}
+
+	return inventory, nil
+}
@@ -0,0 +1,481 @@
+package load_balancing
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	gcpcomputeapi "cloud.google.com/go/compute/apiv1"
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	selflink "github.com/magneticstain/ip-2-cloudresource/gcp/self_link"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+func appendUniqueLink(links []string, link string) []string {
+	if link == "" || slices.Contains(links, link) {
+		return links
+	}
+
+	return append(links, link)
+}
+
+func GetResourceNames(selfLinks []string) []string {
+	var resourceNames []string
+
+	for _, selfLink := range selfLinks {
+		resourceNames = append(resourceNames, selflink.GetResourceName(selfLink))
+	}
+
+	return resourceNames
+}
+
+func GetURLMapBackendServices(urlMap *gcpcomputepbapi.UrlMap) []string {
+	var backendSvcs []string
+
+	// backend services can be referenced at the top level of the URL map, as well as by any path matcher, path rule, or route rule
+	backendSvcs = appendUniqueLink(backendSvcs, urlMap.GetDefaultService())
+
+	for _, pathMatcher := range urlMap.GetPathMatchers() {
+		backendSvcs = appendUniqueLink(backendSvcs, pathMatcher.GetDefaultService())
+
+		for _, pathRule := range pathMatcher.GetPathRules() {
+			backendSvcs = appendUniqueLink(backendSvcs, pathRule.GetService())
+		}
+
+		for _, routeRule := range pathMatcher.GetRouteRules() {
+			backendSvcs = appendUniqueLink(backendSvcs, routeRule.GetService())
+		}
+	}
+
+	return backendSvcs
+}
+
+func GetNetworkEndpointName(networkEndpoint *gcpcomputepbapi.NetworkEndpoint) string {
+	if networkEndpoint.GetInstance() != "" {
+		return selflink.GetResourceName(networkEndpoint.GetInstance())
+	}
+
+	if networkEndpoint.Port != nil {
+		return fmt.Sprintf("%s:%d", networkEndpoint.GetIpAddress(), networkEndpoint.GetPort())
+	}
+
+	return networkEndpoint.GetIpAddress()
+}
+
+// network paths can pass through several resources of the same type (e.g. the backend services of a URL map), so each API client is created the first time it's needed and reused for the rest of the path
+type NetworkMapClients struct {
+	clientOpts  []option.ClientOption
+	openClients []interface{ Close() error }
+
+	httpProxies           *gcpcomputeapi.TargetHttpProxiesClient
+	regionHTTPProxies     *gcpcomputeapi.RegionTargetHttpProxiesClient
+	httpsProxies          *gcpcomputeapi.TargetHttpsProxiesClient
+	regionHTTPSProxies    *gcpcomputeapi.RegionTargetHttpsProxiesClient
+	tcpProxies            *gcpcomputeapi.TargetTcpProxiesClient
+	regionTCPProxies      *gcpcomputeapi.RegionTargetTcpProxiesClient
+	sslProxies            *gcpcomputeapi.TargetSslProxiesClient
+	urlMaps               *gcpcomputeapi.UrlMapsClient
+	regionURLMaps         *gcpcomputeapi.RegionUrlMapsClient
+	backendSvcs           *gcpcomputeapi.BackendServicesClient
+	regionBackendSvcs     *gcpcomputeapi.RegionBackendServicesClient
+	targetPools           *gcpcomputeapi.TargetPoolsClient
+	instanceGroups        *gcpcomputeapi.InstanceGroupsClient
+	regionInstanceGroups  *gcpcomputeapi.RegionInstanceGroupsClient
+	networkEndpointGroups *gcpcomputeapi.NetworkEndpointGroupsClient
+}
+
+func NewNetworkMapClients(clientOpts ...option.ClientOption) *NetworkMapClients {
+	return &NetworkMapClients{clientOpts: clientOpts}
+}
+
+func getNetworkMapClient[T interface {
+	comparable
+	Close() error
+}](ctx context.Context, nmc *NetworkMapClients, client *T, newClient func(context.Context, ...option.ClientOption) (T, error)) (T, error) {
+	var noClient T
+
+	if *client != noClient {
+		return *client, nil
+	}
+
+	createdClient, err := newClient(ctx, nmc.clientOpts...)
+	if err != nil {
+		return noClient, err
+	}
+
+	*client = createdClient
+	nmc.openClients = append(nmc.openClients, createdClient)
+
+	return createdClient, nil
+}
+
+func (nmc *NetworkMapClients) Close() {
+	for _, openClient := range nmc.openClients {
+		if err := openClient.Close(); err != nil {
+			log.Warn("unable to close GCP network mapping client: ", err)
+		}
+	}
+
+	nmc.openClients = nil
+}
+
+func (nmc *NetworkMapClients) GetTargetProxyRef(ctx context.Context, proxyLink string) (string, error) {
+	// returns the URL map (HTTP/S proxies) or backend service (TCP/SSL proxies) that the proxy sends traffic to
+	var nextHopLink string
+
+	project := selflink.GetProject(proxyLink)
+	proxyName := selflink.GetResourceName(proxyLink)
+	scopeType, region := selflink.GetLocation(proxyLink)
+	isRegional := scopeType == "regions"
+
+	switch selflink.GetResourceType(proxyLink) {
+	case "targetHttpProxies":
+		if isRegional {
+			proxyClient, err := getNetworkMapClient(ctx, nmc, &nmc.regionHTTPProxies, gcpcomputeapi.NewRegionTargetHttpProxiesRESTClient)
+			if err != nil {
+				return nextHopLink, err
+			}
+
+			proxy, err := proxyClient.Get(ctx, &gcpcomputepbapi.GetRegionTargetHttpProxyRequest{Project: project, Region: region, TargetHttpProxy: proxyName})
+			if err != nil {
+				return nextHopLink, err
+			}
+
+			return proxy.GetUrlMap(), nil
+		}
+
+		proxyClient, err := getNetworkMapClient(ctx, nmc, &nmc.httpProxies, gcpcomputeapi.NewTargetHttpProxiesRESTClient)
+		if err != nil {
+			return nextHopLink, err
+		}
+
+		proxy, err := proxyClient.Get(ctx, &gcpcomputepbapi.GetTargetHttpProxyRequest{Project: project, TargetHttpProxy: proxyName})
+		if err != nil {
+			return nextHopLink, err
+		}
+
+		return proxy.GetUrlMap(), nil
+	case "targetHttpsProxies":
+		if isRegional {
+			proxyClient, err := getNetworkMapClient(ctx, nmc, &nmc.regionHTTPSProxies, gcpcomputeapi.NewRegionTargetHttpsProxiesRESTClient)
+			if err != nil {
+				return nextHopLink, err
+			}
+
+			proxy, err := proxyClient.Get(ctx, &gcpcomputepbapi.GetRegionTargetHttpsProxyRequest{Project: project, Region: region, TargetHttpsProxy: proxyName})
+			if err != nil {
+				return nextHopLink, err
+			}
+
+			return proxy.GetUrlMap(), nil
+		}
+
+		proxyClient, err := getNetworkMapClient(ctx, nmc, &nmc.httpsProxies, gcpcomputeapi.NewTargetHttpsProxiesRESTClient)
+		if err != nil {
+			return nextHopLink, err
+		}
+
+		proxy, err := proxyClient.Get(ctx, &gcpcomputepbapi.GetTargetHttpsProxyRequest{Project: project, TargetHttpsProxy: proxyName})
+		if err != nil {
+			return nextHopLink, err
+		}
+
+		return proxy.GetUrlMap(), nil
+	case "targetTcpProxies":
+		if isRegional {
+			proxyClient, err := getNetworkMapClient(ctx, nmc, &nmc.regionTCPProxies, gcpcomputeapi.NewRegionTargetTcpProxiesRESTClient)
+			if err != nil {
+				return nextHopLink, err
+			}
+
+			proxy, err := proxyClient.Get(ctx, &gcpcomputepbapi.GetRegionTargetTcpProxyRequest{Project: project, Region: region, TargetTcpProxy: proxyName})
+			if err != nil {
+				return nextHopLink, err
+			}
+
+			return proxy.GetService(), nil
+		}
+
+		proxyClient, err := getNetworkMapClient(ctx, nmc, &nmc.tcpProxies, gcpcomputeapi.NewTargetTcpProxiesRESTClient)
+		if err != nil {
+			return nextHopLink, err
+		}
+
+		proxy, err := proxyClient.Get(ctx, &gcpcomputepbapi.GetTargetTcpProxyRequest{Project: project, TargetTcpProxy: proxyName})
+		if err != nil {
+			return nextHopLink, err
+		}
+
+		return proxy.GetService(), nil
+	case "targetSslProxies":
+		proxyClient, err := getNetworkMapClient(ctx, nmc, &nmc.sslProxies, gcpcomputeapi.NewTargetSslProxiesRESTClient)
+		if err != nil {
+			return nextHopLink, err
+		}
+
+		proxy, err := proxyClient.Get(ctx, &gcpcomputepbapi.GetTargetSslProxyRequest{Project: project, TargetSslProxy: proxyName})
+		if err != nil {
+			return nextHopLink, err
+		}
+
+		return proxy.GetService(), nil
+	}
+
+	return nextHopLink, fmt.Errorf("unsupported GCP target proxy type: %s", proxyLink)
+}
+
+func (nmc *NetworkMapClients) GetURLMap(ctx context.Context, urlMapLink string) (*gcpcomputepbapi.UrlMap, error) {
+	project := selflink.GetProject(urlMapLink)
+	urlMapName := selflink.GetResourceName(urlMapLink)
+
+	if scopeType, region := selflink.GetLocation(urlMapLink); scopeType == "regions" {
+		urlMapClient, err := getNetworkMapClient(ctx, nmc, &nmc.regionURLMaps, gcpcomputeapi.NewRegionUrlMapsRESTClient)
+		if err != nil {
+			return nil, err
+		}
+
+		return urlMapClient.Get(ctx, &gcpcomputepbapi.GetRegionUrlMapRequest{Project: project, Region: region, UrlMap: urlMapName})
+	}
+
+	urlMapClient, err := getNetworkMapClient(ctx, nmc, &nmc.urlMaps, gcpcomputeapi.NewUrlMapsRESTClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return urlMapClient.Get(ctx, &gcpcomputepbapi.GetUrlMapRequest{Project: project, UrlMap: urlMapName})
+}
+
+func (nmc *NetworkMapClients) GetBackendService(ctx context.Context, backendSvcLink string) (*gcpcomputepbapi.BackendService, error) {
+	project := selflink.GetProject(backendSvcLink)
+	backendSvcName := selflink.GetResourceName(backendSvcLink)
+
+	if scopeType, region := selflink.GetLocation(backendSvcLink); scopeType == "regions" {
+		backendSvcClient, err := getNetworkMapClient(ctx, nmc, &nmc.regionBackendSvcs, gcpcomputeapi.NewRegionBackendServicesRESTClient)
+		if err != nil {
+			return nil, err
+		}
+
+		return backendSvcClient.Get(ctx, &gcpcomputepbapi.GetRegionBackendServiceRequest{Project: project, Region: region, BackendService: backendSvcName})
+	}
+
+	backendSvcClient, err := getNetworkMapClient(ctx, nmc, &nmc.backendSvcs, gcpcomputeapi.NewBackendServicesRESTClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return backendSvcClient.Get(ctx, &gcpcomputepbapi.GetBackendServiceRequest{Project: project, BackendService: backendSvcName})
+}
+
+func (nmc *NetworkMapClients) GetTargetPoolInstances(ctx context.Context, targetPoolLink string) ([]string, error) {
+	_, region := selflink.GetLocation(targetPoolLink)
+
+	targetPoolClient, err := getNetworkMapClient(ctx, nmc, &nmc.targetPools, gcpcomputeapi.NewTargetPoolsRESTClient)
+	if err != nil {
+		return nil, err
+	}
+
+	targetPool, err := targetPoolClient.Get(ctx, &gcpcomputepbapi.GetTargetPoolRequest{
+		Project:    selflink.GetProject(targetPoolLink),
+		Region:     region,
+		TargetPool: selflink.GetResourceName(targetPoolLink),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetResourceNames(targetPool.GetInstances()), nil
+}
+
+func (nmc *NetworkMapClients) GetInstanceGroupInstances(ctx context.Context, instanceGroupLink string) ([]string, error) {
+	var instanceNames []string
+	var instanceList *gcpcomputeapi.InstanceWithNamedPortsIterator
+
+	project := selflink.GetProject(instanceGroupLink)
+	instanceGroupName := selflink.GetResourceName(instanceGroupLink)
+	allInstances := gcpcomputepbapi.InstanceGroupsListInstancesRequest_ALL.String()
+
+	if scopeType, location := selflink.GetLocation(instanceGroupLink); scopeType == "regions" {
+		igClient, err := getNetworkMapClient(ctx, nmc, &nmc.regionInstanceGroups, gcpcomputeapi.NewRegionInstanceGroupsRESTClient)
+		if err != nil {
+			return instanceNames, err
+		}
+
+		instanceList = igClient.ListInstances(ctx, &gcpcomputepbapi.ListInstancesRegionInstanceGroupsRequest{
+			Project:       project,
+			Region:        location,
+			InstanceGroup: instanceGroupName,
+			RegionInstanceGroupsListInstancesRequestResource: &gcpcomputepbapi.RegionInstanceGroupsListInstancesRequest{InstanceState: &allInstances},
+		})
+	} else {
+		igClient, err := getNetworkMapClient(ctx, nmc, &nmc.instanceGroups, gcpcomputeapi.NewInstanceGroupsRESTClient)
+		if err != nil {
+			return instanceNames, err
+		}
+
+		instanceList = igClient.ListInstances(ctx, &gcpcomputepbapi.ListInstancesInstanceGroupsRequest{
+			Project:       project,
+			Zone:          location,
+			InstanceGroup: instanceGroupName,
+			InstanceGroupsListInstancesRequestResource: &gcpcomputepbapi.InstanceGroupsListInstancesRequest{InstanceState: &allInstances},
+		})
+	}
+
+	for {
+		instance, err := instanceList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return instanceNames, err
+		}
+
+		instanceNames = append(instanceNames, selflink.GetResourceName(instance.GetInstance()))
+	}
+
+	return instanceNames, nil
+}
+
+func (nmc *NetworkMapClients) GetNetworkEndpointGroupEndpoints(ctx context.Context, negLink string) ([]string, error) {
+	var endpointNames []string
+
+	// only zonal NEGs contain endpoints we can list; serverless, PSC, and internet NEGs point at services outside of the VPC
+	scopeType, zone := selflink.GetLocation(negLink)
+	if scopeType != "zones" {
+		return endpointNames, nil
+	}
+
+	negClient, err := getNetworkMapClient(ctx, nmc, &nmc.networkEndpointGroups, gcpcomputeapi.NewNetworkEndpointGroupsRESTClient)
+	if err != nil {
+		return endpointNames, err
+	}
+
+	endpointList := negClient.ListNetworkEndpoints(ctx, &gcpcomputepbapi.ListNetworkEndpointsNetworkEndpointGroupsRequest{
+		Project:              selflink.GetProject(negLink),
+		Zone:                 zone,
+		NetworkEndpointGroup: selflink.GetResourceName(negLink),
+		NetworkEndpointGroupsListEndpointsRequestResource: &gcpcomputepbapi.NetworkEndpointGroupsListEndpointsRequest{},
+	})
+	for {
+		endpoint, err := endpointList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return endpointNames, err
+		}
+
+		endpointNames = append(endpointNames, GetNetworkEndpointName(endpoint.GetNetworkEndpoint()))
+	}
+
+	return endpointNames, nil
+}
+
+func (nmc *NetworkMapClients) MapBackendServices(ctx context.Context, backendSvcLinks []string) ([]string, error) {
+	var backendGroupLinks, backendTgts []string
+
+	for _, backendSvcLink := range backendSvcLinks {
+		if selflink.GetResourceType(backendSvcLink) != "backendServices" {
+			// backend buckets serve straight from GCS, so there's nothing further to map
+			continue
+		}
+
+		backendSvc, err := nmc.GetBackendService(ctx, backendSvcLink)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, backend := range backendSvc.GetBackends() {
+			backendGroupLinks = appendUniqueLink(backendGroupLinks, backend.GetGroup())
+		}
+	}
+
+	for _, backendGroupLink := range backendGroupLinks {
+		var groupTgts []string
+		var err error
+
+		switch selflink.GetResourceType(backendGroupLink) {
+		case "instanceGroups":
+			groupTgts, err = nmc.GetInstanceGroupInstances(ctx, backendGroupLink)
+		case "networkEndpointGroups":
+			groupTgts, err = nmc.GetNetworkEndpointGroupEndpoints(ctx, backendGroupLink)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		backendTgts = append(backendTgts, groupTgts...)
+	}
+
+	return []string{
+		utils.FormatStrSliceAsCSV(GetResourceNames(backendSvcLinks)),
+		utils.FormatStrSliceAsCSV(GetResourceNames(backendGroupLinks)),
+		utils.FormatStrSliceAsCSV(backendTgts),
+	}, nil
+}
+
+func (lbp LoadBalancingPlugin) MapNetworkPath(lbResource LoadBalancingResource) ([]string, error) {
+	var networkMap, backendSvcLinks []string
+
+	ctx := context.Background()
+
+	if lbResource.ForwardingRule == "" {
+		// reserved addresses that aren't attached to a forwarding rule don't route anywhere
+		return networkMap, nil
+	}
+
+	nmc := NewNetworkMapClients(lbp.GCPConn.ClientOptions...)
+	defer nmc.Close()
+	networkMap = append(networkMap, selflink.GetResourceName(lbResource.ForwardingRule))
+
+	log.Debug("mapping network path for GCP forwarding rule [ ", lbResource.ForwardingRule, " ] with target [ ", lbResource.Target, " ]")
+
+	switch selflink.GetResourceType(lbResource.Target) {
+	case "targetHttpProxies", "targetHttpsProxies":
+		urlMapLink, err := nmc.GetTargetProxyRef(ctx, lbResource.Target)
+		if err != nil {
+			return networkMap, err
+		}
+
+		urlMap, err := nmc.GetURLMap(ctx, urlMapLink)
+		if err != nil {
+			return networkMap, err
+		}
+
+		networkMap = append(networkMap, selflink.GetResourceName(lbResource.Target), urlMap.GetName())
+		backendSvcLinks = GetURLMapBackendServices(urlMap)
+	case "targetTcpProxies", "targetSslProxies":
+		backendSvcLink, err := nmc.GetTargetProxyRef(ctx, lbResource.Target)
+		if err != nil {
+			return networkMap, err
+		}
+
+		networkMap = append(networkMap, selflink.GetResourceName(lbResource.Target))
+		backendSvcLinks = []string{backendSvcLink}
+	case "targetPools":
+		// target pools reference instances directly; there's no backend service in between
+		poolInstances, err := nmc.GetTargetPoolInstances(ctx, lbResource.Target)
+		if err != nil {
+			return networkMap, err
+		}
+
+		return append(networkMap, selflink.GetResourceName(lbResource.Target), utils.FormatStrSliceAsCSV(poolInstances)), nil
+	case "backendServices":
+		backendSvcLinks = []string{lbResource.Target}
+	default:
+		if lbResource.Target != "" {
+			networkMap = append(networkMap, selflink.GetResourceName(lbResource.Target))
+		}
+
+		return networkMap, nil
+	}
+
+	backendMap, err := nmc.MapBackendServices(ctx, backendSvcLinks)
+	if err != nil {
+		return networkMap, err
+	}
+
+	return append(networkMap, backendMap...), nil
+}
@@ -0,0 +1,146 @@
+package load_balancing_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/api/option"
+	"google.golang.org/protobuf/proto"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/load_balancing"
+)
+
+func TestGetURLMapBackendServices(t *testing.T) {
+	bsPrefix := "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/"
+
+	urlMap := gcpcomputepbapi.UrlMap{
+		DefaultService: proto.String(bsPrefix + "web"),
+		PathMatchers: []*gcpcomputepbapi.PathMatcher{
+			{
+				DefaultService: proto.String(bsPrefix + "web"),
+				PathRules: []*gcpcomputepbapi.PathRule{
+					{Service: proto.String(bsPrefix + "api")},
+				},
+				RouteRules: []*gcpcomputepbapi.HttpRouteRule{
+					{Service: proto.String(bsPrefix + "static")},
+					{},
+				},
+			},
+		},
+	}
+
+	expectedBackendSvcs := []string{bsPrefix + "web", bsPrefix + "api", bsPrefix + "static"}
+
+	backendSvcs := plugin.GetURLMapBackendServices(&urlMap)
+	if !reflect.DeepEqual(backendSvcs, expectedBackendSvcs) {
+		t.Errorf("Fetching GCP URL map backend services failed; expected %v, received %v", expectedBackendSvcs, backendSvcs)
+	}
+}
+
+func TestGetNetworkEndpointName(t *testing.T) {
+	var tests = []struct {
+		networkEndpoint *gcpcomputepbapi.NetworkEndpoint
+		expectedName    string
+	}{
+		{&gcpcomputepbapi.NetworkEndpoint{Instance: proto.String("https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instances/web-1"), IpAddress: proto.String("10.0.0.2")}, "web-1"},
+		{&gcpcomputepbapi.NetworkEndpoint{IpAddress: proto.String("10.0.0.3"), Port: proto.Int32(8080)}, "10.0.0.3:8080"},
+		{&gcpcomputepbapi.NetworkEndpoint{IpAddress: proto.String("10.0.0.4")}, "10.0.0.4"},
+	}
+
+	for _, td := range tests {
+		testName := td.expectedName
+
+		t.Run(testName, func(t *testing.T) {
+			endpointName := plugin.GetNetworkEndpointName(td.networkEndpoint)
+
+			if endpointName != td.expectedName {
+				t.Errorf("Fetching GCP network endpoint name failed; expected %s, received %s", td.expectedName, endpointName)
+			}
+		})
+	}
+}
+
+func TestMapNetworkPath(t *testing.T) {
+	lbPlug := lbPlugFactory()
+
+	var tests = []struct {
+		lbResource         plugin.LoadBalancingResource
+		expectedNetworkMap []string
+	}{
+		// unattached reserved address
+		{plugin.LoadBalancingResource{}, nil},
+		// forwarding rule w/ a target type that doesn't lead to any backends
+		{
+			plugin.LoadBalancingResource{
+				ForwardingRule: "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/forwardingRules/proto-rule",
+				Target:         "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/targetInstances/my-target-instance",
+			},
+			[]string{"proto-rule", "my-target-instance"},
+		},
+	}
+
+	for _, td := range tests {
+		testName := td.lbResource.ForwardingRule
+
+		t.Run(testName, func(t *testing.T) {
+			networkMap, err := lbPlug.MapNetworkPath(td.lbResource)
+			if err != nil {
+				t.Errorf("Mapping GCP load balancer network path failed; received error: %s", err)
+			}
+
+			if !reflect.DeepEqual(networkMap, td.expectedNetworkMap) {
+				t.Errorf("Mapping GCP load balancer network path failed; expected %v, received %v", td.expectedNetworkMap, networkMap)
+			}
+		})
+	}
+}
+
+func TestMapBackendServices(t *testing.T) {
+	// each backend service and instance group is served by the same fake API, so the clients are reused between them
+	selfLinkPrefix := "https://www.googleapis.com/compute/v1/projects/my-project/"
+
+	var reqCount int
+	computeAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCount++
+
+		resourceName := path.Base(r.URL.Path)
+		switch {
+		case strings.Contains(r.URL.Path, "/backendServices/"):
+			fmt.Fprintf(w, `{"name": "%s", "backends": [{"group": "%szones/us-central1-a/instanceGroups/%s-ig"}]}`, resourceName, selfLinkPrefix, resourceName)
+		case resourceName == "listInstances":
+			igName := path.Base(path.Dir(r.URL.Path))
+			fmt.Fprintf(w, `{"items": [{"instance": "%szones/us-central1-a/instances/%s-vm"}]}`, selfLinkPrefix, strings.TrimSuffix(igName, "-ig"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer computeAPI.Close()
+
+	nmc := plugin.NewNetworkMapClients(option.WithEndpoint(computeAPI.URL), option.WithoutAuthentication())
+	defer nmc.Close()
+
+	backendMap, err := nmc.MapBackendServices(context.Background(), []string{
+		selfLinkPrefix + "global/backendServices/web",
+		selfLinkPrefix + "global/backendServices/api",
+		selfLinkPrefix + "global/backendBuckets/static",
+	})
+	if err != nil {
+		t.Fatalf("Mapping GCP backend services failed; received error: %s", err)
+	}
+
+	expectedBackendMap := []string{"[web,api,static]", "[web-ig,api-ig]", "[web-vm,api-vm]"}
+	if !reflect.DeepEqual(backendMap, expectedBackendMap) {
+		t.Errorf("Mapping GCP backend services failed; expected %v, received %v", expectedBackendMap, backendMap)
+	}
+
+	if reqCount != 4 {
+		t.Errorf("Mapping GCP backend services failed; expected 4 API requests, received %d", reqCount)
+	}
+}
@@ -0,0 +1,11 @@
+package load_balancing
+
+import (
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type LoadBalancingResource struct {
+	generalResource.Resource
+	// ForwardingRule and Target are the self-links of the forwarding rule and its target proxy, pool, or backend service
+	ForwardingRule, Target, Region string
+}
@@ -2,8 +2,12 @@ package load_balancing_test
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/protobuf/proto"
+
 	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/load_balancing"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
@@ -14,6 +18,243 @@ func lbPlugFactory() plugin.LoadBalancingPlugin {
 	return lbPlug
 }
 
+func TestProcessForwardingRule(t *testing.T) {
+	lbPlug := lbPlugFactory()
+
+	selfLinkPrefix := "https://www.googleapis.com/compute/v1/projects/my-project/"
+
+	var tests = []struct {
+		fwdRule          *gcpcomputepbapi.ForwardingRule
+		expectedIsPublic bool
+		expectedTarget   string
+	}{
+		{
+			&gcpcomputepbapi.ForwardingRule{
+				Name:                proto.String("https-rule"),
+				IPAddress:           proto.String("34.1.2.3"),
+				LoadBalancingScheme: proto.String("EXTERNAL_MANAGED"),
+				Target:              proto.String(selfLinkPrefix + "global/targetHttpsProxies/https-proxy"),
+				SelfLink:            proto.String(selfLinkPrefix + "global/forwardingRules/https-rule"),
+			},
+			true,
+			selfLinkPrefix + "global/targetHttpsProxies/https-proxy",
+		},
+		{
+			&gcpcomputepbapi.ForwardingRule{
+				Name:                proto.String("nlb-rule"),
+				IPAddress:           proto.String("2600:1900:4000::1"),
+				LoadBalancingScheme: proto.String("EXTERNAL"),
+				BackendService:      proto.String(selfLinkPrefix + "regions/us-central1/backendServices/nlb-backend"),
+				SelfLink:            proto.String(selfLinkPrefix + "regions/us-central1/forwardingRules/nlb-rule"),
+			},
+			true,
+			selfLinkPrefix + "regions/us-central1/backendServices/nlb-backend",
+		},
+		{
+			&gcpcomputepbapi.ForwardingRule{
+				Name:                proto.String("ilb-rule"),
+				IPAddress:           proto.String("10.0.0.5"),
+				LoadBalancingScheme: proto.String("INTERNAL"),
+			},
+			false,
+			"",
+		},
+		{
+			&gcpcomputepbapi.ForwardingRule{
+				Name:                proto.String("classic-vpn-esp"),
+				IPAddress:           proto.String("34.1.2.4"),
+				LoadBalancingScheme: proto.String("EXTERNAL"),
+				Target:              proto.String(selfLinkPrefix + "regions/us-central1/targetVpnGateways/classic-vpn"),
+			},
+			false,
+			"",
+		},
+	}
+
+	for _, td := range tests {
+		testName := td.fwdRule.GetName()
+
+		t.Run(testName, func(t *testing.T) {
+			lbResource, isPublic, err := lbPlug.ProcessForwardingRule(td.fwdRule, "us-central1")
+			if err != nil {
+				t.Errorf("Processing GCP forwarding rule failed; received error: %s", err)
+			}
+
+			if isPublic != td.expectedIsPublic {
+				t.Errorf("Processing GCP forwarding rule failed; expected public: %t, received: %t", td.expectedIsPublic, isPublic)
+			}
+
+			if !isPublic {
+				return
+			}
+
+			if lbResource.Target != td.expectedTarget || lbResource.Region != "us-central1" || lbResource.Location != "us-central1" {
+				t.Errorf("Processing GCP forwarding rule failed; expected target %s in us-central1, received %s in %s (location: %s)", td.expectedTarget, lbResource.Target, lbResource.Region, lbResource.Location)
+			}
+
+			ipAddrs := append(lbResource.PublicIPv4Addrs, lbResource.PublicIPv6Addrs...)
+			if !slices.Contains(ipAddrs, td.fwdRule.GetIPAddress()) {
+				t.Errorf("Processing GCP forwarding rule failed; expected IP %s, received %v", td.fwdRule.GetIPAddress(), ipAddrs)
+			}
+		})
+	}
+}
+
+func TestProcessAddress(t *testing.T) {
+	lbPlug := lbPlugFactory()
+
+	selfLinkPrefix := "https://www.googleapis.com/compute/v1/projects/my-project/"
+	fwdRuleSelfLink := selfLinkPrefix + "regions/us-east1/forwardingRules/my-rule"
+	vpnFwdRuleSelfLink := selfLinkPrefix + "regions/us-east1/forwardingRules/classic-vpn-esp"
+	proxySelfLink := selfLinkPrefix + "global/targetHttpsProxies/https-proxy"
+
+	vpnFwdRules := []string{"projects/my-project/regions/us-east1/forwardingRules/classic-vpn-esp"}
+
+	var tests = []struct {
+		addr                   *gcpcomputepbapi.Address
+		expectedIsLBAddr       bool
+		expectedForwardingRule string
+		expectedTarget         string
+	}{
+		{
+			&gcpcomputepbapi.Address{
+				Name:        proto.String("reserved-ip"),
+				Address:     proto.String("35.1.2.3"),
+				AddressType: proto.String("EXTERNAL"),
+				Status:      proto.String("IN_USE"),
+				Users:       []string{fwdRuleSelfLink},
+			},
+			true,
+			fwdRuleSelfLink,
+			"",
+		},
+		{
+			&gcpcomputepbapi.Address{
+				Name:        proto.String("proxy-ip"),
+				Address:     proto.String("35.1.2.5"),
+				AddressType: proto.String("EXTERNAL"),
+				Status:      proto.String("IN_USE"),
+				Users:       []string{proxySelfLink},
+			},
+			true,
+			"",
+			proxySelfLink,
+		},
+		{
+			&gcpcomputepbapi.Address{
+				Name:        proto.String("nat-ip"),
+				Address:     proto.String("35.1.2.6"),
+				AddressType: proto.String("EXTERNAL"),
+				Status:      proto.String("IN_USE"),
+				Users:       []string{selfLinkPrefix + "regions/us-east1/routers/nat-router"},
+			},
+			false,
+			"",
+			"",
+		},
+		{
+			&gcpcomputepbapi.Address{
+				Name:        proto.String("vpn-ip"),
+				Address:     proto.String("35.1.2.7"),
+				AddressType: proto.String("EXTERNAL"),
+				Status:      proto.String("IN_USE"),
+				Users:       []string{vpnFwdRuleSelfLink},
+			},
+			false,
+			"",
+			"",
+		},
+		{
+			&gcpcomputepbapi.Address{
+				Name:        proto.String("vm-ip"),
+				Address:     proto.String("35.1.2.8"),
+				AddressType: proto.String("EXTERNAL"),
+				Status:      proto.String("IN_USE"),
+				Users:       []string{selfLinkPrefix + "zones/us-east1-b/instances/my-vm"},
+			},
+			false,
+			"",
+			"",
+		},
+		{
+			&gcpcomputepbapi.Address{
+				Name:        proto.String("unused-ip"),
+				Address:     proto.String("35.1.2.4"),
+				AddressType: proto.String("EXTERNAL"),
+				Status:      proto.String("RESERVED"),
+			},
+			false,
+			"",
+			"",
+		},
+		{
+			&gcpcomputepbapi.Address{
+				Name:        proto.String("internal-ip"),
+				Address:     proto.String("10.0.0.10"),
+				AddressType: proto.String("INTERNAL"),
+			},
+			false,
+			"",
+			"",
+		},
+	}
+
+	for _, td := range tests {
+		testName := td.addr.GetName()
+
+		t.Run(testName, func(t *testing.T) {
+			lbResource, isLBAddr, err := lbPlug.ProcessAddress(td.addr, "us-east1", vpnFwdRules)
+			if err != nil {
+				t.Errorf("Processing GCP address failed; received error: %s", err)
+			}
+
+			if isLBAddr != td.expectedIsLBAddr {
+				t.Errorf("Processing GCP address failed; expected LB address: %t, received: %t", td.expectedIsLBAddr, isLBAddr)
+			}
+
+			if !isLBAddr {
+				return
+			}
+
+			if lbResource.ForwardingRule != td.expectedForwardingRule || lbResource.Target != td.expectedTarget {
+				t.Errorf("Processing GCP address failed; expected forwarding rule %s and target %s, received %s and %s", td.expectedForwardingRule, td.expectedTarget, lbResource.ForwardingRule, lbResource.Target)
+			}
+		})
+	}
+}
+
+func TestGenerateNetworkMap(t *testing.T) {
+	selfLinkPrefix := "https://www.googleapis.com/compute/v1/projects/my-project/"
+
+	var tests = []struct {
+		name               string
+		lbResource         plugin.LoadBalancingResource
+		expectedNetworkMap []string
+	}{
+		{
+			"fwd-rule-and-target",
+			plugin.LoadBalancingResource{ForwardingRule: selfLinkPrefix + "global/forwardingRules/web-fr", Target: selfLinkPrefix + "global/targetHttpsProxies/web-proxy"},
+			[]string{"web-fr", "web-proxy"},
+		},
+		{
+			"target-only",
+			plugin.LoadBalancingResource{Target: selfLinkPrefix + "regions/us-central1/targetHttpProxies/web-proxy"},
+			[]string{"web-proxy"},
+		},
+		{"empty", plugin.LoadBalancingResource{}, nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			networkMap := plugin.GenerateNetworkMap(td.lbResource)
+
+			if !slices.Equal(networkMap, td.expectedNetworkMap) {
+				t.Errorf("Generating GCP LB network map failed; expected %v, received %v", td.expectedNetworkMap, networkMap)
+			}
+		})
+	}
+}
+
 func TestGetResources(t *testing.T) {
 	lbPlug := lbPlugFactory()
 
@@ -55,3 +296,17 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceInventory(t *testing.T) {
+	lbPlug := lbPlugFactory()
+
+	inventory, _ := lbPlug.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via GCP Load Balancing Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
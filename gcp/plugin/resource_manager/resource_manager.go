@@ -0,0 +1,112 @@
+package resource_manager
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	gcprmapi "cloud.google.com/go/resourcemanager/apiv3"
+	gcprmpbapi "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"google.golang.org/api/iterator"
+
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+)
+
+type ResourceManagerPlugin struct {
+	GCPConn gcpconnector.GCPConnector
+	// organizations/<id> or folders/<id>; if not set, all projects visible to the current identity are returned
+	ParentID string
+}
+
+func NormalizeParentID(parentID string) string {
+	// bare numeric IDs are assumed to be organization IDs, since that's what most users will have on hand
+	if parentID != "" && !strings.Contains(parentID, "/") {
+		return "organizations/" + parentID
+	}
+
+	return parentID
+}
+
+func searchAllProjects(ctx context.Context, projClient *gcprmapi.ProjectsClient) ([]*gcprmpbapi.Project, error) {
+	var projects []*gcprmpbapi.Project
+
+	projList := projClient.SearchProjects(ctx, &gcprmpbapi.SearchProjectsRequest{Query: "state:ACTIVE"})
+	for {
+		project, err := projList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return projects, err
+		}
+
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+func listAllProjectsUnderParent(ctx context.Context, projClient *gcprmapi.ProjectsClient, folderClient *gcprmapi.FoldersClient, parentID string) ([]*gcprmpbapi.Project, error) {
+	var projects []*gcprmpbapi.Project
+
+	log.Debug("fetching projects under ", parentID)
+
+	projList := projClient.ListProjects(ctx, &gcprmpbapi.ListProjectsRequest{Parent: parentID})
+	for {
+		project, err := projList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return projects, err
+		}
+
+		projects = append(projects, project)
+	}
+
+	// ListProjects only returns direct children, so we need to walk each subfolder as well
+	folderList := folderClient.ListFolders(ctx, &gcprmpbapi.ListFoldersRequest{Parent: parentID})
+	for {
+		folder, err := folderList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return projects, err
+		}
+
+		folderProjects, err := listAllProjectsUnderParent(ctx, projClient, folderClient, folder.GetName())
+		if err != nil {
+			return projects, err
+		}
+
+		projects = append(projects, folderProjects...)
+	}
+
+	return projects, nil
+}
+
+func (rmp ResourceManagerPlugin) GetResources() ([]*gcprmpbapi.Project, error) {
+	var projects []*gcprmpbapi.Project
+
+	ctx := context.Background()
+
+	projClient, err := gcprmapi.NewProjectsRESTClient(ctx, rmp.GCPConn.ClientOptions...)
+	if err != nil {
+		return projects, err
+	}
+	defer projClient.Close()
+
+	parentID := NormalizeParentID(rmp.ParentID)
+	if parentID == "" {
+		log.Debug("no parent provided; fetching all projects visible to current identity")
+
+		return searchAllProjects(ctx, projClient)
+	}
+
+	folderClient, err := gcprmapi.NewFoldersRESTClient(ctx, rmp.GCPConn.ClientOptions...)
+	if err != nil {
+		return projects, err
+	}
+	defer folderClient.Close()
+
+	return listAllProjectsUnderParent(ctx, projClient, folderClient, parentID)
+}
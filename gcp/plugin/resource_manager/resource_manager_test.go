@@ -0,0 +1,51 @@
+package resource_manager_test
+
+import (
+	"reflect"
+	"testing"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/resource_manager"
+)
+
+func rmPlugFactory() plugin.ResourceManagerPlugin {
+	rmPlug := plugin.ResourceManagerPlugin{}
+
+	return rmPlug
+}
+
+func TestNormalizeParentID(t *testing.T) {
+	var tests = []struct {
+		parentID, expectedParentID string
+	}{
+		{"123456789012", "organizations/123456789012"},
+		{"organizations/123456789012", "organizations/123456789012"},
+		{"folders/987654321098", "folders/987654321098"},
+		{"", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.parentID
+
+		t.Run(testName, func(t *testing.T) {
+			parentID := plugin.NormalizeParentID(td.parentID)
+
+			if parentID != td.expectedParentID {
+				t.Errorf("Normalizing GCP parent ID failed; expected %s, received %s", td.expectedParentID, parentID)
+			}
+		})
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	rmPlug := rmPlugFactory()
+
+	projects, _ := rmPlug.GetResources()
+
+	expectedType := "Project"
+	for _, project := range projects {
+		projectType := reflect.TypeOf(project).Elem()
+		if projectType.Name() != expectedType {
+			t.Errorf("Fetching resources via GCP Resource Manager Plugin failed; wanted %s type, received %s", expectedType, projectType.Name())
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package vpn_gateway
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	gcpcomputeapi "cloud.google.com/go/compute/apiv1"
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
+
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	selflink "github.com/magneticstain/ip-2-cloudresource/gcp/self_link"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+type VPNGatewayPlugin struct {
+	GCPConn   gcpconnector.GCPConnector
+	InvCache  *inventorycache.InventoryCache
+	ProjectID string
+}
+
+func AddIPAddrToResource(vpnResource *generalResource.Resource, ipAddr string) error {
+	if ipAddr == "" {
+		return nil
+	}
+
+	ipVer, err := utils.DetermineIpAddrVersion(ipAddr)
+	if err != nil {
+		return err
+	}
+
+	if ipVer == 4 {
+		vpnResource.PublicIPv4Addrs = append(vpnResource.PublicIPv4Addrs, ipAddr)
+	} else {
+		vpnResource.PublicIPv6Addrs = append(vpnResource.PublicIPv6Addrs, ipAddr)
+	}
+
+	return nil
+}
+
+func (vpngwp VPNGatewayPlugin) ProcessVPNGateway(vpnGateway *gcpcomputepbapi.VpnGateway) (generalResource.Resource, error) {
+	vpnResource := generalResource.Resource{
+		Id:        strconv.FormatUint(vpnGateway.GetId(), 10),
+		RID:       selflink.GetResourcePath(vpnGateway.GetSelfLink()),
+		AccountID: vpngwp.ProjectID,
+		Name:      vpnGateway.GetName(),
+		CloudSvc:  "vpn_gateway",
+	}
+
+	// HA VPN gateways have one external IP per interface
+	for _, vpnInterface := range vpnGateway.GetVpnInterfaces() {
+		err := AddIPAddrToResource(&vpnResource, vpnInterface.GetIpAddress())
+		if err != nil {
+			return vpnResource, err
+		}
+
+		err = AddIPAddrToResource(&vpnResource, vpnInterface.GetIpv6Address())
+		if err != nil {
+			return vpnResource, err
+		}
+	}
+
+	return vpnResource, nil
+}
+
+func (vpngwp VPNGatewayPlugin) ProcessTargetVPNGateway(tgtVPNGateway *gcpcomputepbapi.TargetVpnGateway, fwdRuleIPAddrs map[string]string) (generalResource.Resource, error) {
+	vpnResource := generalResource.Resource{
+		Id:        strconv.FormatUint(tgtVPNGateway.GetId(), 10),
+		RID:       selflink.GetResourcePath(tgtVPNGateway.GetSelfLink()),
+		AccountID: vpngwp.ProjectID,
+		Name:      tgtVPNGateway.GetName(),
+		Status:    tgtVPNGateway.GetStatus(),
+		CloudSvc:  "vpn_gateway",
+	}
+
+	// classic VPN gateways don't hold their IP directly; it's set on the forwarding rules pointing at them
+	for _, fwdRule := range tgtVPNGateway.GetForwardingRules() {
+		err := AddIPAddrToResource(&vpnResource, fwdRuleIPAddrs[selflink.GetResourcePath(fwdRule)])
+		if err != nil {
+			return vpnResource, err
+		}
+	}
+
+	return vpnResource, nil
+}
+
+func (vpngwp VPNGatewayPlugin) GetVPNGatewayResources(ctx context.Context) ([]generalResource.Resource, error) {
+	var vpnResources []generalResource.Resource
+
+	vpnGwClient, err := gcpcomputeapi.NewVpnGatewaysRESTClient(ctx, vpngwp.GCPConn.ClientOptions...)
+	if err != nil {
+		return vpnResources, err
+	}
+	defer vpnGwClient.Close()
+
+	req := &gcpcomputepbapi.AggregatedListVpnGatewaysRequest{
+		Project:              vpngwp.ProjectID,
+		ReturnPartialSuccess: proto.Bool(true),
+	}
+
+	vpnGwList := vpnGwClient.AggregatedList(ctx, req)
+	for {
+		vpnGwListPair, err := vpnGwList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return vpnResources, err
+		}
+
+		for _, vpnGateway := range vpnGwListPair.Value.GetVpnGateways() {
+			vpnResource, err := vpngwp.ProcessVPNGateway(vpnGateway)
+			if err != nil {
+				return vpnResources, err
+			}
+
+			log.Debug("HA VPN gateway found - ID: ", vpnResource.Id, ", Name: ", vpnResource.Name)
+
+			vpnResources = append(vpnResources, vpnResource)
+		}
+	}
+
+	return vpnResources, nil
+}
+
+func (vpngwp VPNGatewayPlugin) GetVPNForwardingRuleIPAddrs(ctx context.Context) (map[string]string, error) {
+	// forwarding rule path => IP
+	fwdRuleIPAddrs := make(map[string]string)
+
+	frClient, err := gcpcomputeapi.NewForwardingRulesRESTClient(ctx, vpngwp.GCPConn.ClientOptions...)
+	if err != nil {
+		return fwdRuleIPAddrs, err
+	}
+	defer frClient.Close()
+
+	req := &gcpcomputepbapi.AggregatedListForwardingRulesRequest{
+		Project:              vpngwp.ProjectID,
+		Filter:               proto.String("target eq .*/targetVpnGateways/.*"),
+		ReturnPartialSuccess: proto.Bool(true),
+	}
+
+	frList := frClient.AggregatedList(ctx, req)
+	for {
+		frListPair, err := frList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return fwdRuleIPAddrs, err
+		}
+
+		for _, fwdRule := range frListPair.Value.GetForwardingRules() {
+			fwdRuleIPAddrs[selflink.GetResourcePath(fwdRule.GetSelfLink())] = fwdRule.GetIPAddress()
+		}
+	}
+
+	return fwdRuleIPAddrs, nil
+}
+
+func (vpngwp VPNGatewayPlugin) GetTargetVPNGatewayResources(ctx context.Context) ([]generalResource.Resource, error) {
+	var vpnResources []generalResource.Resource
+
+	fwdRuleIPAddrs, err := vpngwp.GetVPNForwardingRuleIPAddrs(ctx)
+	if err != nil {
+		return vpnResources, err
+	}
+
+	tgtVPNGwClient, err := gcpcomputeapi.NewTargetVpnGatewaysRESTClient(ctx, vpngwp.GCPConn.ClientOptions...)
+	if err != nil {
+		return vpnResources, err
+	}
+	defer tgtVPNGwClient.Close()
+
+	req := &gcpcomputepbapi.AggregatedListTargetVpnGatewaysRequest{
+		Project:              vpngwp.ProjectID,
+		ReturnPartialSuccess: proto.Bool(true),
+	}
+
+	tgtVPNGwList := tgtVPNGwClient.AggregatedList(ctx, req)
+	for {
+		tgtVPNGwListPair, err := tgtVPNGwList.Next()
+		if err == iterator.Done {
+			break
+		} else if err != nil {
+			return vpnResources, err
+		}
+
+		for _, tgtVPNGateway := range tgtVPNGwListPair.Value.GetTargetVpnGateways() {
+			vpnResource, err := vpngwp.ProcessTargetVPNGateway(tgtVPNGateway, fwdRuleIPAddrs)
+			if err != nil {
+				return vpnResources, err
+			}
+
+			log.Debug("classic VPN gateway found - ID: ", vpnResource.Id, ", Name: ", vpnResource.Name, ", Status: ", vpnResource.Status)
+
+			vpnResources = append(vpnResources, vpnResource)
+		}
+	}
+
+	return vpnResources, nil
+}
+
+func (vpngwp VPNGatewayPlugin) GetResources() ([]generalResource.Resource, error) {
+	var vpnResources []generalResource.Resource
+
+	ctx := context.Background()
+
+	haVPNResources, err := vpngwp.GetVPNGatewayResources(ctx)
+	if err != nil {
+		return vpnResources, err
+	}
+	vpnResources = append(vpnResources, haVPNResources...)
+
+	classicVPNResources, err := vpngwp.GetTargetVPNGatewayResources(ctx)
+	if err != nil {
+		return vpnResources, err
+	}
+	vpnResources = append(vpnResources, classicVPNResources...)
+
+	return vpnResources, nil
+}
+
+func (vpngwp VPNGatewayPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
+	log.Debug("fetching and searching VPN gateway resources")
+
+	vpnResources, err := inventorycache.Fetch(vpngwp.InvCache, "vpn_gateway/"+vpngwp.ProjectID, vpngwp.GetResources)
+	if err != nil {
+		return *matchingResource, err
+	}
+
+	for _, vpnResource := range vpnResources {
+		if slices.Contains(vpnResource.PublicIPv4Addrs, tgtIP) || slices.Contains(vpnResource.PublicIPv6Addrs, tgtIP) {
+			*matchingResource = vpnResource
+
+			log.Debug("IP found as VPN gateway -> ", matchingResource.RID)
+
+			break
+		}
+	}
+
+	return *matchingResource, nil
+}
+
+func (vpngwp VPNGatewayPlugin) GetResourceInventory() ([]generalResource.Resource, error) {
+	return inventorycache.Fetch(vpngwp.InvCache, "vpn_gateway/"+vpngwp.ProjectID, vpngwp.GetResources)
+}
@@ -0,0 +1,139 @@
+package vpn_gateway_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/protobuf/proto"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/vpn_gateway"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+const selfLinkPrefix = "https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1/"
+
+func vpngwPlugFactory() plugin.VPNGatewayPlugin {
+	vpngwPlug := plugin.VPNGatewayPlugin{ProjectID: "my-project"}
+
+	return vpngwPlug
+}
+
+func TestProcessVPNGateway(t *testing.T) {
+	vpngwPlug := vpngwPlugFactory()
+
+	vpnGateway := &gcpcomputepbapi.VpnGateway{
+		Id:       proto.Uint64(1234),
+		Name:     proto.String("ha-vpn"),
+		SelfLink: proto.String(selfLinkPrefix + "vpnGateways/ha-vpn"),
+		VpnInterfaces: []*gcpcomputepbapi.VpnGatewayVpnGatewayInterface{
+			{IpAddress: proto.String("34.1.2.3")},
+			{IpAddress: proto.String("34.1.2.4"), Ipv6Address: proto.String("2600:1900:4000::1")},
+		},
+	}
+
+	vpnResource, err := vpngwPlug.ProcessVPNGateway(vpnGateway)
+	if err != nil {
+		t.Errorf("Processing HA VPN gateway failed; received error: %s", err)
+	}
+
+	if !slices.Equal(vpnResource.PublicIPv4Addrs, []string{"34.1.2.3", "34.1.2.4"}) || !slices.Equal(vpnResource.PublicIPv6Addrs, []string{"2600:1900:4000::1"}) {
+		t.Errorf("Processing HA VPN gateway failed; received IPv4 IPs %s and IPv6 IPs %s", vpnResource.PublicIPv4Addrs, vpnResource.PublicIPv6Addrs)
+	}
+
+	if vpnResource.RID != "projects/my-project/regions/us-central1/vpnGateways/ha-vpn" {
+		t.Errorf("Processing HA VPN gateway failed; received unexpected RID %s", vpnResource.RID)
+	}
+}
+
+func TestProcessTargetVPNGateway(t *testing.T) {
+	vpngwPlug := vpngwPlugFactory()
+
+	tgtVPNGateway := &gcpcomputepbapi.TargetVpnGateway{
+		Id:       proto.Uint64(5678),
+		Name:     proto.String("classic-vpn"),
+		Status:   proto.String("READY"),
+		SelfLink: proto.String(selfLinkPrefix + "targetVpnGateways/classic-vpn"),
+		ForwardingRules: []string{
+			selfLinkPrefix + "forwardingRules/classic-vpn-esp",
+			selfLinkPrefix + "forwardingRules/classic-vpn-udp500",
+		},
+	}
+	fwdRuleIPAddrs := map[string]string{
+		"projects/my-project/regions/us-central1/forwardingRules/classic-vpn-esp":    "35.1.2.3",
+		"projects/my-project/regions/us-central1/forwardingRules/classic-vpn-udp500": "35.1.2.3",
+		"projects/my-project/regions/us-central1/forwardingRules/other-rule":         "35.1.2.4",
+	}
+
+	vpnResource, err := vpngwPlug.ProcessTargetVPNGateway(tgtVPNGateway, fwdRuleIPAddrs)
+	if err != nil {
+		t.Errorf("Processing classic VPN gateway failed; received error: %s", err)
+	}
+
+	if !slices.Contains(vpnResource.PublicIPv4Addrs, "35.1.2.3") || slices.Contains(vpnResource.PublicIPv4Addrs, "35.1.2.4") {
+		t.Errorf("Processing classic VPN gateway failed; received IPs %s", vpnResource.PublicIPv4Addrs)
+	}
+
+	if vpnResource.Status != "READY" {
+		t.Errorf("Processing classic VPN gateway failed; expected READY status, received %s", vpnResource.Status)
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	vpngwPlug := vpngwPlugFactory()
+
+	vpnResources, _ := vpngwPlug.GetResources()
+
+	expectedType := "Resource"
+	for _, vpnResource := range vpnResources {
+		vpnResourceType := reflect.TypeOf(vpnResource)
+		if vpnResourceType.Name() != expectedType {
+			t.Errorf("Fetching resources via VPN Gateway Plugin failed; wanted %s type, received %s", expectedType, vpnResourceType.Name())
+		}
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	vpngwPlug := vpngwPlugFactory()
+
+	var tests = []struct {
+		ipAddr string
+	}{
+		{"1.1.1.1"},
+		{"1234.45.9666.1"},
+		{"18.161.22.61"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21"},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			var matchingResource generalResource.Resource
+
+			res, _ := vpngwPlug.SearchResources(td.ipAddr, &matchingResource)
+
+			expectedType := "Resource"
+			resType := reflect.TypeOf(res)
+			if resType.Name() != expectedType {
+				t.Errorf("VPN gateway search failed; expected %s after search, received %s", expectedType, resType.Name())
+			}
+		})
+	}
+}
+
+func TestGetResourceInventory(t *testing.T) {
+	vpngwPlug := vpngwPlugFactory()
+
+	inventory, _ := vpngwPlug.GetResourceInventory()
+
+	expectedType := "Resource"
+	for _, resource := range inventory {
+		resourceType := reflect.TypeOf(resource)
+		if resourceType.Name() != expectedType {
+			t.Errorf("Fetching resource inventory via GCP VPN Gateway Plugin failed; wanted %s type, received %s", expectedType, resourceType.Name())
+		}
+	}
+}
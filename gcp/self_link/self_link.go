@@ -0,0 +1,77 @@
+package selflink
+
+/*
+DEV NOTE:
+---
+GCP resources reference each other by self-link URLs, e.g.:
+
+https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1/forwardingRules/my-rule
+
+The helpers here pick those URLs apart so we can follow references between resources and report a stable, readable resource path back to the user.
+*/
+
+import (
+	"strings"
+)
+
+const computeAPIPrefix = "/compute/v1/"
+
+func GetResourcePath(selfLink string) string {
+	// EX: projects/my-project/regions/us-central1/forwardingRules/my-rule
+	if _, resourcePath, found := strings.Cut(selfLink, computeAPIPrefix); found {
+		return resourcePath
+	}
+
+	return strings.TrimPrefix(selfLink, "/")
+}
+
+func GetResourceName(selfLink string) string {
+	return selfLink[strings.LastIndex(selfLink, "/")+1:]
+}
+
+func GetResourceType(selfLink string) string {
+	// the resource type is always the second to last path element
+	pathElmnts := strings.Split(GetResourcePath(selfLink), "/")
+	if len(pathElmnts) < 2 {
+		return ""
+	}
+
+	return pathElmnts[len(pathElmnts)-2]
+}
+
+func GetProject(selfLink string) string {
+	pathElmnts := strings.Split(GetResourcePath(selfLink), "/")
+	if len(pathElmnts) < 2 || pathElmnts[0] != "projects" {
+		return ""
+	}
+
+	return pathElmnts[1]
+}
+
+func GetLocation(selfLink string) (string, string) {
+	// returns the scope type (global, regions, or zones) along with the region or zone name, if there is one
+	pathElmnts := strings.Split(GetResourcePath(selfLink), "/")
+	if len(pathElmnts) < 3 {
+		return "", ""
+	}
+
+	switch pathElmnts[2] {
+	case "regions", "zones":
+		if len(pathElmnts) > 3 {
+			return pathElmnts[2], pathElmnts[3]
+		}
+	case "global":
+		return "global", ""
+	}
+
+	return "", ""
+}
+
+func GetRegionFromScope(scope string) string {
+	// aggregated list scopes are formatted as e.g. regions/us-central1, zones/us-central1-a, or global
+	if _, region, found := strings.Cut(scope, "/"); found {
+		return region
+	}
+
+	return scope
+}
@@ -0,0 +1,104 @@
+package selflink_test
+
+import (
+	"testing"
+
+	selflink "github.com/magneticstain/ip-2-cloudresource/gcp/self_link"
+)
+
+func TestGetResourcePath(t *testing.T) {
+	var tests = []struct {
+		selfLink, expectedPath string
+	}{
+		{"https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1/forwardingRules/my-rule", "projects/my-project/regions/us-central1/forwardingRules/my-rule"},
+		{"projects/my-project/global/backendServices/my-backend", "projects/my-project/global/backendServices/my-backend"},
+		{"/projects/my-project/zones/us-central1-a/instances/my-vm", "projects/my-project/zones/us-central1-a/instances/my-vm"},
+	}
+
+	for _, td := range tests {
+		testName := td.expectedPath
+
+		t.Run(testName, func(t *testing.T) {
+			resourcePath := selflink.GetResourcePath(td.selfLink)
+
+			if resourcePath != td.expectedPath {
+				t.Errorf("Parsing GCP self-link resource path failed; expected %s, received %s", td.expectedPath, resourcePath)
+			}
+		})
+	}
+}
+
+func TestGetResourceNameAndType(t *testing.T) {
+	var tests = []struct {
+		selfLink, expectedName, expectedType string
+	}{
+		{"https://www.googleapis.com/compute/v1/projects/my-project/global/targetHttpsProxies/my-proxy", "my-proxy", "targetHttpsProxies"},
+		{"https://www.googleapis.com/compute/v1/projects/my-project/regions/us-east1/targetPools/my-pool", "my-pool", "targetPools"},
+		{"my-resource", "my-resource", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.selfLink
+
+		t.Run(testName, func(t *testing.T) {
+			resourceName := selflink.GetResourceName(td.selfLink)
+			if resourceName != td.expectedName {
+				t.Errorf("Parsing GCP self-link resource name failed; expected %s, received %s", td.expectedName, resourceName)
+			}
+
+			resourceType := selflink.GetResourceType(td.selfLink)
+			if resourceType != td.expectedType {
+				t.Errorf("Parsing GCP self-link resource type failed; expected %s, received %s", td.expectedType, resourceType)
+			}
+		})
+	}
+}
+
+func TestGetProjectAndLocation(t *testing.T) {
+	var tests = []struct {
+		selfLink, expectedProject, expectedScopeType, expectedLocation string
+	}{
+		{"https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1/backendServices/my-backend", "my-project", "regions", "us-central1"},
+		{"https://www.googleapis.com/compute/v1/projects/shared-vpc/zones/us-central1-a/instanceGroups/my-ig", "shared-vpc", "zones", "us-central1-a"},
+		{"https://www.googleapis.com/compute/v1/projects/my-project/global/urlMaps/my-url-map", "my-project", "global", ""},
+		{"not_a_self_link", "", "", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.selfLink
+
+		t.Run(testName, func(t *testing.T) {
+			project := selflink.GetProject(td.selfLink)
+			if project != td.expectedProject {
+				t.Errorf("Parsing GCP self-link project failed; expected %s, received %s", td.expectedProject, project)
+			}
+
+			scopeType, location := selflink.GetLocation(td.selfLink)
+			if scopeType != td.expectedScopeType || location != td.expectedLocation {
+				t.Errorf("Parsing GCP self-link location failed; expected %s/%s, received %s/%s", td.expectedScopeType, td.expectedLocation, scopeType, location)
+			}
+		})
+	}
+}
+
+func TestGetRegionFromScope(t *testing.T) {
+	var tests = []struct {
+		scope, expectedRegion string
+	}{
+		{"regions/us-central1", "us-central1"},
+		{"zones/us-central1-a", "us-central1-a"},
+		{"global", "global"},
+	}
+
+	for _, td := range tests {
+		testName := td.scope
+
+		t.Run(testName, func(t *testing.T) {
+			region := selflink.GetRegionFromScope(td.scope)
+
+			if region != td.expectedRegion {
+				t.Errorf("Parsing GCP aggregated list scope failed; expected %s, received %s", td.expectedRegion, region)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package ipfuzzing
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	gcpipprefix "github.com/magneticstain/ip-2-cloudresource/gcp/svc/ip_fuzzing/models/gcp_ip_prefix"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// returned in place of a region when the IP isn't within a Google Cloud customer range
+const (
+	// owned by Google, but used by Google itself (e.g. front ends, APIs, etc) rather than being assigned to a customer resource
+	GoogleOwnedScope = "GOOGLE"
+	// not within any published Google range; this may still be a customer resource if it's a BYOIP address
+	UnknownScope = "UNKNOWN"
+)
+
+func ResolveIPAddrToScope(ipAddr string, ipVer int, cloudIPSet, googIPSet gcpipprefix.RawGCPIPRangeJSON) (string, error) {
+	cloudPrefixSet, err := ConvertIPPrefixesToGeneric(cloudIPSet.Prefixes, ipVer)
+	if err != nil {
+		return UnknownScope, err
+	}
+
+	cloudPrefix, found, err := ResolveIPAddrToPrefix(ipAddr, cloudPrefixSet)
+	if err != nil {
+		return UnknownScope, err
+	} else if found {
+		log.Debug("IP found in Google Cloud range [ ", cloudPrefix.IPRange, " ] with scope [ ", cloudPrefix.Scope, " ]")
+
+		return cloudPrefix.Scope, nil
+	}
+
+	// the goog.json ranges include the cloud ranges, so we can only assume the IP is Google-owned once we know it isn't a cloud IP
+	googPrefixSet, err := ConvertIPPrefixesToGeneric(googIPSet.Prefixes, ipVer)
+	if err != nil {
+		return UnknownScope, err
+	}
+
+	googPrefix, found, err := ResolveIPAddrToPrefix(ipAddr, googPrefixSet)
+	if err != nil {
+		return UnknownScope, err
+	} else if found {
+		log.Debug("IP found in Google-owned range [ ", googPrefix.IPRange, " ]")
+
+		return GoogleOwnedScope, nil
+	}
+
+	return UnknownScope, nil
+}
+
+func FuzzIP(ipAddr string) (string, error) {
+	// returns the region (i.e. scope) of the Google Cloud range the IP is in, or one of the non-customer scopes if it's not in one
+	parsedIPVer, err := utils.DetermineIpAddrVersion(ipAddr)
+	if err != nil {
+		return UnknownScope, err
+	}
+
+	cloudIPSet, err := FetchCloudIPRanges()
+	if err != nil {
+		return UnknownScope, err
+	}
+	log.Debug("Google Cloud public IP dataset loaded")
+
+	googIPSet, err := FetchGoogIPRanges()
+	if err != nil {
+		return UnknownScope, err
+	}
+	log.Debug("Google-owned public IP dataset loaded")
+
+	return ResolveIPAddrToScope(ipAddr, parsedIPVer, cloudIPSet, googIPSet)
+}
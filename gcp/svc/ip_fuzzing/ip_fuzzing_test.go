@@ -0,0 +1,103 @@
+package ipfuzzing_test
+
+import (
+	"fmt"
+	"testing"
+
+	ipfuzzing "github.com/magneticstain/ip-2-cloudresource/gcp/svc/ip_fuzzing"
+	gcpipprefix "github.com/magneticstain/ip-2-cloudresource/gcp/svc/ip_fuzzing/models/gcp_ip_prefix"
+)
+
+func cloudIPSetFactory() gcpipprefix.RawGCPIPRangeJSON {
+	return gcpipprefix.RawGCPIPRangeJSON{
+		Prefixes: []gcpipprefix.GCPPrefix{
+			{IPv4Prefix: "34.1.208.0/20", Service: "Google Cloud", Scope: "africa-south1"},
+			{IPv4Prefix: "34.16.0.0/17", Service: "Google Cloud", Scope: "us-central1"},
+			{IPv6Prefix: "2600:1900:4000::/44", Service: "Google Cloud", Scope: "us-central1"},
+		},
+	}
+}
+
+func googIPSetFactory() gcpipprefix.RawGCPIPRangeJSON {
+	return gcpipprefix.RawGCPIPRangeJSON{
+		Prefixes: []gcpipprefix.GCPPrefix{
+			{IPv4Prefix: "8.8.4.0/24"},
+			{IPv4Prefix: "34.0.0.0/15"},
+			{IPv4Prefix: "34.16.0.0/12"},
+			{IPv6Prefix: "2001:4860::/32"},
+			{IPv6Prefix: "2600:1900::/28"},
+		},
+	}
+}
+
+func TestConvertIPPrefixesToGeneric(t *testing.T) {
+	var tests = []struct {
+		ipVer, expectedPrefixCnt int
+	}{
+		{4, 2},
+		{6, 1},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("IPv%d", td.ipVer)
+
+		t.Run(testName, func(t *testing.T) {
+			prefixes, err := ipfuzzing.ConvertIPPrefixesToGeneric(cloudIPSetFactory().Prefixes, td.ipVer)
+			if err != nil {
+				t.Errorf("Converting GCP IP prefixes to generic failed; error: %s", err)
+			}
+
+			if len(prefixes) != td.expectedPrefixCnt {
+				t.Errorf("Converting GCP IP prefixes to generic failed; expected %d prefixes, received %d", td.expectedPrefixCnt, len(prefixes))
+			}
+		})
+	}
+}
+
+func TestConvertIPPrefixesToGeneric_InvalidIPVersion(t *testing.T) {
+	_, err := ipfuzzing.ConvertIPPrefixesToGeneric(cloudIPSetFactory().Prefixes, 5)
+	if err == nil {
+		t.Errorf("Error was expected, but not seen, when converting GCP IP prefixes using an invalid IP version")
+	}
+}
+
+func TestResolveIPAddrToScope(t *testing.T) {
+	var tests = []struct {
+		ipAddr, expectedScope string
+		ipVer                 int
+	}{
+		{"34.1.210.5", "africa-south1", 4},
+		{"34.16.12.1", "us-central1", 4},
+		{"2600:1900:4000:1::", "us-central1", 6},
+		{"8.8.4.4", ipfuzzing.GoogleOwnedScope, 4},
+		{"34.20.1.1", ipfuzzing.GoogleOwnedScope, 4},
+		{"2001:4860:4860::8888", ipfuzzing.GoogleOwnedScope, 6},
+		{"1.1.1.1", ipfuzzing.UnknownScope, 4},
+		{"18.161.22.61", ipfuzzing.UnknownScope, 4},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", ipfuzzing.UnknownScope, 6},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			scope, err := ipfuzzing.ResolveIPAddrToScope(td.ipAddr, td.ipVer, cloudIPSetFactory(), googIPSetFactory())
+			if err != nil {
+				t.Errorf("Resolving IP to GCP scope failed; error: %s", err)
+			}
+
+			if scope != td.expectedScope {
+				t.Errorf("Resolving IP to GCP scope failed; IP: %s, expected %s, received %s", td.ipAddr, td.expectedScope, scope)
+			}
+		})
+	}
+}
+
+func TestResolveIPAddrToPrefix_InvalidPrefix(t *testing.T) {
+	prefixSet := []gcpipprefix.GenericGCPPrefix{{IPRange: "34.1.208.0/99"}}
+
+	_, _, err := ipfuzzing.ResolveIPAddrToPrefix("34.1.210.5", prefixSet)
+	if err == nil {
+		t.Errorf("Error was expected, but not seen, when resolving IP using an invalid prefix")
+	}
+}
@@ -0,0 +1,114 @@
+package ipfuzzing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	gcpipprefix "github.com/magneticstain/ip-2-cloudresource/gcp/svc/ip_fuzzing/models/gcp_ip_prefix"
+)
+
+// cloud.json only includes ranges available to Google Cloud customers, while goog.json includes every range owned by Google (including the cloud ranges)
+// REF: https://cloud.google.com/compute/docs/faq#find_ip_range
+const gcpCloudIPRangeURL string = "https://www.gstatic.com/ipranges/cloud.json"
+const gcpGoogIPRangeURL string = "https://www.gstatic.com/ipranges/goog.json"
+
+// the IP ranges are only fetched once per run so that searching for multiple IPs doesn't refetch them for each one
+var ipRangeCache = struct {
+	sync.Mutex
+	data map[string]gcpipprefix.RawGCPIPRangeJSON
+}{data: make(map[string]gcpipprefix.RawGCPIPRangeJSON)}
+
+func FetchIPRanges(ipRangeURL string) (gcpipprefix.RawGCPIPRangeJSON, error) {
+	var ipRangeData gcpipprefix.RawGCPIPRangeJSON
+
+	ipRangeCache.Lock()
+	defer ipRangeCache.Unlock()
+
+	if cachedIPRangeData, found := ipRangeCache.data[ipRangeURL]; found {
+		return cachedIPRangeData, nil
+	}
+
+	resp, err := http.Get(ipRangeURL)
+	if err != nil {
+		return ipRangeData, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ipRangeData, fmt.Errorf("received HTTP status %s when fetching IP ranges from remote URL :: [ URL: %s ]", resp.Status, ipRangeURL)
+	}
+
+	jsonData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ipRangeData, err
+	}
+
+	jsonErr := json.Unmarshal(jsonData, &ipRangeData)
+	if jsonErr != nil {
+		return ipRangeData, jsonErr
+	}
+
+	ipRangeCache.data[ipRangeURL] = ipRangeData
+
+	return ipRangeData, nil
+}
+
+func FetchCloudIPRanges() (gcpipprefix.RawGCPIPRangeJSON, error) {
+	return FetchIPRanges(gcpCloudIPRangeURL)
+}
+
+func FetchGoogIPRanges() (gcpipprefix.RawGCPIPRangeJSON, error) {
+	return FetchIPRanges(gcpGoogIPRangeURL)
+}
+
+func ConvertIPPrefixesToGeneric(prefixes []gcpipprefix.GCPPrefix, ipVer int) ([]gcpipprefix.GenericGCPPrefix, error) {
+	// unlike AWS, Google lists both IP versions in a single prefix set, so we only keep the prefixes matching the given version
+	var ipPrefixes []gcpipprefix.GenericGCPPrefix
+	var ipRange string
+
+	for _, prefix := range prefixes {
+		switch ipVer {
+		case 4:
+			ipRange = prefix.IPv4Prefix
+		case 6:
+			ipRange = prefix.IPv6Prefix
+		default:
+			return ipPrefixes, fmt.Errorf("%d is not a valid IP version; must be 4 or 6", ipVer)
+		}
+
+		if ipRange == "" {
+			continue
+		}
+
+		ipPrefixes = append(ipPrefixes, gcpipprefix.GenericGCPPrefix{
+			IPRange: ipRange,
+			Scope:   prefix.Scope,
+			Service: prefix.Service,
+		})
+	}
+
+	return ipPrefixes, nil
+}
+
+func ResolveIPAddrToPrefix(ipAddr string, ipPrefixSet []gcpipprefix.GenericGCPPrefix) (gcpipprefix.GenericGCPPrefix, bool, error) {
+	var matchingPrefix gcpipprefix.GenericGCPPrefix
+	parsedIPAddr := net.ParseIP(ipAddr)
+
+	for _, ipPrefix := range ipPrefixSet {
+		_, cidrNet, err := net.ParseCIDR(ipPrefix.IPRange)
+		if err != nil {
+			return matchingPrefix, false, err
+		}
+
+		if cidrNet.Contains(parsedIPAddr) {
+			// target IP is within this IP range
+			return ipPrefix, true, nil
+		}
+	}
+
+	return matchingPrefix, false, nil
+}
@@ -0,0 +1,21 @@
+package gcpipprefix
+
+type GenericGCPPrefix struct {
+	IPRange string
+	Scope   string
+	Service string
+}
+
+type GCPPrefix struct {
+	IPv4Prefix string `json:"ipv4Prefix"`
+	IPv6Prefix string `json:"ipv6Prefix"`
+	// only set for cloud.json prefixes; goog.json only lists the ranges themselves
+	Service string `json:"service"`
+	Scope   string `json:"scope"`
+}
+
+type RawGCPIPRangeJSON struct {
+	SyncToken    string      `json:"syncToken"`
+	CreationTime string      `json:"creationTime"`
+	Prefixes     []GCPPrefix `json:"prefixes"`
+}
@@ -0,0 +1,94 @@
+package hostlookup
+
+import (
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// a cloud service that can be identified from its hostname alone
+type CloudHostname struct {
+	FQDN, Platform string
+	CloudSvcs      []string
+}
+
+type HostResolution struct {
+	Host string
+	// the host followed by each name its CNAME chain points to, in order
+	CNAMEChain []string
+	IPAddrs    []string
+	// set if any name in the chain is a cloud service's own hostname
+	RecognizedHostname CloudHostname
+}
+
+func getCloudHostnameRegexes() map[string]CloudHostname {
+	// maps hostname patterns to the platform and service(s) that hand them out
+	return map[string]CloudHostname{
+		`^[a-z0-9]+\.cloudfront\.net$`: {
+			Platform:  "aws",
+			CloudSvcs: []string{"cloudfront"},
+		},
+		`\.elb\.([a-z0-9\-]+\.)?amazonaws\.com(\.cn)?$`: {
+			Platform:  "aws",
+			CloudSvcs: []string{"elbv1", "elbv2"},
+		},
+		`\.azurefd\.net$`: {
+			Platform:  "azure",
+			CloudSvcs: []string{"cdn"},
+		},
+	}
+}
+
+func RecognizeHostname(fqdn string) (CloudHostname, bool) {
+	// ELB hostnames are also handed out with a dualstack. prefix, which isn't part of the name the ELB API returns
+	normalizedFQDN := strings.TrimPrefix(utils.NormalizeFQDN(fqdn), "dualstack.")
+
+	for hostnameRegex, cloudHostname := range getCloudHostnameRegexes() {
+		if regexp.MustCompile(hostnameRegex).MatchString(normalizedFQDN) {
+			cloudHostname.FQDN = normalizedFQDN
+
+			return cloudHostname, true
+		}
+	}
+
+	return CloudHostname{}, false
+}
+
+func Resolve(host string, followCNAMEs bool) (HostResolution, error) {
+	var err error
+
+	hostResolution := HostResolution{Host: utils.NormalizeFQDN(host), CNAMEChain: []string{utils.NormalizeFQDN(host)}}
+
+	if followCNAMEs {
+		hostResolution.CNAMEChain, err = utils.LookupCNAMEChain(host)
+		if err != nil {
+			return hostResolution, err
+		}
+
+		log.Debug("CNAME chain for ", host, ": ", hostResolution.CNAMEChain)
+	}
+
+	for _, chainFQDN := range hostResolution.CNAMEChain {
+		if cloudHostname, found := RecognizeHostname(chainFQDN); found {
+			hostResolution.RecognizedHostname = cloudHostname
+
+			log.Debug(chainFQDN, " recognized as ", strings.Join(cloudHostname.CloudSvcs, "/"), " hostname on ", cloudHostname.Platform)
+
+			break
+		}
+	}
+
+	ipAddrs, err := utils.LookupFQDN(hostResolution.CNAMEChain[len(hostResolution.CNAMEChain)-1])
+	if err != nil {
+		return hostResolution, err
+	}
+
+	for _, ipAddr := range ipAddrs {
+		hostResolution.IPAddrs = append(hostResolution.IPAddrs, ipAddr.String())
+	}
+
+	return hostResolution, nil
+}
@@ -0,0 +1,75 @@
+package hostlookup_test
+
+import (
+	"slices"
+	"testing"
+
+	hostlookup "github.com/magneticstain/ip-2-cloudresource/host_lookup"
+)
+
+func TestRecognizeHostname(t *testing.T) {
+	var tests = []struct {
+		fqdn, expectedPlatform string
+		expectedCloudSvcs      []string
+		expectedFound          bool
+	}{
+		{"d111111abcdef8.cloudfront.net", "aws", []string{"cloudfront"}, true},
+		{"D111111ABCDEF8.CloudFront.net.", "aws", []string{"cloudfront"}, true},
+		{"my-lb-1234567890.us-east-1.elb.amazonaws.com", "aws", []string{"elbv1", "elbv2"}, true},
+		{"dualstack.my-lb-1234567890.us-east-1.elb.amazonaws.com", "aws", []string{"elbv1", "elbv2"}, true},
+		{"my-nlb-1234567890abcdef.elb.us-east-1.amazonaws.com", "aws", []string{"elbv1", "elbv2"}, true},
+		{"my-endpoint-abcdefgh.z01.azurefd.net", "azure", []string{"cdn"}, true},
+		{"www.example.com", "", nil, false},
+		{"cloudfront.net.example.com", "", nil, false},
+	}
+
+	for _, td := range tests {
+		testName := td.fqdn
+
+		t.Run(testName, func(t *testing.T) {
+			cloudHostname, found := hostlookup.RecognizeHostname(td.fqdn)
+
+			if found != td.expectedFound || cloudHostname.Platform != td.expectedPlatform || !slices.Equal(cloudHostname.CloudSvcs, td.expectedCloudSvcs) {
+				t.Errorf("Recognizing hostname failed; expected %s %s (%t), received %s %s (%t)", td.expectedPlatform, td.expectedCloudSvcs, td.expectedFound, cloudHostname.Platform, cloudHostname.CloudSvcs, found)
+			}
+		})
+	}
+}
+
+func TestRecognizeHostname_NormalizedFQDN(t *testing.T) {
+	cloudHostname, _ := hostlookup.RecognizeHostname("DualStack.My-LB-1234567890.us-east-1.elb.amazonaws.com.")
+
+	expectedFQDN := "my-lb-1234567890.us-east-1.elb.amazonaws.com"
+	if cloudHostname.FQDN != expectedFQDN {
+		t.Errorf("Recognizing hostname failed; expected FQDN %s, received %s", expectedFQDN, cloudHostname.FQDN)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	var tests = []struct {
+		host         string
+		followCNAMEs bool
+	}{
+		{"localhost", false},
+		{"localhost", true},
+	}
+
+	for _, td := range tests {
+		testName := td.host
+
+		t.Run(testName, func(t *testing.T) {
+			hostResolution, err := hostlookup.Resolve(td.host, td.followCNAMEs)
+			if err != nil {
+				t.Fatalf("Resolving host failed; received error: %s", err)
+			}
+
+			if len(hostResolution.CNAMEChain) == 0 || hostResolution.CNAMEChain[0] != td.host {
+				t.Errorf("Resolving host failed; expected CNAME chain to start with %s, received %s", td.host, hostResolution.CNAMEChain)
+			}
+
+			if len(hostResolution.IPAddrs) == 0 {
+				t.Errorf("Resolving host failed; no IPs returned for %s", td.host)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package inventorycache
+
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// caches the resource inventories fetched by plugins so that searching for multiple IPs only lists each service's resources once
+// a nil cache is valid and simply disables caching, which is what single IP searches use
+type InventoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	scopes  map[string]*InventoryCache
+}
+
+type cacheEntry struct {
+	mu      sync.Mutex
+	fetched bool
+	data    any
+}
+
+func New() *InventoryCache {
+	return &InventoryCache{
+		entries: make(map[string]*cacheEntry),
+		scopes:  make(map[string]*InventoryCache),
+	}
+}
+
+func (ic *InventoryCache) Scope(scopeName string) *InventoryCache {
+	// returns a child cache for the given scope (e.g. an account or project), creating it if needed
+	if ic == nil {
+		return nil
+	}
+
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	scopedCache, found := ic.scopes[scopeName]
+	if !found {
+		scopedCache = New()
+		ic.scopes[scopeName] = scopedCache
+	}
+
+	return scopedCache
+}
+
+func (ic *InventoryCache) getEntry(key string) *cacheEntry {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	entry, found := ic.entries[key]
+	if !found {
+		entry = &cacheEntry{}
+		ic.entries[key] = entry
+	}
+
+	return entry
+}
+
+func Fetch[T any](ic *InventoryCache, key string, fetchFunc func() (T, error)) (T, error) {
+	// returns the cached inventory for the key, or runs the fetch function and caches its results if there isn't one yet
+	// only successful fetches are cached, so a failed fetch will be retried on the next call
+	if ic == nil {
+		return fetchFunc()
+	}
+
+	entry := ic.getEntry(key)
+
+	// concurrent fetches for the same key wait on the first one instead of listing the same resources in parallel
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.fetched {
+		log.Debug("using cached inventory for ", key)
+
+		return entry.data.(T), nil
+	}
+
+	data, err := fetchFunc()
+	if err != nil {
+		return data, err
+	}
+
+	entry.data = data
+	entry.fetched = true
+
+	return data, nil
+}
@@ -0,0 +1,85 @@
+package inventorycache_test
+
+import (
+	"errors"
+	"testing"
+
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+)
+
+func TestFetch(t *testing.T) {
+	fetchCnt := 0
+	fetchFunc := func() ([]string, error) {
+		fetchCnt++
+
+		return []string{"1.1.1.1"}, nil
+	}
+
+	invCache := inventorycache.New()
+	for i := 0; i < 3; i++ {
+		ipAddrs, err := inventorycache.Fetch(invCache, "ec2", fetchFunc)
+		if err != nil || len(ipAddrs) != 1 {
+			t.Errorf("Fetching cached inventory failed; received %s, error: %s", ipAddrs, err)
+		}
+	}
+
+	if fetchCnt != 1 {
+		t.Errorf("Fetching cached inventory failed; expected 1 fetch, received %d", fetchCnt)
+	}
+}
+
+func TestFetch_NilCache(t *testing.T) {
+	fetchCnt := 0
+	fetchFunc := func() ([]string, error) {
+		fetchCnt++
+
+		return []string{"1.1.1.1"}, nil
+	}
+
+	var invCache *inventorycache.InventoryCache
+	for i := 0; i < 3; i++ {
+		_, _ = inventorycache.Fetch(invCache, "ec2", fetchFunc)
+	}
+
+	if fetchCnt != 3 {
+		t.Errorf("Fetching inventory without cache failed; expected 3 fetches, received %d", fetchCnt)
+	}
+}
+
+func TestFetch_ErrorsNotCached(t *testing.T) {
+	fetchCnt := 0
+	fetchFunc := func() ([]string, error) {
+		fetchCnt++
+
+		return nil, errors.New("throttled")
+	}
+
+	invCache := inventorycache.New()
+	for i := 0; i < 2; i++ {
+		_, err := inventorycache.Fetch(invCache, "ec2", fetchFunc)
+		if err == nil {
+			t.Errorf("Fetching cached inventory should have returned an error, but did not")
+		}
+	}
+
+	if fetchCnt != 2 {
+		t.Errorf("Fetching cached inventory failed; expected failed fetches to be retried, received %d fetches", fetchCnt)
+	}
+}
+
+func TestScope(t *testing.T) {
+	invCache := inventorycache.New()
+
+	if invCache.Scope("123456789012") != invCache.Scope("123456789012") {
+		t.Errorf("Scoping inventory cache failed; expected the same cache for the same scope")
+	}
+
+	if invCache.Scope("123456789012") == invCache.Scope("210987654321") {
+		t.Errorf("Scoping inventory cache failed; expected different caches for different scopes")
+	}
+
+	var nilCache *inventorycache.InventoryCache
+	if nilCache.Scope("123456789012") != nil {
+		t.Errorf("Scoping nil inventory cache failed; expected nil cache")
+	}
+}
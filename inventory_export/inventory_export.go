@@ -0,0 +1,112 @@
+package inventoryexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func GetSupportedFormats() []string {
+	return []string{
+		"json",
+		"ndjson",
+		"csv",
+	}
+}
+
+func GetCSVHeader() []string {
+	return []string{
+		"account_id",
+		"account_aliases",
+		"cloud_svc",
+		"rid",
+		"name",
+		"status",
+		"location",
+		"ip_version",
+		"ip_addr",
+	}
+}
+
+func WriteJSON(writer io.Writer, resources []generalResource.Resource) error {
+	// always output an array, even if no resources were found, so the output can be parsed the same way every time
+	if resources == nil {
+		resources = []generalResource.Resource{}
+	}
+
+	jsonEncoder := json.NewEncoder(writer)
+	jsonEncoder.SetIndent("", "  ")
+
+	return jsonEncoder.Encode(resources)
+}
+
+func WriteNDJSON(writer io.Writer, resources []generalResource.Resource) error {
+	jsonEncoder := json.NewEncoder(writer)
+
+	for _, resource := range resources {
+		err := jsonEncoder.Encode(resource)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WriteCSV(writer io.Writer, resources []generalResource.Resource) error {
+	// one row per IP rather than per resource, so the output can be fed straight into scanners and spreadsheets
+	csvWriter := csv.NewWriter(writer)
+
+	err := csvWriter.Write(GetCSVHeader())
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range resources {
+		for _, ipAddrSet := range []struct {
+			ipVer   string
+			ipAddrs []string
+		}{
+			{"4", resource.PublicIPv4Addrs},
+			{"6", resource.PublicIPv6Addrs},
+		} {
+			for _, ipAddr := range ipAddrSet.ipAddrs {
+				err = csvWriter.Write([]string{
+					resource.AccountID,
+					strings.Join(resource.AccountAliases, ";"),
+					resource.CloudSvc,
+					resource.RID,
+					resource.Name,
+					resource.Status,
+					resource.Location,
+					ipAddrSet.ipVer,
+					ipAddr,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+func Write(writer io.Writer, resources []generalResource.Resource, format string) error {
+	switch strings.ToLower(format) {
+	case "json":
+		return WriteJSON(writer, resources)
+	case "ndjson":
+		return WriteNDJSON(writer, resources)
+	case "csv":
+		return WriteCSV(writer, resources)
+	default:
+		return fmt.Errorf("unsupported inventory output format: '%s'", format)
+	}
+}
@@ -0,0 +1,120 @@
+package inventoryexport_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	inventoryexport "github.com/magneticstain/ip-2-cloudresource/inventory_export"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func inventoryFactory() []generalResource.Resource {
+	return []generalResource.Resource{
+		{
+			RID:             "arn:aws:ec2:us-east-1:123456789012:instance/i-1234567890abcdef0",
+			AccountID:       "123456789012",
+			AccountAliases:  []string{"prod", "prod-legacy"},
+			CloudSvc:        "ec2",
+			PublicIPv4Addrs: []string{"18.161.22.61"},
+			PublicIPv6Addrs: []string{"2600:9000:24eb:dc00:1:3b80:4f00:21"},
+		},
+		{
+			RID:             "arn:aws:cloudfront::123456789012:distribution/E1234567890ABC",
+			AccountID:       "123456789012",
+			CloudSvc:        "cloudfront",
+			PublicIPv4Addrs: []string{"1.1.1.1", "18.161.22.62"},
+		},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var tests = []struct {
+		format        string
+		expectedLines int
+	}{
+		{"json", -1},
+		{"ndjson", 2},
+		{"csv", 5},
+		{"CSV", 5},
+	}
+
+	for _, td := range tests {
+		testName := td.format
+
+		t.Run(testName, func(t *testing.T) {
+			var output bytes.Buffer
+
+			err := inventoryexport.Write(&output, inventoryFactory(), td.format)
+			if err != nil {
+				t.Fatalf("Writing inventory failed; received error: %s", err)
+			}
+
+			if td.expectedLines >= 0 {
+				outputLines := strings.Split(strings.TrimSpace(output.String()), "\n")
+				if len(outputLines) != td.expectedLines {
+					t.Errorf("Writing inventory failed; expected %d lines, received %d", td.expectedLines, len(outputLines))
+				}
+			}
+		})
+	}
+}
+
+func TestWrite_InvalidFormat(t *testing.T) {
+	var output bytes.Buffer
+
+	err := inventoryexport.Write(&output, inventoryFactory(), "xml")
+	if err == nil {
+		t.Errorf("Writing inventory should have failed for an unsupported format, but did not")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var output bytes.Buffer
+	var resources []generalResource.Resource
+
+	err := inventoryexport.WriteJSON(&output, inventoryFactory())
+	if err != nil {
+		t.Fatalf("Writing JSON inventory failed; received error: %s", err)
+	}
+
+	err = json.Unmarshal(output.Bytes(), &resources)
+	if err != nil || len(resources) != 2 {
+		t.Errorf("Writing JSON inventory failed; expected 2 resources, received %d (error: %s)", len(resources), err)
+	}
+}
+
+func TestWriteJSON_Empty(t *testing.T) {
+	var output bytes.Buffer
+
+	err := inventoryexport.WriteJSON(&output, nil)
+	if err != nil {
+		t.Fatalf("Writing JSON inventory failed; received error: %s", err)
+	}
+
+	if strings.TrimSpace(output.String()) != "[]" {
+		t.Errorf("Writing empty JSON inventory failed; expected [], received %s", output.String())
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var output bytes.Buffer
+
+	err := inventoryexport.WriteCSV(&output, inventoryFactory())
+	if err != nil {
+		t.Fatalf("Writing CSV inventory failed; received error: %s", err)
+	}
+
+	outputLines := strings.Split(strings.TrimSpace(output.String()), "\n")
+
+	expectedHeader := strings.Join(inventoryexport.GetCSVHeader(), ",")
+	if outputLines[0] != expectedHeader {
+		t.Errorf("Writing CSV inventory failed; expected header %s, received %s", expectedHeader, outputLines[0])
+	}
+
+	expectedRow := "123456789012,prod;prod-legacy,ec2,arn:aws:ec2:us-east-1:123456789012:instance/i-1234567890abcdef0,,,,6,2600:9000:24eb:dc00:1:3b80:4f00:21"
+	if outputLines[2] != expectedRow {
+		t.Errorf("Writing CSV inventory failed; expected row %s, received %s", expectedRow, outputLines[2])
+	}
+}
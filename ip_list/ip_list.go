@@ -0,0 +1,163 @@
+package iplist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+)
+
+func GetSupportedFormats() []string {
+	return []string{
+		"auto",
+		"newline",
+		"csv",
+		"json",
+	}
+}
+
+func IsIPAddr(value string) bool {
+	return net.ParseIP(strings.TrimSpace(value)) != nil
+}
+
+func DetectFormat(data []byte) string {
+	trimmedData := bytes.TrimSpace(data)
+
+	switch {
+	case bytes.HasPrefix(trimmedData, []byte("[")), bytes.HasPrefix(trimmedData, []byte("{")):
+		return "json"
+	case bytes.Contains(trimmedData, []byte(",")):
+		return "csv"
+	default:
+		return "newline"
+	}
+}
+
+func ParseNewlineList(data []byte) ([]string, error) {
+	// one IP per line; blank lines and comments are skipped
+	// invalid entries are kept so that they're reported as errors instead of silently dropped
+	var ipAddrs []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		ipAddrs = append(ipAddrs, line)
+	}
+
+	return ipAddrs, scanner.Err()
+}
+
+func ParseCSVList(data []byte) ([]string, error) {
+	// scanner reports usually have a header and several columns, so we pull out any field that's an IP rather than relying on a specific column
+	var ipAddrs []string
+
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return ipAddrs, err
+		}
+
+		for _, field := range record {
+			if IsIPAddr(field) {
+				ipAddrs = append(ipAddrs, strings.TrimSpace(field))
+			}
+		}
+	}
+
+	return ipAddrs, nil
+}
+
+func ParseJSONList(data []byte) ([]string, error) {
+	// supports either an array of IPs, e.g. ["1.1.1.1", "2.2.2.2"], or an array of objects with IPs as values, e.g. [{"ip": "1.1.1.1"}]
+	var ipAddrs []string
+	var entries []any
+
+	err := json.Unmarshal(data, &entries)
+	if err != nil {
+		return ipAddrs, err
+	}
+
+	for _, entry := range entries {
+		switch entryVal := entry.(type) {
+		case string:
+			ipAddrs = append(ipAddrs, strings.TrimSpace(entryVal))
+		case map[string]any:
+			for _, fieldVal := range entryVal {
+				fieldStr, isStr := fieldVal.(string)
+				if isStr && IsIPAddr(fieldStr) {
+					ipAddrs = append(ipAddrs, strings.TrimSpace(fieldStr))
+				}
+			}
+		default:
+			return ipAddrs, fmt.Errorf("unsupported JSON IP list entry: %v", entry)
+		}
+	}
+
+	return ipAddrs, nil
+}
+
+func Parse(data []byte, format string) ([]string, error) {
+	var ipAddrs []string
+	var err error
+
+	if format == "auto" || format == "" {
+		format = DetectFormat(data)
+	}
+
+	switch format {
+	case "newline":
+		ipAddrs, err = ParseNewlineList(data)
+	case "csv":
+		ipAddrs, err = ParseCSVList(data)
+	case "json":
+		ipAddrs, err = ParseJSONList(data)
+	default:
+		err = fmt.Errorf("unsupported IP list format: '%s'", format)
+	}
+	if err != nil {
+		return ipAddrs, err
+	}
+
+	// the same IP often shows up multiple times in a report, but we only need to search for it once
+	var uniqueIPAddrs []string
+	for _, ipAddr := range ipAddrs {
+		if !slices.Contains(uniqueIPAddrs, ipAddr) {
+			uniqueIPAddrs = append(uniqueIPAddrs, ipAddr)
+		}
+	}
+
+	return uniqueIPAddrs, nil
+}
+
+func Read(inputPath, format string) ([]string, error) {
+	// reads the IP list from the given file, or from stdin if the path is "-"
+	var data []byte
+	var err error
+
+	if inputPath == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(inputPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data, format)
+}
@@ -0,0 +1,76 @@
+package iplist_test
+
+import (
+	"slices"
+	"testing"
+
+	iplist "github.com/magneticstain/ip-2-cloudresource/ip_list"
+)
+
+func TestDetectFormat(t *testing.T) {
+	var tests = []struct {
+		data, expectedFormat string
+	}{
+		{"1.1.1.1\n18.161.22.61\n", "newline"},
+		{"host,ip\nweb01,1.1.1.1\n", "csv"},
+		{`["1.1.1.1", "18.161.22.61"]`, "json"},
+		{`  [{"ip": "1.1.1.1"}]`, "json"},
+	}
+
+	for _, td := range tests {
+		testName := td.expectedFormat
+
+		t.Run(testName, func(t *testing.T) {
+			format := iplist.DetectFormat([]byte(td.data))
+
+			if format != td.expectedFormat {
+				t.Errorf("Detecting IP list format failed; expected %s, received %s", td.expectedFormat, format)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		name, data, format string
+		expectedIPAddrs    []string
+	}{
+		{"newline", "1.1.1.1\n\n# comment\n2600:9000:24eb:dc00:1:3b80:4f00:21\n1234.45.9666.1\n1.1.1.1\n", "auto", []string{"1.1.1.1", "2600:9000:24eb:dc00:1:3b80:4f00:21", "1234.45.9666.1"}},
+		{"csv", "host,ip,port\nweb01,1.1.1.1,443\nweb02, 18.161.22.61,80\n", "auto", []string{"1.1.1.1", "18.161.22.61"}},
+		{"json_strings", `["1.1.1.1", "18.161.22.61"]`, "auto", []string{"1.1.1.1", "18.161.22.61"}},
+		{"json_objects", `[{"ip": "1.1.1.1", "port": "443"}, {"ip": "18.161.22.61"}]`, "json", []string{"1.1.1.1", "18.161.22.61"}},
+		{"forced_newline", "1.1.1.1,18.161.22.61", "newline", []string{"1.1.1.1,18.161.22.61"}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			ipAddrs, err := iplist.Parse([]byte(td.data), td.format)
+			if err != nil {
+				t.Errorf("Parsing IP list failed; received error: %s", err)
+			}
+
+			if !slices.Equal(ipAddrs, td.expectedIPAddrs) {
+				t.Errorf("Parsing IP list failed; expected %s, received %s", td.expectedIPAddrs, ipAddrs)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	var tests = []struct {
+		name, data, format string
+	}{
+		{"bad_json", `["1.1.1.1"`, "json"},
+		{"unsupported_json_entry", `[1, 2]`, "json"},
+		{"unsupported_format", "1.1.1.1", "xml"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			_, err := iplist.Parse([]byte(td.data), td.format)
+			if err == nil {
+				t.Errorf("Parsing IP list should have failed for %s, but did not", td.name)
+			}
+		})
+	}
+}
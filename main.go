@@ -1,19 +1,32 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"slices"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/rollbar/rollbar-go"
 	log "github.com/sirupsen/logrus"
 
+	iphistory "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_history"
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	configfile "github.com/magneticstain/ip-2-cloudresource/config_file"
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	inventoryexport "github.com/magneticstain/ip-2-cloudresource/inventory_export"
+	iplist "github.com/magneticstain/ip-2-cloudresource/ip_list"
 	"github.com/magneticstain/ip-2-cloudresource/resource"
 	platformsearch "github.com/magneticstain/ip-2-cloudresource/search"
+	"github.com/magneticstain/ip-2-cloudresource/server"
+	"github.com/magneticstain/ip-2-cloudresource/snapshot"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
@@ -42,6 +55,14 @@ func outputResults(matchedResource resource.Resource, networkMapping bool, silen
 
 			log.Info("resource found -> [ ", matchedResource.RID, " ] within ", matchedResource.CloudSvc, " service running in ", acctStr)
 
+			if matchedResource.Location != "" {
+				log.Info("resource is located in ", matchedResource.Location)
+			}
+
+			if matchedResource.IPType != "" {
+				log.Info("IP matched as ", matchedResource.IPType, " IP address of resource")
+			}
+
 			if networkMapping {
 				var networkMapGraph string
 
@@ -84,7 +105,259 @@ func outputResults(matchedResource resource.Resource, networkMapping bool, silen
 	}
 }
 
-func runCloudSearch(platform, tenantID, ipAddr, cloudSvc, orgSearchXaccountRoleARN, orgSearchRoleName, orgSearchOrgUnitID string, ipFuzzing, advIPFuzzing, orgSearch, networkMapping, silent, jsonOutput bool) {
+func outputBulkResult(result platformsearch.BulkSearchResult, networkMapping bool, silent bool, jsonOutput bool) {
+	// one result is output per IP as soon as its search completes, so results can be streamed to other tools
+	if jsonOutput {
+		output, err := json.Marshal(result)
+		if err != nil {
+			errMap := map[string]error{"error": err}
+			errMapJSON, _ := json.Marshal(errMap)
+
+			fmt.Printf("%s\n", errMapJSON)
+		} else {
+			fmt.Printf("%s\n", output)
+		}
+
+		return
+	}
+
+	if silent {
+		// plaintext
+		switch {
+		case result.Error != "":
+			fmt.Printf("%s error: %s\n", result.IPAddr, result.Error)
+		case result.Resource.RID != "":
+			fmt.Printf("%s %s %s\n", result.IPAddr, result.Resource.RID, result.Resource.AccountID)
+		default:
+			fmt.Printf("%s not found\n", result.IPAddr)
+		}
+
+		return
+	}
+
+	if result.Error != "" {
+		log.Error("error when searching for IP ", result.IPAddr, ": ", result.Error)
+		return
+	}
+
+	log.Info("results for IP ", result.IPAddr, ":")
+	outputResults(result.Resource, networkMapping, silent, jsonOutput)
+}
+
+func checkPartialResults(err error) {
+	// inventory-based searches still return the results from every account that could be searched, so those are output before reporting the rest
+	var acctSearchErr platformsearch.AcctSearchError
+	if err != nil && !errors.As(err, &acctSearchErr) {
+		log.Fatal(err)
+	}
+}
+
+func outputHostResult(hostSearchResult platformsearch.HostSearchResult, networkMapping bool, silent bool, jsonOutput bool) {
+	if jsonOutput {
+		output, err := json.Marshal(hostSearchResult)
+		if err != nil {
+			errMap := map[string]error{"error": err}
+			errMapJSON, _ := json.Marshal(errMap)
+
+			fmt.Printf("%s\n", errMapJSON)
+		} else {
+			fmt.Printf("%s\n", output)
+		}
+
+		return
+	}
+
+	if silent {
+		// plaintext
+		fmt.Println(strings.Join(hostSearchResult.CNAMEChain, " -> "))
+	} else {
+		log.Info("CNAME chain: [ ", strings.Join(hostSearchResult.CNAMEChain, " -> "), " ]")
+		log.Info("host resolves to [ ", strings.Join(hostSearchResult.IPAddrs, ", "), " ]")
+	}
+
+	recognizedHostname := hostSearchResult.RecognizedHostname
+	if recognizedHostname.Platform != "" {
+		if !silent {
+			log.Info(recognizedHostname.FQDN, " recognized as ", strings.ToUpper(recognizedHostname.Platform), " ", strings.Join(recognizedHostname.CloudSvcs, "/"), " hostname")
+		}
+
+		if len(hostSearchResult.Resources) == 0 {
+			outputResults(resource.Resource{}, networkMapping, silent, jsonOutput)
+		}
+		for _, matchedResource := range hostSearchResult.Resources {
+			outputResults(matchedResource, networkMapping, silent, jsonOutput)
+		}
+
+		return
+	}
+
+	for _, result := range hostSearchResult.Results {
+		outputBulkResult(result, networkMapping, silent, jsonOutput)
+	}
+}
+
+func loadSnapshot(snapshotPath string, maxAge time.Duration, silent bool) *snapshot.Snapshot {
+	snap, err := snapshot.Read(snapshotPath)
+	if err != nil {
+		log.Fatal("error when reading snapshot: ", err)
+	}
+
+	snapshotAge := snap.GetAge(time.Now()).Round(time.Second)
+	log.Info("using snapshot from ", snap.CreatedAt.Format(time.RFC3339), " ( ", snapshotAge, " old ) covering [ ", strings.Join(snap.GetPlatforms(), ", "), " ]")
+
+	if snap.IsStale(time.Now(), maxAge) {
+		// logs are discarded in silent mode, but stale answers should never go unnoticed
+		staleMsg := fmt.Sprintf("snapshot is %s old, which is older than the max age of %s; results may be out of date", snapshotAge, maxAge)
+		if silent {
+			fmt.Fprintln(os.Stderr, "WARNING:", staleMsg)
+		} else {
+			log.Warn(staleMsg)
+		}
+	}
+
+	return &snap
+}
+
+func runSnapshot(platformParam, tenantIDParam, cloudSvc, snapshotPath, orgSearchXaccountRoleARN, orgSearchRoleName, orgSearchOrgUnitID string, orgSearchMaxWorkers int, orgSearch bool, azureConnConfig azureconnector.AzureConnectorConfig, gcpConnConfig gcpconnector.GCPConnectorConfig) {
+	platforms := getSupportedPlatforms()
+	if strings.ToLower(platformParam) != "all" {
+		platforms = strings.Split(strings.ToLower(platformParam), ",")
+	}
+	tenantIDs := utils.ParseTenantIDs(tenantIDParam)
+
+	snap := snapshot.New(APP_VER)
+
+	for _, platform := range platforms {
+		if !slices.Contains(getSupportedPlatforms(), platform) {
+			log.Fatal("'", platform, "' is not a supported platform")
+			return
+		}
+
+		tenantID := utils.GetPlatformTenantID(tenantIDs, platform)
+		if platform != "aws" && tenantID == "" {
+			log.Warn("skipping ", strings.ToUpper(platform), " since no tenant ID was provided for it")
+			continue
+		}
+
+		searchCtlr := platformsearch.Search{
+			Platform:            platform,
+			TenantID:            tenantID,
+			AzureConnConfig:     azureConnConfig,
+			GCPConnConfig:       gcpConnConfig,
+			OrgSearchMaxWorkers: orgSearchMaxWorkers,
+		}
+		doOrgSearch := orgSearch && platform != "azure"
+
+		log.Info("gathering public IPs of resources in ", cloudSvc, " ", strings.ToUpper(platform), " service(s)")
+
+		inventory, err := searchCtlr.StartInventory(cloudSvc, doOrgSearch, orgSearchXaccountRoleARN, orgSearchRoleName, orgSearchOrgUnitID)
+		if err != nil {
+			// a partial snapshot would give wrong answers without any indication (e.g. not finding IPs in accounts that failed), so it's better to not write one at all
+			var acctSearchErr platformsearch.AcctSearchError
+			if errors.As(err, &acctSearchErr) {
+				log.Fatal("not writing snapshot since some ", strings.ToUpper(platform), " accounts couldn't be inventoried: ", err)
+				return
+			}
+
+			log.Fatal("error when gathering ", strings.ToUpper(platform), " inventory for snapshot: ", err)
+			return
+		}
+
+		log.Info("found [ ", len(inventory), " ] ", strings.ToUpper(platform), " resources with public IPs")
+
+		snap.AddPlatformInventory(snapshot.PlatformInventory{
+			Platform:  platform,
+			TenantID:  tenantID,
+			CloudSvcs: searchCtlr.ReconcileCloudSvcParam(cloudSvc),
+			OrgSearch: doOrgSearch,
+			Resources: inventory,
+		})
+	}
+
+	err := snapshot.Write(snapshotPath, snap)
+	if err != nil {
+		log.Fatal("error when writing snapshot: ", err)
+		return
+	}
+
+	log.Info("snapshot written to ", snapshotPath)
+}
+
+func runDiff(oldSnapshotPath, newSnapshotPath string, jsonOutput bool) {
+	var snaps []snapshot.Snapshot
+
+	for _, snapshotPath := range []string{oldSnapshotPath, newSnapshotPath} {
+		snap, err := snapshot.Read(snapshotPath)
+		if err != nil {
+			log.Fatal("error when reading snapshot: ", err)
+			return
+		}
+
+		snaps = append(snaps, snap)
+	}
+
+	if snaps[1].CreatedAt.Before(snaps[0].CreatedAt) {
+		log.Warn(newSnapshotPath, " was taken before ", oldSnapshotPath, "; the snapshots may be in the wrong order")
+	}
+
+	snapshotDiff := snapshot.Compare(snaps[0], snaps[1])
+	for _, skippedScope := range snapshotDiff.SkippedScopes {
+		log.Warn("skipping part of snapshot comparison; ", skippedScope)
+	}
+
+	log.Info("found [ ", len(snapshotDiff.Appeared), " ] new, [ ", len(snapshotDiff.Disappeared), " ] released, and [ ", len(snapshotDiff.Moved), " ] moved public IPs")
+
+	var err error
+	if jsonOutput {
+		jsonEncoder := json.NewEncoder(os.Stdout)
+		jsonEncoder.SetIndent("", "  ")
+
+		err = jsonEncoder.Encode(snapshotDiff)
+	} else {
+		err = snapshotDiff.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatal("error when outputting snapshot diff: ", err)
+	}
+}
+
+func outputAttribution(attribution iphistory.Attribution, networkMapping bool, silent bool, jsonOutput bool) {
+	if jsonOutput {
+		output, err := json.Marshal(attribution)
+		if err != nil {
+			errMap := map[string]error{"error": err}
+			errMapJSON, _ := json.Marshal(errMap)
+
+			fmt.Printf("%s\n", errMapJSON)
+		} else {
+			fmt.Printf("%s\n", output)
+		}
+
+		return
+	}
+
+	if silent {
+		// plaintext
+		if attribution.Resource.RID != "" {
+			fmt.Println(attribution.Resource.RID)
+			fmt.Println(attribution.Resource.AccountID)
+		} else {
+			fmt.Println("not found")
+		}
+		fmt.Printf("confidence: %s", attribution.Confidence)
+
+		return
+	}
+
+	outputResults(attribution.Resource, networkMapping, silent, jsonOutput)
+
+	log.Info("attribution confidence: ", attribution.Confidence)
+	for _, evidence := range attribution.Evidence {
+		log.Info("evidence -> [ ", evidence.Time.Format(time.RFC3339), " ] ", evidence.Source, " ", evidence.Name, " ( ", evidence.ID, " ): ", evidence.Summary)
+	}
+}
+
+func runCloudSearch(platform, tenantID, ipAddr, host, inputPath, inputFormat, cloudSvc, orgSearchXaccountRoleARN, orgSearchRoleName, orgSearchOrgUnitID, fromSnapshotPath string, snapshotMaxAge time.Duration, historicalAt time.Time, historyLookback time.Duration, orgSearchMaxWorkers int, gcpAssetSearch, matchPrivateIPs, followCNAMEs, ipFuzzing, advIPFuzzing, orgSearch, networkMapping, silent, jsonOutput bool, azureConnConfig azureconnector.AzureConnectorConfig, gcpConnConfig gcpconnector.GCPConnectorConfig) {
 	var err error
 
 	platform = strings.ToLower(platform)
@@ -94,15 +367,141 @@ func runCloudSearch(platform, tenantID, ipAddr, cloudSvc, orgSearchXaccountRoleA
 		return
 	}
 
-	// search
-	log.Info("searching for IP ", ipAddr, " in ", cloudSvc, " ", strings.ToUpper(platform), " service(s)")
-
 	searchCtlr := platformsearch.Search{
-		Platform: platform,
-		TenantID: tenantID,
-		IpAddr:   ipAddr,
+		Platform:            platform,
+		TenantID:            tenantID,
+		IpAddr:              ipAddr,
+		AzureConnConfig:     azureConnConfig,
+		GCPConnConfig:       gcpConnConfig,
+		OrgSearchMaxWorkers: orgSearchMaxWorkers,
+		GCPAssetSearch:      gcpAssetSearch,
+		MatchPrivateIPs:     matchPrivateIPs,
+	}
+
+	if fromSnapshotPath != "" {
+		searchCtlr.Snapshot = loadSnapshot(fromSnapshotPath, snapshotMaxAge, silent)
+	}
+
+	if !historicalAt.IsZero() {
+		log.Info("searching for owner of IP ", ipAddr, " at ", historicalAt.Format(time.RFC3339), " in ", strings.ToUpper(platform))
+
+		attribution, err := searchCtlr.StartHistoricalSearch(
+			historicalAt,
+			historyLookback,
+			orgSearch,
+			orgSearchXaccountRoleARN,
+			orgSearchRoleName,
+			orgSearchOrgUnitID,
+		)
+		if err != nil {
+			log.Fatal("error when searching IP history: ", err)
+			return
+		}
+
+		outputAttribution(attribution, networkMapping, silent, jsonOutput)
+
+		return
+	}
+
+	if host != "" {
+		log.Info("searching for host ", host, " in ", cloudSvc, " ", strings.ToUpper(platform), " service(s)")
+
+		hostSearchResult, err := searchCtlr.StartHostSearch(
+			host,
+			followCNAMEs,
+			cloudSvc,
+			ipFuzzing,
+			advIPFuzzing,
+			orgSearch,
+			orgSearchXaccountRoleARN,
+			orgSearchRoleName,
+			orgSearchOrgUnitID,
+			networkMapping,
+		)
+		checkPartialResults(err)
+
+		outputHostResult(hostSearchResult, networkMapping, silent, jsonOutput)
+
+		if err != nil {
+			log.Fatal("host search results are incomplete: ", err)
+		}
+
+		return
+	}
+
+	if inputPath != "" {
+		ipAddrs, err := iplist.Read(inputPath, inputFormat)
+		if err != nil {
+			log.Fatal("error when reading IP list: ", err)
+			return
+		}
+
+		log.Info("searching for [ ", len(ipAddrs), " ] IPs in ", cloudSvc, " ", strings.ToUpper(platform), " service(s)")
+
+		err = searchCtlr.StartBulkSearch(
+			ipAddrs,
+			func(result platformsearch.BulkSearchResult) error {
+				outputBulkResult(result, networkMapping, silent, jsonOutput)
+				return nil
+			},
+			cloudSvc,
+			ipFuzzing,
+			advIPFuzzing,
+			orgSearch,
+			orgSearchXaccountRoleARN,
+			orgSearchRoleName,
+			orgSearchOrgUnitID,
+			networkMapping,
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return
 	}
 
+	if utils.IsIPRange(ipAddr) {
+		ipRange, err := utils.ParseIPRange(ipAddr)
+		if err != nil {
+			log.Fatal("error when parsing IP range: ", err)
+			return
+		}
+
+		log.Info("searching for IPs in range ", ipRange, " in ", cloudSvc, " ", strings.ToUpper(platform), " service(s)")
+
+		matchedResources, err := searchCtlr.StartRangeSearch(
+			ipRange,
+			cloudSvc,
+			orgSearch,
+			orgSearchXaccountRoleARN,
+			orgSearchRoleName,
+			orgSearchOrgUnitID,
+		)
+		checkPartialResults(err)
+
+		log.Info("found [ ", len(matchedResources), " ] resources with IPs in range")
+
+		if len(matchedResources) == 0 {
+			outputResults(resource.Resource{}, networkMapping, silent, jsonOutput)
+		}
+		for _, matchedResource := range matchedResources {
+			outputResults(matchedResource, networkMapping, silent, jsonOutput)
+
+			if !silent {
+				log.Info("resource IPs: ", strings.Join(append(slices.Clip(matchedResource.PublicIPv4Addrs), matchedResource.PublicIPv6Addrs...), ", "))
+			}
+		}
+
+		if err != nil {
+			log.Fatal("range search results are incomplete: ", err)
+		}
+
+		return
+	}
+
+	// search
+	log.Info("searching for IP ", ipAddr, " in ", cloudSvc, " ", strings.ToUpper(platform), " service(s)")
+
 	_, err = searchCtlr.StartSearch(
 		cloudSvc,
 		ipFuzzing,
@@ -121,9 +520,177 @@ func runCloudSearch(platform, tenantID, ipAddr, cloudSvc, orgSearchXaccountRoleA
 	outputResults(searchCtlr.MatchedResource, networkMapping, silent, jsonOutput)
 }
 
+func applyConfigProfile(configPath string, profileName string) error {
+	// fills in any flags that weren't set on the command line from the selected (or default) profile of the config file
+	configPath, err := configfile.Find(configPath)
+	if err != nil {
+		return err
+	}
+	if configPath == "" {
+		if profileName != "" {
+			return fmt.Errorf("profile '%s' was selected, but no config file was found in %s", profileName, configfile.GetConfigDir())
+		}
+
+		return nil
+	}
+
+	cfg, err := configfile.Read(configPath)
+	if err != nil {
+		return err
+	}
+
+	profile, err := cfg.GetProfile(profileName)
+	if err != nil {
+		return err
+	}
+
+	// these decide which profile is used, so setting them from a profile wouldn't make sense
+	return profile.Apply(flag.CommandLine, []string{"config", "profile", "version"})
+}
+
+func getSupportedSubcommands() []string {
+	return []string{
+		"search",
+		"inventory",
+		"snapshot",
+		"diff",
+		"serve",
+	}
+}
+
+func runServe(platform, tenantID, listenAddr, grpcListenAddr, orgSearchXaccountRoleARN, orgSearchRoleName, orgSearchOrgUnitID, fromSnapshotPath string, snapshotMaxAge, cacheTTL time.Duration, orgSearchMaxWorkers, maxBatchSize int, gcpAssetSearch, matchPrivateIPs, ipFuzzing, advIPFuzzing, orgSearch, networkMapping bool, azureConnConfig azureconnector.AzureConnectorConfig, gcpConnConfig gcpconnector.GCPConnectorConfig) {
+	platform = strings.ToLower(platform)
+	if !slices.Contains(getSupportedPlatforms(), platform) {
+		log.Fatal("'", platform, "' is not a supported platform")
+		return
+	}
+
+	serverConfig := server.ServerConfig{
+		DefaultPlatform:          platform,
+		DefaultTenantIDs:         utils.ParseTenantIDs(tenantID),
+		AzureConnConfig:          azureConnConfig,
+		GCPConnConfig:            gcpConnConfig,
+		OrgSearch:                orgSearch,
+		OrgSearchXaccountRoleARN: orgSearchXaccountRoleARN,
+		OrgSearchRoleName:        orgSearchRoleName,
+		OrgSearchOrgUnitID:       orgSearchOrgUnitID,
+		OrgSearchMaxWorkers:      orgSearchMaxWorkers,
+		GCPAssetSearch:           gcpAssetSearch,
+		MatchPrivateIPs:          matchPrivateIPs,
+		IPFuzzing:                ipFuzzing,
+		AdvIPFuzzing:             advIPFuzzing,
+		NetworkMapping:           networkMapping,
+		CacheTTL:                 cacheTTL,
+		MaxBatchSize:             maxBatchSize,
+		APIToken:                 os.Getenv("IP2CR_API_TOKEN"),
+	}
+
+	if fromSnapshotPath != "" {
+		serverConfig.Snapshot = loadSnapshot(fromSnapshotPath, snapshotMaxAge, false)
+	}
+
+	apiServer := server.New(serverConfig)
+
+	if apiServer.Config.APIToken == "" && !strings.HasPrefix(listenAddr, "127.0.0.1:") && !strings.HasPrefix(listenAddr, "localhost:") {
+		log.Warn("IP2CR_API_TOKEN is not set, so anyone who can reach ", listenAddr, " can search your cloud resources")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// both APIs share the same server, so connections and inventories are reused between REST and gRPC requests
+	serverFuncs := []func() error{
+		func() error { return apiServer.ListenAndServe(ctx, listenAddr) },
+	}
+	if grpcListenAddr != "" {
+		serverFuncs = append(serverFuncs, func() error { return apiServer.ServeGRPC(ctx, grpcListenAddr) })
+	}
+
+	serveErrs := make(chan error, len(serverFuncs))
+	for _, serverFunc := range serverFuncs {
+		go func(serverFunc func() error) {
+			serveErrs <- serverFunc()
+		}(serverFunc)
+	}
+
+	var serveErr error
+	for range serverFuncs {
+		err := <-serveErrs
+		if err != nil && serveErr == nil {
+			serveErr = err
+
+			// shut down the other server too, rather than leaving the process half-running
+			stop()
+		}
+	}
+
+	if serveErr != nil {
+		log.Fatal("error when running API server: ", serveErr)
+	}
+}
+
+func runInventory(platform, tenantID, cloudSvc, outputFormat, orgSearchXaccountRoleARN, orgSearchRoleName, orgSearchOrgUnitID, fromSnapshotPath string, snapshotMaxAge time.Duration, orgSearchMaxWorkers int, orgSearch, silent bool, azureConnConfig azureconnector.AzureConnectorConfig, gcpConnConfig gcpconnector.GCPConnectorConfig) {
+	platform = strings.ToLower(platform)
+	supportedPlatforms := getSupportedPlatforms()
+	if !slices.Contains(supportedPlatforms, platform) {
+		log.Fatal("'", platform, "' is not a supported platform")
+		return
+	}
+
+	searchCtlr := platformsearch.Search{
+		Platform:            platform,
+		TenantID:            tenantID,
+		AzureConnConfig:     azureConnConfig,
+		GCPConnConfig:       gcpConnConfig,
+		OrgSearchMaxWorkers: orgSearchMaxWorkers,
+	}
+
+	if fromSnapshotPath != "" {
+		searchCtlr.Snapshot = loadSnapshot(fromSnapshotPath, snapshotMaxAge, silent)
+	}
+
+	log.Info("gathering public IPs of resources in ", cloudSvc, " ", strings.ToUpper(platform), " service(s)")
+
+	inventory, err := searchCtlr.StartInventory(
+		cloudSvc,
+		orgSearch,
+		orgSearchXaccountRoleARN,
+		orgSearchRoleName,
+		orgSearchOrgUnitID,
+	)
+	checkPartialResults(err)
+	inventoryErr := err
+
+	log.Info("found [ ", len(inventory), " ] resources with public IPs")
+
+	err = inventoryexport.Write(os.Stdout, inventory, outputFormat)
+	if err != nil {
+		log.Fatal("error when outputting inventory: ", err)
+	}
+
+	if inventoryErr != nil {
+		log.Fatal("inventory is incomplete: ", inventoryErr)
+	}
+}
+
 func main() {
+	// subcommands are optional, so that existing usage (e.g. `ip2cr -ipaddr=1.2.3.4`) keeps working as a search
+	subcommand := "search"
+	cliArgs := os.Args[1:]
+	if len(cliArgs) > 0 && !strings.HasPrefix(cliArgs[0], "-") {
+		subcommand = cliArgs[0]
+		cliArgs = cliArgs[1:]
+	}
+
+	if !slices.Contains(getSupportedSubcommands(), subcommand) {
+		log.Error("'", subcommand, "' is not a supported subcommand (supported values: ", strings.Join(getSupportedSubcommands(), ", "), ")")
+		os.Exit(1)
+	}
+
 	// CLI param parsing
 	version := flag.Bool("version", false, "Outputs the version of IP2CR in use and exits")
+	configPath := flag.String("config", "", "Path to a YAML config file with named profiles of flag values; defaults to $XDG_CONFIG_HOME/ip2cr/config.yaml (or ~/.config/ip2cr/config.yaml) if it exists")
+	profileName := flag.String("profile", os.Getenv("IP2CR_PROFILE"), "Name of the config file profile to use; if not set, the config file's default-profile is used. Flags set on the command line override profile values")
 
 	// output
 	silentOutput := flag.Bool("silent", false, "If enabled, only output the results")
@@ -132,13 +699,36 @@ func main() {
 
 	// base
 	platform := flag.String("platform", "aws", "Platform to target for IP search (supported values: aws, gcp, azure)")
-	ipAddr := flag.String("ipaddr", "", "IP address to search for (REQUIRED)")
+	ipAddr := flag.String("ipaddr", "", "IP address to search for (REQUIRED unless --host or --input is set); a CIDR (e.g. 10.0.0.0/24) or range (e.g. 10.0.0.1-10.0.0.50) can also be provided to find every resource with an IP inside it")
+	host := flag.String("host", "", "Hostname to search for; every IP it resolves to is searched, and hostnames that point at a CloudFront, ELB, or Azure Front Door hostname are matched by name instead")
+	followCNAMEs := flag.Bool("follow-cnames", true, "Follow the CNAME chain of the host set with --host, reporting each hop and recognizing cloud service hostnames along the way")
+	inputPath := flag.String("input", "", "Path to a file containing a list of IPs to search for, or - to read the list from stdin; each service's resources are only fetched once for the whole list")
+	inputFormat := flag.String("input-format", "auto", "Format of the IP list set with --input (supported values: "+strings.Join(iplist.GetSupportedFormats(), ", ")+")")
 	cloudSvc := flag.String("svc", "all", "Specific cloud service(s) to search. Multiple services can be listed in CSV format, e.g. elbv1,elbv2. Available services are: [all, cloudfront , ec2 , elbv1 , elbv2]")
 
 	// platform
+	awsRegion := flag.String("aws-region", "", "The AWS region to search; defaults to the region from your AWS config or environment")
 	tenantID := flag.String("tenant-id", "", "For cloud platforms that require or support it, set this to the ID of the target tenant (e.g. project, account, subscription, etc) ID to search")
 
+	// azure auth
+	azureAuth := flag.String("azure-auth", "default", "Method to use when authenticating to Azure (supported values: "+strings.Join(azureconnector.GetSupportedAuthMethods(), ", ")+"); client secrets and certificate passwords are read from the AZURE_CLIENT_SECRET and AZURE_CLIENT_CERTIFICATE_PASSWORD environment variables")
+	azureDirectoryID := flag.String("azure-directory-id", os.Getenv("AZURE_TENANT_ID"), "The ID of the Entra ID (Azure AD) directory to authenticate against; this is separate from the subscription ID set with --tenant-id")
+	azureClientID := flag.String("azure-client-id", os.Getenv("AZURE_CLIENT_ID"), "The client ID of the service principal or user-assigned managed identity to authenticate as")
+	azureClientCert := flag.String("azure-client-cert", os.Getenv("AZURE_CLIENT_CERTIFICATE_PATH"), "Path to the PEM or PKCS#12 certificate to use with client-certificate auth")
+	azureCloud := flag.String("azure-cloud", "public", "The Azure cloud to connect to (supported values: public, usgovernment, china)")
+	azureARMEndpoint := flag.String("azure-arm-endpoint", "", "Override the Azure Resource Manager endpoint, e.g. to target a local stub")
+
+	// gcp
+	gcpImpersonateSvcAcct := flag.String("gcp-impersonate-service-account", "", "Email of a GCP service account to impersonate when searching; the base credentials must be allowed to create tokens for it")
+	gcpCredsFile := flag.String("gcp-credentials-file", "", "Path to a GCP service account key or external account config file to use instead of application default credentials")
+	gcpQuotaProject := flag.String("gcp-quota-project", "", "The GCP project to bill API quota to, if different from the project being searched")
+	gcpEndpoint := flag.String("gcp-endpoint", "", "Override the GCP API endpoint, e.g. to target a local emulator")
+	gcpAssetSearch := flag.Bool("gcp-asset-search", false, "Search GCP using Cloud Asset Inventory instead of each service's API; combine with --org-search to search every project under the org or folder set with --org-search-ou-id in a single query")
+
 	// FEATURE FLAGS
+	// private IPs
+	matchPrivateIPs := flag.Bool("private-ips", false, "Also match private IPs (e.g. VPC IPs) for services that support it (currently GCP only)")
+
 	// IP fuzzing
 	ipFuzzing := flag.Bool("ip-fuzzing", true, "Toggle the IP fuzzing feature to evaluate the IP and help optimize search (not recommended for small accounts due to overhead outweighing value)")
 	advIPFuzzing := flag.Bool("adv-ip-fuzzing", true, "Toggle the advanced IP fuzzing feature to perform a more intensive heuristics evaluation to fuzz the service (not recommended for IPv6 addresses)")
@@ -147,20 +737,74 @@ func main() {
 	orgSearch := flag.Bool("org-search", false, "Search through all child accounts of the organization for resources, as well as target account (target account should be parent account)")
 	orgSearchXaccountRoleARN := flag.String("org-search-xaccount-role-arn", "", "The ARN of the role to assume for gathering AWS Organizations information for search, e.g. the role to assume with R/O access to your AWS Organizations account")
 	orgSearchRoleName := flag.String("org-search-role-name", "ip2cr", "The name of the role in each child account of an AWS Organization to assume when performing a search")
-	orgSearchOrgUnitID := flag.String("org-search-ou-id", "", "The ID of the AWS Organizations Organizational Unit to target when performing a search")
+	orgSearchOrgUnitID := flag.String("org-search-ou-id", "", "The ID of the AWS Organizations Organizational Unit to target when performing a search. For GCP, this is the organization or folder to search projects under (e.g. organizations/123 or folders/456); if not set, all projects visible to the current identity are searched")
+	orgSearchMaxWorkers := flag.Int("org-search-max-workers", 10, "The max number of accounts/projects to search concurrently when performing an org search")
 
 	// network mapping
 	networkMapping := flag.Bool("network-mapping", false, "If enabled, generate a network map associated with the identified resource if it's found")
 
-	flag.Parse()
+	// snapshots
+	snapshotPath := flag.String("snapshot-file", "ip2cr.snap", "Path to write the snapshot to when using the snapshot subcommand")
+	fromSnapshotPath := flag.String("from-snapshot", "", "Path to a snapshot created with the snapshot subcommand to answer searches from, instead of calling the platform's APIs")
+	historicalTimestamp := flag.String("at", "", "Find the resource that owned the IP at this time instead of now, e.g. 2024-04-01T12:30:00Z; uses AWS Config history and CloudTrail events (AWS only)")
+	historyLookback := flag.Duration("at-lookback", 7*24*time.Hour, "How far before the time set with --at to look for CloudTrail events that associated the IP; CloudTrail only keeps 90 days of events")
+	snapshotMaxAge := flag.Duration("snapshot-max-age", 24*time.Hour, "Warn if the snapshot set with --from-snapshot is older than this (e.g. 30m, 12h); set to 0 to disable")
+
+	// inventory
+	inventoryFormat := flag.String("format", "json", "Output format to use with the inventory subcommand (supported values: "+strings.Join(inventoryexport.GetSupportedFormats(), ", ")+")")
+
+	// serve
+	listenAddr := flag.String("listen", "127.0.0.1:8080", "Address to listen on when using the serve subcommand; set the IP2CR_API_TOKEN environment variable to require a bearer token for requests")
+	grpcListenAddr := flag.String("grpc-listen", "", "Address to also serve the gRPC API on when using the serve subcommand, e.g. 127.0.0.1:9090; disabled if not set")
+	cacheTTL := flag.Duration("cache-ttl", 15*time.Minute, "How long the serve subcommand reuses connections and fetched inventories before refreshing them (e.g. 5m, 1h); set to 0 to never refresh")
+	maxBatchSize := flag.Int("max-batch-size", 1000, "The max number of IPs the serve subcommand accepts in a single batch lookup")
+
+	_ = flag.CommandLine.Parse(cliArgs)
 
 	if *version {
 		fmt.Println("ip-2-cloudresource", APP_VER)
 		return
 	}
 
-	if *ipAddr == "" {
-		log.Error("IP address is required")
+	err := applyConfigProfile(*configPath, *profileName)
+	if err != nil {
+		log.Error("error when loading config: ", err)
+		os.Exit(1)
+	}
+
+	if *awsRegion != "" {
+		// picked up by the AWS SDK when loading its default config
+		os.Setenv("AWS_REGION", *awsRegion)
+	}
+
+	if subcommand == "diff" && flag.NArg() != 2 {
+		log.Error("the diff subcommand requires exactly two snapshots, e.g. ip2cr diff old.snap new.snap")
+		os.Exit(1)
+	}
+
+	if subcommand == "search" && *ipAddr == "" && *host == "" && *inputPath == "" {
+		log.Error("IP address, host, or input list is required")
+		os.Exit(1)
+	}
+
+	var historicalAt time.Time
+	if *historicalTimestamp != "" {
+		var err error
+
+		historicalAt, err = utils.ParseTimestamp(*historicalTimestamp)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		if *ipAddr == "" || utils.IsIPRange(*ipAddr) || *host != "" || *inputPath != "" {
+			log.Error("--at requires a single IP address set with --ipaddr")
+			os.Exit(1)
+		}
+	}
+
+	if subcommand == "inventory" && !slices.Contains(inventoryexport.GetSupportedFormats(), strings.ToLower(*inventoryFormat)) {
+		log.Error("'", *inventoryFormat, "' is not a supported inventory output format")
 		os.Exit(1)
 	}
 
@@ -174,21 +818,30 @@ func main() {
 		log.SetLevel(log.DebugLevel)
 	}
 
-	// if the service(s) are specified, then we don't need to spend our time fuzzing the IP
-	if *cloudSvc != "all" {
+	// if the service(s) are specified, then we don't need to spend our time fuzzing the IP; the same goes for range searches, which check every resource regardless
+	if *cloudSvc != "all" || utils.IsIPRange(*ipAddr) {
 		*ipFuzzing = false
 		*advIPFuzzing = false
 	}
 
-	// modify flags based on platform's supported feature set
+	// snapshots and the server cover several platforms, so they pick the tenant ID for each platform themselves
+	if subcommand != "snapshot" && subcommand != "serve" {
+		*tenantID = utils.GetPlatformTenantID(utils.ParseTenantIDs(*tenantID), *platform)
+	}
+
+	// modify flags based on platform's supported feature set; snapshots can cover several platforms, so they're handled per platform instead
 	switch {
+	case subcommand == "snapshot", subcommand == "diff", subcommand == "serve":
 	case *platform != "aws":
-		*ipFuzzing = false
+		// GCP only supports basic IP fuzzing using its published IP ranges
+		if *platform != "gcp" {
+			*ipFuzzing = false
+			*orgSearch = false
+		}
 		*advIPFuzzing = false
-		*orgSearch = false
-		*networkMapping = false
-	case *platform == "gcp", *platform == "azure":
-		if *tenantID == "" {
+
+		// org searches enumerate the projects to search themselves, and snapshots already include the tenant
+		if *tenantID == "" && !*orgSearch && *fromSnapshotPath == "" {
 			log.Fatal("tenant ID is required for searching ", strings.ToUpper(*platform))
 		}
 	}
@@ -197,21 +850,134 @@ func main() {
 
 	utils.InitRollbar(APP_ENV, APP_VER)
 
+	azureConnConfig := azureconnector.AzureConnectorConfig{
+		AuthMethod:         *azureAuth,
+		DirectoryID:        *azureDirectoryID,
+		ClientID:           *azureClientID,
+		ClientSecret:       os.Getenv("AZURE_CLIENT_SECRET"),
+		ClientCertPath:     *azureClientCert,
+		ClientCertPassword: os.Getenv("AZURE_CLIENT_CERTIFICATE_PASSWORD"),
+		Cloud:              *azureCloud,
+		ARMEndpoint:        *azureARMEndpoint,
+	}
+	gcpConnConfig := gcpconnector.GCPConnectorConfig{
+		ImpersonateServiceAccount: *gcpImpersonateSvcAcct,
+		CredentialsFile:           *gcpCredsFile,
+		QuotaProject:              *gcpQuotaProject,
+		Endpoint:                  *gcpEndpoint,
+	}
+
+	switch subcommand {
+	case "serve":
+		rollbar.WrapAndWait(
+			runServe,
+			*platform,
+			*tenantID,
+			*listenAddr,
+			*grpcListenAddr,
+			*orgSearchXaccountRoleARN,
+			*orgSearchRoleName,
+			*orgSearchOrgUnitID,
+			*fromSnapshotPath,
+			*snapshotMaxAge,
+			*cacheTTL,
+			*orgSearchMaxWorkers,
+			*maxBatchSize,
+			*gcpAssetSearch,
+			*matchPrivateIPs,
+			*ipFuzzing,
+			*advIPFuzzing,
+			*orgSearch,
+			*networkMapping,
+			azureConnConfig,
+			gcpConnConfig,
+		)
+
+		rollbar.Close()
+
+		return
+	case "diff":
+		rollbar.WrapAndWait(
+			runDiff,
+			flag.Arg(0),
+			flag.Arg(1),
+			*jsonOutput,
+		)
+
+		rollbar.Close()
+
+		return
+	case "inventory":
+		rollbar.WrapAndWait(
+			runInventory,
+			*platform,
+			*tenantID,
+			*cloudSvc,
+			*inventoryFormat,
+			*orgSearchXaccountRoleARN,
+			*orgSearchRoleName,
+			*orgSearchOrgUnitID,
+			*fromSnapshotPath,
+			*snapshotMaxAge,
+			*orgSearchMaxWorkers,
+			*orgSearch,
+			*silentOutput,
+			azureConnConfig,
+			gcpConnConfig,
+		)
+
+		rollbar.Close()
+
+		return
+	case "snapshot":
+		rollbar.WrapAndWait(
+			runSnapshot,
+			*platform,
+			*tenantID,
+			*cloudSvc,
+			*snapshotPath,
+			*orgSearchXaccountRoleARN,
+			*orgSearchRoleName,
+			*orgSearchOrgUnitID,
+			*orgSearchMaxWorkers,
+			*orgSearch,
+			azureConnConfig,
+			gcpConnConfig,
+		)
+
+		rollbar.Close()
+
+		return
+	}
+
 	rollbar.WrapAndWait(
 		runCloudSearch,
 		*platform,
 		*tenantID,
 		*ipAddr,
+		*host,
+		*inputPath,
+		*inputFormat,
 		*cloudSvc,
 		*orgSearchXaccountRoleARN,
 		*orgSearchRoleName,
 		*orgSearchOrgUnitID,
+		*fromSnapshotPath,
+		*snapshotMaxAge,
+		historicalAt,
+		*historyLookback,
+		*orgSearchMaxWorkers,
+		*gcpAssetSearch,
+		*matchPrivateIPs,
+		*followCNAMEs,
 		*ipFuzzing,
 		*advIPFuzzing,
 		*orgSearch,
 		*networkMapping,
 		*silentOutput,
 		*jsonOutput,
+		azureConnConfig,
+		gcpConnConfig,
 	)
 
 	rollbar.Close()
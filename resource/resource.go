@@ -1,6 +1,8 @@
 package resource
 
 type Resource struct {
-	Id, RID, AccountID, Name, Status, CloudSvc                   string
+	Id, RID, AccountID, Name, Status, CloudSvc, IPType, Location string
 	AccountAliases, NetworkMap, PublicIPv4Addrs, PublicIPv6Addrs []string
+	// DNS names that point at the resource, for services that are addressed by hostname (e.g. load balancers and CDNs)
+	FQDNs []string
 }
@@ -3,8 +3,11 @@ package search
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/rollbar/rollbar-go"
@@ -14,23 +17,96 @@ import (
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
 	iamp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/iam"
 	ipfuzzing "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing"
+	iphistory "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_history"
 	azurecontroller "github.com/magneticstain/ip-2-cloudresource/azure"
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
 	gcpcontroller "github.com/magneticstain/ip-2-cloudresource/gcp"
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/resource_manager"
+	gcpipfuzzing "github.com/magneticstain/ip-2-cloudresource/gcp/svc/ip_fuzzing"
+	hostlookup "github.com/magneticstain/ip-2-cloudresource/host_lookup"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/snapshot"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type Search struct {
 	AWSCtrlr                   awscontroller.AWSController
+	AzureConnConfig            azureconnector.AzureConnectorConfig
 	AzureCtrlr                 azurecontroller.AzureController
 	CloudSvcs                  []string
+	GCPConnConfig              gcpconnector.GCPConnectorConfig
 	GCPCtrlr                   gcpcontroller.GCPController
 	MatchedResource            generalResource.Resource
 	IpAddr, Platform, TenantID string
+	// max number of accounts/projects to search concurrently during an org search; <= 0 means no limit
+	OrgSearchMaxWorkers int
+	// search GCP via Cloud Asset Inventory instead of each service's API
+	GCPAssetSearch bool
+	// also match private IPs for services that support it
+	MatchPrivateIPs bool
+	// only set for bulk searches, where resource inventories are reused across IPs
+	InvCache *inventorycache.InventoryCache
+	// if set, searches are answered from this snapshot without calling the platform's APIs
+	Snapshot *snapshot.Snapshot
+	// set once connected, so long-running callers (e.g. the API server) can reuse the connection across searches
+	isConnected bool
+}
+
+type BulkSearchResult struct {
+	IPAddr   string
+	Found    bool
+	Resource generalResource.Resource
+	Error    string
+}
+
+type HostSearchResult struct {
+	Host       string
+	CNAMEChain []string
+	IPAddrs    []string
+	// set if the host points at a cloud service's own hostname, in which case the service is identified without IP matching
+	RecognizedHostname hostlookup.CloudHostname
+	// resources whose hostname matched the recognized hostname
+	Resources []generalResource.Resource
+	Results   []BulkSearchResult
+}
+
+// returned alongside the results of an inventory-based search when some accounts couldn't be searched, so callers know the results are incomplete
+type AcctSearchError struct {
+	AcctErrs map[string]error
+}
+
+func (acctSearchErr AcctSearchError) Error() string {
+	var acctIDs, acctErrMsgs []string
+
+	for acctID := range acctSearchErr.AcctErrs {
+		acctIDs = append(acctIDs, acctID)
+	}
+	sort.Strings(acctIDs)
+
+	for _, acctID := range acctIDs {
+		acctErrMsgs = append(acctErrMsgs, fmt.Sprintf("%s: %s", acctID, acctSearchErr.AcctErrs[acctID]))
+	}
+
+	return fmt.Sprintf("unable to search %d account(s) :: [ %s ]", len(acctIDs), strings.Join(acctErrMsgs, "; "))
 }
 
 func (search *Search) connectToPlatform() (bool, error) {
 	// generate a connection to the specified platform via plugin
-	// GCP does not require a connector as it uses ADC ( https://cloud.google.com/docs/authentication/application-default-credentials / https://archive.is/tSqC2 )
+
+	if search.Snapshot != nil {
+		// nothing to connect to, but the snapshot needs to include the platform for its answers to mean anything
+		if !slices.Contains(search.Snapshot.GetPlatforms(), search.Platform) {
+			return false, fmt.Errorf("snapshot does not include an inventory for %s", search.Platform)
+		}
+
+		return true, nil
+	}
+
+	if search.isConnected {
+		return true, nil
+	}
 
 	switch search.Platform {
 	case "aws":
@@ -41,17 +117,41 @@ func (search *Search) connectToPlatform() (bool, error) {
 
 		search.AWSCtrlr = ac
 	case "azure":
-		azc, err := azurecontroller.New()
+		azc, err := azurecontroller.New(search.AzureConnConfig)
 		if err != nil {
 			return false, err
 		}
 
 		search.AzureCtrlr = azc
+	case "gcp":
+		gcpc, err := gcpcontroller.New(search.GCPConnConfig)
+		if err != nil {
+			return false, err
+		}
+
+		gcpc.MatchPrivateIPs = search.MatchPrivateIPs
+		search.GCPCtrlr = gcpc
 	}
 
+	search.isConnected = true
+
 	return true, nil
 }
 
+func (search *Search) Connect() error {
+	// connects to the platform ahead of time and enables inventory caching, so copies of the search can share both
+	_, err := search.connectToPlatform()
+	if err != nil {
+		return err
+	}
+
+	if search.InvCache == nil {
+		search.InvCache = inventorycache.New()
+	}
+
+	return nil
+}
+
 func (search Search) ReconcileCloudSvcParam(cloudSvc string) []string {
 	var cloudSvcs []string
 
@@ -104,11 +204,47 @@ func (search Search) RunIPFuzzing(doAdvIPFuzzing bool) ([]string, error) {
 	return svcSet, err
 }
 
+func (search *Search) RunGCPIPFuzzing() ([]string, error) {
+	// GCP's published ranges can't tell us which service an IP belongs to, but they can tell us its region, or that it isn't a customer IP at all
+	svcSet := search.CloudSvcs
+
+	fuzzedScope, err := gcpipfuzzing.FuzzIP(search.IpAddr)
+	if err != nil {
+		return svcSet, err
+	}
+
+	switch fuzzedScope {
+	case gcpipfuzzing.GoogleOwnedScope:
+		log.Info("IP fuzzing determined the IP is owned by Google, but is not a Google Cloud customer IP; skipping search")
+		svcSet = []string{}
+	case gcpipfuzzing.UnknownScope:
+		log.Info("IP is not within any published Google range; it may be a BYOIP address")
+	case "global":
+		log.Info("IP fuzzing determined the IP is a global Google Cloud IP")
+	default:
+		log.Info("IP fuzzing determined the IP is a Google Cloud IP in region: ", fuzzedScope)
+		search.GCPCtrlr.Region = fuzzedScope
+	}
+
+	return svcSet, nil
+}
+
+func (search *Search) scopeInvCache(acctID string) {
+	// plugins don't always know which account they're searching (e.g. AWS uses whichever role is assumed), so each account gets its own cache scope
+	acctInvCache := search.InvCache.Scope(search.Platform + "/" + acctID)
+
+	search.AWSCtrlr.InvCache = acctInvCache
+	search.AzureCtrlr.InvCache = acctInvCache
+	search.GCPCtrlr.InvCache = acctInvCache
+}
+
 func (search Search) doAccountLevelSearch(acctID string, doNetMapping bool) (generalResource.Resource, error) {
 	var acctAliases []string
 	var matchingResource generalResource.Resource
 	var err error
 
+	search.scopeInvCache(acctID)
+
 	if acctID != "current" && search.Platform == "aws" {
 		// resolve account's aliases
 		iamp := iamp.IAMPlugin{AwsConn: search.AWSCtrlr.PrincipalAWSConn}
@@ -118,6 +254,8 @@ func (search Search) doAccountLevelSearch(acctID string, doNetMapping bool) (gen
 		}
 
 		log.Info("starting resource search in AWS account: ", acctID, " ", acctAliases)
+	} else if acctID != "current" {
+		log.Info("starting resource search in ", strings.ToUpper(search.Platform), " project: ", acctID)
 	} else {
 		log.Info("starting resource search in current account")
 	}
@@ -127,9 +265,14 @@ func (search Search) doAccountLevelSearch(acctID string, doNetMapping bool) (gen
 		case "aws":
 			matchingResource, err = search.AWSCtrlr.SearchAWSSvc(search.IpAddr, svc, doNetMapping)
 		case "azure":
-			matchingResource, err = search.AzureCtrlr.SearchAzureSvc(search.TenantID, search.IpAddr, svc, &matchingResource)
+			matchingResource, err = search.AzureCtrlr.SearchAzureSvc(search.TenantID, search.IpAddr, svc, doNetMapping, &matchingResource)
 		case "gcp":
-			matchingResource, err = search.GCPCtrlr.SearchGCPSvc(search.TenantID, search.IpAddr, svc, &matchingResource)
+			projectID := search.TenantID
+			if acctID != "current" {
+				projectID = acctID
+			}
+
+			matchingResource, err = search.GCPCtrlr.SearchGCPSvc(projectID, search.IpAddr, svc, doNetMapping, &matchingResource)
 		default:
 			errorMsg := fmt.Sprintf("%s is not a supported platform for searching", search.Platform)
 			return matchingResource, errors.New(errorMsg)
@@ -139,7 +282,10 @@ func (search Search) doAccountLevelSearch(acctID string, doNetMapping bool) (gen
 			return matchingResource, err
 		} else if matchingResource.RID != "" {
 			// resource was found
-			matchingResource.AccountID = acctID
+			if svc != "cloud_asset" {
+				// asset search results already include the project they were found in, which is more specific than the org/folder scope searched
+				matchingResource.AccountID = acctID
+			}
 			matchingResource.AccountAliases = acctAliases
 
 			break
@@ -149,22 +295,29 @@ func (search Search) doAccountLevelSearch(acctID string, doNetMapping bool) (gen
 	return matchingResource, nil
 }
 
-func (search Search) runSearchWorker(matchingResourceBuffer chan<- generalResource.Resource, acctID string, orgSearchRoleName string, doNetMapping bool, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	// org support is only available for AWS at this time
+func (search *Search) assumeAcctRole(acctID string, orgSearchRoleName string) error {
+	// org support requires assuming a role in each account for AWS only; GCP and Azure identities can access other projects/subscriptions directly
 	if acctID != "current" && search.Platform == "aws" {
 		// replace connector with assumed role connector before running rest of logic
 		acctRoleArn := fmt.Sprintf("arn:aws:iam::%s:role/%s", acctID, orgSearchRoleName)
 		ac, err := awsconnector.NewAWSConnectorAssumeRole(acctRoleArn, aws.Config{})
 		if err != nil {
-			log.Error("error when assuming role for account search worker: ", err)
-			return
+			return err
 		}
 
 		search.AWSCtrlr.PrincipalAWSConn = ac
 	}
 
+	return nil
+}
+
+func (search Search) runSearchWorker(matchingResourceBuffer chan<- generalResource.Resource, acctID string, orgSearchRoleName string, doNetMapping bool) {
+	err := search.assumeAcctRole(acctID, orgSearchRoleName)
+	if err != nil {
+		log.Error("error when assuming role for account search worker: ", err)
+		return
+	}
+
 	resultResource, err := search.doAccountLevelSearch(acctID, doNetMapping)
 	if err != nil {
 		log.Error("error when running search within account search worker: ", err)
@@ -177,19 +330,41 @@ func (search Search) runSearchWorker(matchingResourceBuffer chan<- generalResour
 func (search *Search) initSearchWorkers(acctsToSearch []string, orgSearchRoleName string, doNetMapping bool) bool {
 	log.Info("beginning resource gathering")
 
-	matchingResourceBuffer := make(chan generalResource.Resource, 1)
+	matchingResourceBuffer := make(chan generalResource.Resource, len(acctsToSearch))
+	// closed once a match is found so that workers still waiting for a slot don't bother searching
+	matchFound := make(chan struct{})
 	var wg sync.WaitGroup
 
+	// orgs can have hundreds (or thousands) of accounts/projects, so we cap how many are searched at once to avoid API throttling
+	workerLimit := search.OrgSearchMaxWorkers
+	if workerLimit <= 0 {
+		workerLimit = len(acctsToSearch)
+	}
+	workerSlots := make(chan struct{}, max(workerLimit, 1))
+
 	for _, acctID := range acctsToSearch {
 		wg.Add(1)
-		go rollbar.WrapAndWait(
-			search.runSearchWorker,
-			matchingResourceBuffer,
-			acctID,
-			orgSearchRoleName,
-			doNetMapping,
-			&wg,
-		)
+		// each worker gets its own copy of the search, so the next IP's search can't change it out from under them
+		go func(acctSearch Search, acctID string) {
+			defer wg.Done()
+
+			workerSlots <- struct{}{}
+			defer func() { <-workerSlots }()
+
+			select {
+			case <-matchFound:
+				return
+			default:
+			}
+
+			rollbar.WrapAndWait(
+				acctSearch.runSearchWorker,
+				matchingResourceBuffer,
+				acctID,
+				orgSearchRoleName,
+				doNetMapping,
+			)
+		}(*search, acctID)
 	}
 
 	go func() {
@@ -200,11 +375,95 @@ func (search *Search) initSearchWorkers(acctsToSearch []string, orgSearchRoleNam
 	resultResource, found := <-matchingResourceBuffer
 	if found {
 		search.MatchedResource = resultResource
+		close(matchFound)
+
+		// other workers may also find a match (e.g. an asset search across an org); the buffer has room for all of them, so we just wait for them to finish
+		wg.Wait()
 	}
 
 	return found
 }
 
+func (search *Search) fetchAcctsToSearch(doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchOrgUnitID string) ([]string, error) {
+	var acctsToSearch []string
+	var err error
+
+	if search.Snapshot != nil {
+		// the snapshot already covers every account it was taken with
+		acctsToSearch = append(acctsToSearch, "current")
+	} else if search.Platform == "gcp" && search.GCPAssetSearch {
+		// Cloud Asset Inventory covers every project under the org or folder in a single query
+		if doOrgSearch && orgSearchOrgUnitID != "" {
+			acctsToSearch = append(acctsToSearch, resource_manager.NormalizeParentID(orgSearchOrgUnitID))
+		} else {
+			acctsToSearch = append(acctsToSearch, "current")
+		}
+	} else if doOrgSearch {
+		log.Info("starting org account enumeration")
+
+		switch search.Platform {
+		case "aws":
+			acctsToSearch, err = search.AWSCtrlr.FetchOrgAcctIds(orgSearchOrgUnitID, orgSearchXaccountRoleARN)
+		case "gcp":
+			// for GCP, the OU ID is the organization or folder to enumerate projects under
+			acctsToSearch, err = search.GCPCtrlr.FetchProjectIDs(orgSearchOrgUnitID)
+		default:
+			err = fmt.Errorf("org search is not supported for %s", search.Platform)
+		}
+		if err != nil {
+			return acctsToSearch, err
+		}
+
+		log.Info("found [ ", len(acctsToSearch), " ] accounts/projects to search")
+	} else {
+		acctsToSearch = append(acctsToSearch, "current")
+	}
+
+	return acctsToSearch, nil
+}
+
+func (search *Search) searchIPAddr(cloudSvc string, doIPFuzzing bool, doAdvIPFuzzing bool, acctsToSearch []string, orgSearchRoleName string, doNetMapping bool) (bool, error) {
+	var err error
+
+	search.MatchedResource = generalResource.Resource{}
+	// the region is set per IP by IP fuzzing, so it shouldn't carry over from a previous search
+	search.GCPCtrlr.Region = ""
+
+	// TODO: move this to init function
+	search.CloudSvcs = search.ReconcileCloudSvcParam(cloudSvc)
+
+	if search.Snapshot != nil {
+		snapshotResources := search.searchSnapshot(cloudSvc, func(snapshotResource generalResource.Resource) bool {
+			return slices.Contains(snapshotResource.PublicIPv4Addrs, search.IpAddr) || slices.Contains(snapshotResource.PublicIPv6Addrs, search.IpAddr)
+		})
+		if len(snapshotResources) == 0 {
+			return false, nil
+		}
+
+		search.MatchedResource = snapshotResources[0]
+
+		return true, nil
+	}
+
+	if doIPFuzzing || doAdvIPFuzzing {
+		if search.Platform == "gcp" {
+			search.CloudSvcs, err = search.RunGCPIPFuzzing()
+		} else {
+			search.CloudSvcs, err = search.RunIPFuzzing(doAdvIPFuzzing)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if search.Platform == "gcp" && search.GCPAssetSearch && len(search.CloudSvcs) > 0 {
+		// Cloud Asset Inventory covers every supported service in a single query
+		search.CloudSvcs = []string{"cloud_asset"}
+	}
+
+	return search.initSearchWorkers(acctsToSearch, orgSearchRoleName, doNetMapping), nil
+}
+
 func (search *Search) StartSearch(cloudSvc string, doIPFuzzing bool, doAdvIPFuzzing bool, doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchRoleName string, orgSearchOrgUnitID string, doNetMapping bool) (bool, error) {
 	var resourceFound bool
 	var err error
@@ -214,29 +473,369 @@ func (search *Search) StartSearch(cloudSvc string, doIPFuzzing bool, doAdvIPFuzz
 		log.Fatal("error when connecting to ", search.Platform, ": ", err)
 	}
 
-	// TODO: move this to init function
-	search.CloudSvcs = search.ReconcileCloudSvcParam(cloudSvc)
+	acctsToSearch, err := search.fetchAcctsToSearch(doOrgSearch, orgSearchXaccountRoleARN, orgSearchOrgUnitID)
+	if err != nil {
+		return resourceFound, err
+	}
 
-	if doIPFuzzing || doAdvIPFuzzing {
-		search.CloudSvcs, err = search.RunIPFuzzing(doAdvIPFuzzing)
+	resourceFound, err = search.searchIPAddr(cloudSvc, doIPFuzzing, doAdvIPFuzzing, acctsToSearch, orgSearchRoleName, doNetMapping)
+	if err != nil {
+		return resourceFound, err
+	}
+
+	return resourceFound, nil
+}
+
+func (search *Search) StartBulkSearch(ipAddrs []string, resultHandler func(BulkSearchResult) error, cloudSvc string, doIPFuzzing bool, doAdvIPFuzzing bool, doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchRoleName string, orgSearchOrgUnitID string, doNetMapping bool) error {
+	// connects and enumerates accounts once, then searches each IP against resource inventories that are only fetched the first time they're needed
+	_, err := search.connectToPlatform()
+	if err != nil {
+		return err
+	}
+
+	if search.InvCache == nil {
+		search.InvCache = inventorycache.New()
+	}
+
+	acctsToSearch, err := search.fetchAcctsToSearch(doOrgSearch, orgSearchXaccountRoleARN, orgSearchOrgUnitID)
+	if err != nil {
+		return err
+	}
+
+	return search.searchIPAddrs(ipAddrs, resultHandler, cloudSvc, doIPFuzzing, doAdvIPFuzzing, acctsToSearch, orgSearchRoleName, doNetMapping)
+}
+
+func (search *Search) searchIPAddrs(ipAddrs []string, resultHandler func(BulkSearchResult) error, cloudSvc string, doIPFuzzing bool, doAdvIPFuzzing bool, acctsToSearch []string, orgSearchRoleName string, doNetMapping bool) error {
+	// the handler can stop the search early by returning an error, e.g. once whoever wanted the results has gone away
+	for _, ipAddr := range ipAddrs {
+		log.Info("searching for IP ", ipAddr)
+
+		search.IpAddr = ipAddr
+		result := BulkSearchResult{IPAddr: ipAddr}
+
+		// an error with one IP (e.g. an invalid address in the list) shouldn't stop the rest from being searched
+		if _, err := utils.DetermineIpAddrVersion(ipAddr); err != nil {
+			result.Error = err.Error()
+		} else if result.Found, err = search.searchIPAddr(cloudSvc, doIPFuzzing, doAdvIPFuzzing, acctsToSearch, orgSearchRoleName, doNetMapping); err != nil {
+			result.Error = err.Error()
+		}
+		result.Resource = search.MatchedResource
+
+		err := resultHandler(result)
 		if err != nil {
-			return resourceFound, err
+			return err
 		}
 	}
 
-	var acctsToSearch []string
-	if doOrgSearch {
-		log.Info("starting org account enumeration")
+	return nil
+}
 
-		acctsToSearch, err = search.AWSCtrlr.FetchOrgAcctIds(orgSearchOrgUnitID, orgSearchXaccountRoleARN)
+func (search Search) FetchAcctInventory(acctID string) ([]generalResource.Resource, error) {
+	// gathers every resource and its public IPs across the search's services within a single account/project/subscription
+	var inventory []generalResource.Resource
+	var acctAliases []string
+	var err error
+
+	search.scopeInvCache(acctID)
+
+	if acctID != "current" && search.Platform == "aws" {
+		iamp := iamp.IAMPlugin{AwsConn: search.AWSCtrlr.PrincipalAWSConn}
+		acctAliases, err = iamp.GetResources()
 		if err != nil {
-			return resourceFound, err
+			return inventory, err
 		}
-	} else {
-		acctsToSearch = append(acctsToSearch, "current")
 	}
 
-	resourceFound = search.initSearchWorkers(acctsToSearch, orgSearchRoleName, doNetMapping)
+	for _, svc := range search.CloudSvcs {
+		var svcInventory []generalResource.Resource
 
-	return resourceFound, nil
+		switch search.Platform {
+		case "aws":
+			svcInventory, err = search.AWSCtrlr.GetAWSSvcInventory(svc)
+		case "azure":
+			svcInventory, err = search.AzureCtrlr.GetAzureSvcInventory(search.TenantID, svc)
+		case "gcp":
+			projectID := search.TenantID
+			if acctID != "current" {
+				projectID = acctID
+			}
+
+			svcInventory, err = search.GCPCtrlr.GetGCPSvcInventory(projectID, svc)
+		default:
+			err = fmt.Errorf("%s is not a supported platform for searching", search.Platform)
+		}
+		if err != nil {
+			return inventory, err
+		}
+
+		for _, svcResource := range svcInventory {
+			if svcResource.AccountID == "" || acctID != "current" {
+				svcResource.AccountID = acctID
+			}
+			svcResource.AccountAliases = acctAliases
+
+			inventory = append(inventory, svcResource)
+		}
+	}
+
+	return inventory, nil
+}
+
+func (search Search) searchSnapshot(cloudSvc string, filterFunc func(generalResource.Resource) bool) []generalResource.Resource {
+	// returns every resource in the snapshot for the search's platform and service(s) that the filter function accepts
+	var resources []generalResource.Resource
+
+	cloudSvcs := search.ReconcileCloudSvcParam(cloudSvc)
+
+	for _, snapshotResource := range search.Snapshot.GetResources(search.Platform) {
+		if cloudSvc != "all" && !slices.Contains(cloudSvcs, snapshotResource.CloudSvc) {
+			continue
+		}
+
+		if filterFunc(snapshotResource) {
+			resources = append(resources, snapshotResource)
+		}
+	}
+
+	return resources
+}
+
+func (search *Search) runInventoryWorkers(acctsToSearch []string, orgSearchRoleName string, filterFunc func(generalResource.Resource) bool) ([]generalResource.Resource, error) {
+	// fetches the inventory of each account in parallel, returning every resource the filter function accepts along with any accounts that couldn't be searched
+	var resources []generalResource.Resource
+	var resourcesMu sync.Mutex
+	acctErrs := make(map[string]error)
+	var wg sync.WaitGroup
+
+	workerLimit := search.OrgSearchMaxWorkers
+	if workerLimit <= 0 {
+		workerLimit = len(acctsToSearch)
+	}
+	workerSlots := make(chan struct{}, max(workerLimit, 1))
+
+	for _, acctID := range acctsToSearch {
+		wg.Add(1)
+		go func(acctSearch Search, acctID string) {
+			defer wg.Done()
+
+			workerSlots <- struct{}{}
+			defer func() { <-workerSlots }()
+
+			acctInventory, err := func() ([]generalResource.Resource, error) {
+				err := acctSearch.assumeAcctRole(acctID, orgSearchRoleName)
+				if err != nil {
+					return nil, err
+				}
+
+				return acctSearch.FetchAcctInventory(acctID)
+			}()
+
+			resourcesMu.Lock()
+			defer resourcesMu.Unlock()
+
+			if err != nil {
+				log.Error("error when fetching inventory for account ", acctID, ": ", err)
+				acctErrs[acctID] = err
+				return
+			}
+
+			for _, acctResource := range acctInventory {
+				if filterFunc(acctResource) {
+					resources = append(resources, acctResource)
+				}
+			}
+		}(*search, acctID)
+	}
+
+	wg.Wait()
+
+	// workers finish in any order, so we sort the results to keep output stable between runs
+	sort.SliceStable(resources, func(i, j int) bool {
+		if resources[i].AccountID != resources[j].AccountID {
+			return resources[i].AccountID < resources[j].AccountID
+		}
+
+		return resources[i].RID < resources[j].RID
+	})
+
+	if len(acctErrs) > 0 {
+		return resources, AcctSearchError{AcctErrs: acctErrs}
+	}
+
+	return resources, nil
+}
+
+func (search *Search) searchInventory(cloudSvc string, doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchRoleName string, orgSearchOrgUnitID string, filterFunc func(generalResource.Resource) bool) ([]generalResource.Resource, error) {
+	// fetches the inventory of every selected service and account, returning each resource the filter function accepts
+	// if some accounts can't be searched, the results from the rest are returned with an AcctSearchError listing them
+	if search.Snapshot != nil {
+		_, err := search.connectToPlatform()
+		if err != nil {
+			return nil, err
+		}
+
+		return search.searchSnapshot(cloudSvc, filterFunc), nil
+	}
+
+	if search.Platform == "gcp" && search.GCPAssetSearch {
+		return nil, errors.New("inventory-based searches are not supported with GCP Cloud Asset Inventory search")
+	}
+
+	_, err := search.connectToPlatform()
+	if err != nil {
+		return nil, err
+	}
+
+	if search.InvCache == nil {
+		search.InvCache = inventorycache.New()
+	}
+
+	search.CloudSvcs = search.ReconcileCloudSvcParam(cloudSvc)
+
+	acctsToSearch, err := search.fetchAcctsToSearch(doOrgSearch, orgSearchXaccountRoleARN, orgSearchOrgUnitID)
+	if err != nil {
+		return nil, err
+	}
+
+	return search.runInventoryWorkers(acctsToSearch, orgSearchRoleName, filterFunc)
+}
+
+func (search *Search) StartRangeSearch(ipRange utils.IPRange, cloudSvc string, doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchRoleName string, orgSearchOrgUnitID string) ([]generalResource.Resource, error) {
+	// returns every resource with at least one IP in the range instead of stopping at the first match
+	log.Info("searching for resources with IPs in range ", ipRange)
+
+	return search.searchInventory(cloudSvc, doOrgSearch, orgSearchXaccountRoleARN, orgSearchRoleName, orgSearchOrgUnitID, func(acctResource generalResource.Resource) bool {
+		return ipRange.ContainsAny(acctResource.PublicIPv4Addrs) || ipRange.ContainsAny(acctResource.PublicIPv6Addrs)
+	})
+}
+
+func (search *Search) StartInventory(cloudSvc string, doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchRoleName string, orgSearchOrgUnitID string) ([]generalResource.Resource, error) {
+	// returns every resource with at least one public IP, i.e. the attack surface of the selected services and accounts
+	log.Info("gathering inventory of resources with public IPs")
+
+	return search.searchInventory(cloudSvc, doOrgSearch, orgSearchXaccountRoleARN, orgSearchRoleName, orgSearchOrgUnitID, func(acctResource generalResource.Resource) bool {
+		return len(acctResource.PublicIPv4Addrs) > 0 || len(acctResource.PublicIPv6Addrs) > 0
+	})
+}
+
+func (search *Search) StartHostSearch(host string, followCNAMEs bool, cloudSvc string, doIPFuzzing bool, doAdvIPFuzzing bool, doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchRoleName string, orgSearchOrgUnitID string, doNetMapping bool) (HostSearchResult, error) {
+	// resolves the host and searches each of its IPs, unless it points at a cloud service hostname, which is matched by name instead
+	var hostSearchResult HostSearchResult
+
+	hostResolution, err := hostlookup.Resolve(host, followCNAMEs)
+	if err != nil {
+		return hostSearchResult, err
+	}
+
+	hostSearchResult = HostSearchResult{
+		Host:               hostResolution.Host,
+		CNAMEChain:         hostResolution.CNAMEChain,
+		IPAddrs:            hostResolution.IPAddrs,
+		RecognizedHostname: hostResolution.RecognizedHostname,
+	}
+
+	recognizedHostname := hostResolution.RecognizedHostname
+	if recognizedHostname.Platform != "" && recognizedHostname.Platform != search.Platform {
+		log.Warn(recognizedHostname.FQDN, " is a ", strings.ToUpper(recognizedHostname.Platform), " hostname; search ", strings.ToUpper(recognizedHostname.Platform), " to find the resource it belongs to")
+
+		return hostSearchResult, nil
+	}
+
+	_, err = search.connectToPlatform()
+	if err != nil {
+		return hostSearchResult, err
+	}
+
+	if search.InvCache == nil {
+		search.InvCache = inventorycache.New()
+	}
+
+	acctsToSearch, err := search.fetchAcctsToSearch(doOrgSearch, orgSearchXaccountRoleARN, orgSearchOrgUnitID)
+	if err != nil {
+		return hostSearchResult, err
+	}
+
+	if recognizedHostname.Platform != "" {
+		log.Info(host, " points to ", recognizedHostname.FQDN, "; searching ", strings.Join(recognizedHostname.CloudSvcs, ", "), " resources by hostname")
+
+		hostnameFilterFunc := func(acctResource generalResource.Resource) bool {
+			return slices.Contains(acctResource.FQDNs, recognizedHostname.FQDN)
+		}
+
+		search.CloudSvcs = recognizedHostname.CloudSvcs
+		if search.Snapshot != nil {
+			hostSearchResult.Resources = search.searchSnapshot(strings.Join(recognizedHostname.CloudSvcs, ","), hostnameFilterFunc)
+		} else {
+			hostSearchResult.Resources, err = search.runInventoryWorkers(acctsToSearch, orgSearchRoleName, hostnameFilterFunc)
+		}
+
+		return hostSearchResult, err
+	}
+
+	err = search.searchIPAddrs(
+		hostResolution.IPAddrs,
+		func(result BulkSearchResult) error {
+			hostSearchResult.Results = append(hostSearchResult.Results, result)
+			return nil
+		},
+		cloudSvc,
+		doIPFuzzing,
+		doAdvIPFuzzing,
+		acctsToSearch,
+		orgSearchRoleName,
+		doNetMapping,
+	)
+
+	return hostSearchResult, err
+}
+
+func GetConfidenceRank(confidence string) int {
+	return slices.Index([]string{iphistory.ConfidenceNone, iphistory.ConfidenceLow, iphistory.ConfidenceMedium, iphistory.ConfidenceHigh}, confidence)
+}
+
+func (search *Search) StartHistoricalSearch(at time.Time, lookback time.Duration, doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchRoleName string, orgSearchOrgUnitID string) (iphistory.Attribution, error) {
+	// determines who owned the IP at a point in time, returning the attribution with the strongest evidence across all accounts searched
+	attribution := iphistory.Attribution{IPAddr: search.IpAddr, At: at, Confidence: iphistory.ConfidenceNone}
+
+	if search.Platform != "aws" {
+		return attribution, fmt.Errorf("historical searches are not supported for %s", search.Platform)
+	} else if search.Snapshot != nil {
+		return attribution, errors.New("historical searches are not supported with snapshots")
+	}
+
+	_, err := search.connectToPlatform()
+	if err != nil {
+		return attribution, err
+	}
+
+	acctsToSearch, err := search.fetchAcctsToSearch(doOrgSearch, orgSearchXaccountRoleARN, orgSearchOrgUnitID)
+	if err != nil {
+		return attribution, err
+	}
+
+	for _, acctID := range acctsToSearch {
+		acctSearch := *search
+
+		err = acctSearch.assumeAcctRole(acctID, orgSearchRoleName)
+		if err != nil {
+			log.Error("error when assuming role for historical search of account ", acctID, ": ", err)
+			continue
+		}
+
+		acctAttribution, err := acctSearch.AWSCtrlr.GetIPAttribution(search.IpAddr, at, lookback)
+		if err != nil {
+			log.Error("error when fetching IP history for account ", acctID, ": ", err)
+			continue
+		}
+
+		if acctAttribution.Resource.RID != "" && acctAttribution.Resource.AccountID == "" {
+			acctAttribution.Resource.AccountID = acctID
+		}
+
+		if GetConfidenceRank(acctAttribution.Confidence) > GetConfidenceRank(attribution.Confidence) {
+			attribution = acctAttribution
+		}
+	}
+
+	return attribution, nil
 }
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+
+	gcpcontroller "github.com/magneticstain/ip-2-cloudresource/gcp"
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	inventorycache "github.com/magneticstain/ip-2-cloudresource/inventory_cache"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func computeAPIFactory(t *testing.T, projectIPs map[string]string) *httptest.Server {
+	// serves an aggregated instance list for each project, with a single instance using the project's IP
+	computeAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		projectID := strings.Split(strings.TrimPrefix(r.URL.Path, "/compute/v1/projects/"), "/")[0]
+
+		if projectID == "project-denied" {
+			http.Error(w, `{"error": {"code": 403, "message": "permission denied"}}`, http.StatusForbidden)
+			return
+		}
+
+		// projects without a match respond slowly, so they're still being searched when another project's match comes back
+		ipAddr, found := projectIPs[projectID]
+		if !found {
+			time.Sleep(50 * time.Millisecond)
+			fmt.Fprint(w, `{"items": {}}`)
+			return
+		}
+
+		fmt.Fprintf(w, `{"items": {"zones/us-central1-a": {"instances": [{
+			"id": "1",
+			"name": "vm-%[1]s",
+			"status": "RUNNING",
+			"zone": "https://www.googleapis.com/compute/v1/projects/%[1]s/zones/us-central1-a",
+			"selfLink": "https://www.googleapis.com/compute/v1/projects/%[1]s/zones/us-central1-a/instances/vm-%[1]s",
+			"networkInterfaces": [{"name": "nic0", "accessConfigs": [{"natIP": "%[2]s"}]}]
+		}]}}}`, projectID, ipAddr)
+	}))
+	t.Cleanup(computeAPI.Close)
+
+	return computeAPI
+}
+
+func TestSearchIPAddrs_MultipleAccounts(t *testing.T) {
+	// each IP is matched in a different project while the other projects are still being searched; run with -race to catch workers sharing the search
+	projectIPs := map[string]string{
+		"project-a": "34.0.0.1",
+		"project-b": "34.0.0.2",
+	}
+	acctsToSearch := []string{"project-a", "project-b", "project-c", "project-d"}
+
+	computeAPI := computeAPIFactory(t, projectIPs)
+
+	bulkSearch := Search{
+		Platform: "gcp",
+		GCPCtrlr: gcpcontroller.GCPController{
+			GCPConn: gcpconnector.GCPConnector{
+				ClientOptions: []option.ClientOption{option.WithEndpoint(computeAPI.URL), option.WithoutAuthentication()},
+			},
+		},
+		InvCache: inventorycache.New(),
+	}
+
+	var results []BulkSearchResult
+	err := bulkSearch.searchIPAddrs([]string{"34.0.0.1", "34.0.0.2"}, func(result BulkSearchResult) error {
+		results = append(results, result)
+		return nil
+	}, "compute", false, false, acctsToSearch, "", false)
+	if err != nil {
+		t.Fatalf("Multi-account bulk search failed; received error: %s", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Multi-account bulk search failed; expected 2 results, received %d", len(results))
+	}
+
+	for _, result := range results {
+		expectedAcctID := "project-a"
+		if result.IPAddr == "34.0.0.2" {
+			expectedAcctID = "project-b"
+		}
+
+		if !result.Found || result.Resource.AccountID != expectedAcctID {
+			t.Errorf("Multi-account bulk search failed; expected %s to be found in %s, received %+v", result.IPAddr, expectedAcctID, result)
+		}
+	}
+}
+
+func TestRunInventoryWorkers_FailedAccounts(t *testing.T) {
+	computeAPI := computeAPIFactory(t, map[string]string{"project-a": "34.0.0.1"})
+
+	inventorySearch := Search{
+		Platform:  "gcp",
+		CloudSvcs: []string{"compute"},
+		GCPCtrlr: gcpcontroller.GCPController{
+			GCPConn: gcpconnector.GCPConnector{
+				ClientOptions: []option.ClientOption{option.WithEndpoint(computeAPI.URL), option.WithoutAuthentication()},
+			},
+		},
+		InvCache: inventorycache.New(),
+	}
+
+	inventory, err := inventorySearch.runInventoryWorkers([]string{"project-a", "project-denied"}, "", func(generalResource.Resource) bool { return true })
+
+	// the accounts that could be searched should still be returned
+	if len(inventory) != 1 || inventory[0].AccountID != "project-a" {
+		t.Errorf("Inventory with failed accounts failed; expected the project-a resource, received %+v", inventory)
+	}
+
+	var acctSearchErr AcctSearchError
+	if !errors.As(err, &acctSearchErr) {
+		t.Fatalf("Inventory with failed accounts failed; expected an account search error, received %v", err)
+	}
+
+	if _, found := acctSearchErr.AcctErrs["project-denied"]; !found || len(acctSearchErr.AcctErrs) != 1 {
+		t.Errorf("Inventory with failed accounts failed; expected only project-denied to fail, received %v", acctSearchErr.AcctErrs)
+	}
+}
@@ -1,16 +1,21 @@
 package search_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 
 	awscontroller "github.com/magneticstain/ip-2-cloudresource/aws"
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
 	gcpcontroller "github.com/magneticstain/ip-2-cloudresource/gcp"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/search"
+	"github.com/magneticstain/ip-2-cloudresource/snapshot"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
 	"golang.org/x/exp/slices"
 )
 
@@ -72,8 +77,11 @@ func TestReconcileCloudSvcParam_AllSvcs(t *testing.T) {
 		}},
 		{"gcp", "all", []string{
 			"compute",
+			"gke",
 			"load_balancing",
 			"cloud_sql",
+			"cloud_nat",
+			"vpn_gateway",
 		}},
 	}
 
@@ -458,3 +466,205 @@ func TestStartSearch_OrgSearchEnabled_TargetOUID_InvalidID(t *testing.T) {
 		})
 	}
 }
+
+func TestStartBulkSearch(t *testing.T) {
+	ipAddrs := []string{
+		"1.1.1.1",
+		"1234.45.9666.1",
+		"2600:9000:24eb:dc00:1:3b80:4f00:21",
+		"x2600:9000:24eb:XYZ1:1:3b80:4f00:21",
+	}
+
+	bulkSearch := searchFactory("")
+	bulkSearch.Platform = "aws"
+
+	var results []search.BulkSearchResult
+	err := bulkSearch.StartBulkSearch(ipAddrs, func(result search.BulkSearchResult) error {
+		results = append(results, result)
+		return nil
+	}, "ec2", false, false, false, "", "", "", false)
+	if err != nil {
+		t.Errorf("Overall bulk search failed; received error: %s", err)
+	}
+
+	if len(results) != len(ipAddrs) {
+		t.Fatalf("Overall bulk search failed; expected %d results, received %d", len(ipAddrs), len(results))
+	}
+
+	for i, result := range results {
+		if result.IPAddr != ipAddrs[i] {
+			t.Errorf("Overall bulk search failed; expected result for %s, received result for %s", ipAddrs[i], result.IPAddr)
+		}
+	}
+
+	for _, invalidResult := range []search.BulkSearchResult{results[1], results[3]} {
+		if invalidResult.Error == "" {
+			t.Errorf("Overall bulk search should have returned an error for invalid IP %s, but did not", invalidResult.IPAddr)
+		}
+	}
+}
+
+func TestStartRangeSearch(t *testing.T) {
+	var tests = []struct {
+		ipRange, cloudSvc string
+	}{
+		{"18.161.22.0/24", "ec2"},
+		{"18.161.22.1-18.161.22.61", "elbv1,elbv2"},
+		{"2600:9000:24eb::/48", "all"},
+	}
+
+	for _, td := range tests {
+		testName := td.ipRange
+
+		rangeSearch := searchFactory("")
+		rangeSearch.Platform = "aws"
+
+		t.Run(testName, func(t *testing.T) {
+			ipRange, _ := utils.ParseIPRange(td.ipRange)
+
+			// without credentials, the account can't be searched, which should be reported as such rather than as an overall failure
+			var acctSearchErr search.AcctSearchError
+			matchedResources, err := rangeSearch.StartRangeSearch(ipRange, td.cloudSvc, false, "", "", "")
+			if err != nil && !errors.As(err, &acctSearchErr) {
+				t.Errorf("Overall range search failed; received error: %s", err)
+			}
+
+			for _, matchedResource := range matchedResources {
+				if !ipRange.ContainsAny(matchedResource.PublicIPv4Addrs) && !ipRange.ContainsAny(matchedResource.PublicIPv6Addrs) {
+					t.Errorf("Overall range search failed; %s does not have an IP in range %s", matchedResource.RID, ipRange)
+				}
+			}
+		})
+	}
+}
+
+func TestStartRangeSearch_GCPAssetSearch(t *testing.T) {
+	rangeSearch := searchFactory("")
+	rangeSearch.Platform = "gcp"
+	rangeSearch.GCPAssetSearch = true
+
+	ipRange, _ := utils.ParseIPRange("18.161.22.0/24")
+
+	_, err := rangeSearch.StartRangeSearch(ipRange, "all", false, "", "", "")
+	if err == nil {
+		t.Errorf("Overall range search should have failed with GCP asset search enabled, but did not")
+	}
+}
+
+func TestStartInventory(t *testing.T) {
+	var tests = []struct {
+		cloudSvc string
+	}{
+		{"ec2"},
+		{"elbv1,elbv2"},
+		{"all"},
+	}
+
+	for _, td := range tests {
+		testName := td.cloudSvc
+
+		inventorySearch := searchFactory("")
+		inventorySearch.Platform = "aws"
+
+		t.Run(testName, func(t *testing.T) {
+			var acctSearchErr search.AcctSearchError
+			inventory, err := inventorySearch.StartInventory(td.cloudSvc, false, "", "", "")
+			if err != nil && !errors.As(err, &acctSearchErr) {
+				t.Errorf("Gathering inventory failed; received error: %s", err)
+			}
+
+			for _, inventoryResource := range inventory {
+				if len(inventoryResource.PublicIPv4Addrs) == 0 && len(inventoryResource.PublicIPv6Addrs) == 0 {
+					t.Errorf("Gathering inventory failed; %s does not have a public IP", inventoryResource.RID)
+				}
+			}
+		})
+	}
+}
+
+func snapshotFactory() *snapshot.Snapshot {
+	snap := snapshot.New("v0.0.0-test")
+
+	snap.AddPlatformInventory(snapshot.PlatformInventory{
+		Platform: "aws",
+		Resources: []generalResource.Resource{
+			{RID: "arn:aws:ec2:us-east-1:123456789012:instance/i-1234567890abcdef0", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.61"}},
+			{RID: "arn:aws:cloudfront::123456789012:distribution/E1234567890ABC", AccountID: "123456789012", CloudSvc: "cloudfront", PublicIPv6Addrs: []string{"2600:9000:24eb:dc00:1:3b80:4f00:21"}},
+		},
+	})
+
+	return &snap
+}
+
+func TestStartSearch_FromSnapshot(t *testing.T) {
+	var tests = []struct {
+		ipAddr, cloudSvc, expectedRID string
+	}{
+		{"18.161.22.61", "all", "arn:aws:ec2:us-east-1:123456789012:instance/i-1234567890abcdef0"},
+		{"18.161.22.61", "cloudfront", ""},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "all", "arn:aws:cloudfront::123456789012:distribution/E1234567890ABC"},
+		{"1.1.1.1", "all", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr + "_" + td.cloudSvc
+
+		snapshotSearch := search.Search{Platform: "aws", IpAddr: td.ipAddr, Snapshot: snapshotFactory()}
+
+		t.Run(testName, func(t *testing.T) {
+			resourceFound, err := snapshotSearch.StartSearch(td.cloudSvc, true, true, false, "", "", "", false)
+			if err != nil {
+				t.Fatalf("Snapshot search failed; received error: %s", err)
+			}
+
+			if resourceFound != (td.expectedRID != "") || snapshotSearch.MatchedResource.RID != td.expectedRID {
+				t.Errorf("Snapshot search failed; expected %s, received %s", td.expectedRID, snapshotSearch.MatchedResource.RID)
+			}
+		})
+	}
+}
+
+func TestStartRangeSearch_FromSnapshot(t *testing.T) {
+	snapshotSearch := search.Search{Platform: "aws", Snapshot: snapshotFactory()}
+	ipRange, _ := utils.ParseIPRange("18.161.22.0/24")
+
+	matchedResources, err := snapshotSearch.StartRangeSearch(ipRange, "all", false, "", "", "")
+	if err != nil {
+		t.Fatalf("Snapshot range search failed; received error: %s", err)
+	}
+
+	if len(matchedResources) != 1 {
+		t.Errorf("Snapshot range search failed; expected 1 resource, received %d", len(matchedResources))
+	}
+}
+
+func TestStartInventory_FromSnapshotMissingPlatform(t *testing.T) {
+	snapshotSearch := search.Search{Platform: "azure", Snapshot: snapshotFactory()}
+
+	_, err := snapshotSearch.StartInventory("all", false, "", "", "")
+	if err == nil {
+		t.Errorf("Snapshot inventory should have failed for a platform missing from the snapshot, but did not")
+	}
+}
+
+func TestStartHistoricalSearch_Unsupported(t *testing.T) {
+	var tests = []struct {
+		name, platform string
+		snap           *snapshot.Snapshot
+	}{
+		{"gcp", "gcp", nil},
+		{"azure", "azure", nil},
+		{"aws_snapshot", "aws", snapshotFactory()},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			historicalSearch := search.Search{Platform: td.platform, IpAddr: "18.161.22.61", Snapshot: td.snap}
+
+			_, err := historicalSearch.StartHistoricalSearch(time.Now().Add(-24*time.Hour), 24*time.Hour, false, "", "", "")
+			if err == nil {
+				t.Errorf("Historical search should have failed for %s, but did not", td.name)
+			}
+		})
+	}
+}
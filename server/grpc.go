@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	ip2crv1 "github.com/magneticstain/ip-2-cloudresource/api/ip2cr/v1"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	platformsearch "github.com/magneticstain/ip-2-cloudresource/search"
+)
+
+// implements the v1 gRPC API on top of the same warm searches used by the REST API
+type lookupService struct {
+	ip2crv1.UnimplementedLookupServiceServer
+
+	srv *Server
+}
+
+func NewPBResource(resource generalResource.Resource) *ip2crv1.Resource {
+	return &ip2crv1.Resource{
+		Id:              resource.Id,
+		Rid:             resource.RID,
+		AccountId:       resource.AccountID,
+		AccountAliases:  resource.AccountAliases,
+		Name:            resource.Name,
+		Status:          resource.Status,
+		CloudSvc:        resource.CloudSvc,
+		IpType:          resource.IPType,
+		Location:        resource.Location,
+		NetworkMap:      resource.NetworkMap,
+		PublicIpv4Addrs: resource.PublicIPv4Addrs,
+		PublicIpv6Addrs: resource.PublicIPv6Addrs,
+		Fqdns:           resource.FQDNs,
+	}
+}
+
+func NewPBLookupResponse(result platformsearch.BulkSearchResult) *ip2crv1.LookupResponse {
+	lookupResp := &ip2crv1.LookupResponse{
+		Ip:    result.IPAddr,
+		Found: result.Found,
+		Error: result.Error,
+	}
+	if result.Found {
+		lookupResp.Resource = NewPBResource(result.Resource)
+	}
+
+	return lookupResp
+}
+
+func getGRPCError(err error) error {
+	var reqErr InvalidRequestError
+	if errors.As(err, &reqErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	} else if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+func (svc *lookupService) Lookup(ctx context.Context, req *ip2crv1.LookupRequest) (*ip2crv1.LookupResponse, error) {
+	if req.GetIp() == "" {
+		return nil, status.Error(codes.InvalidArgument, "ip is required")
+	}
+
+	lookupReq := LookupRequest{
+		IPAddrs:  []string{req.GetIp()},
+		Platform: req.GetPlatform(),
+		TenantID: req.GetTenantId(),
+		CloudSvc: req.GetSvc(),
+	}
+
+	results, err := svc.srv.Lookup(ctx, lookupReq)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+
+	return NewPBLookupResponse(results[0]), nil
+}
+
+func (svc *lookupService) BatchLookup(req *ip2crv1.BatchLookupRequest, stream ip2crv1.LookupService_BatchLookupServer) error {
+	var sendErr error
+
+	lookupReq := LookupRequest{
+		IPAddrs:  req.GetIps(),
+		Platform: req.GetPlatform(),
+		TenantID: req.GetTenantId(),
+		CloudSvc: req.GetSvc(),
+	}
+
+	err := svc.srv.LookupEach(stream.Context(), lookupReq, func(result platformsearch.BulkSearchResult) error {
+		// once the client has gone away, there's no point in searching for the rest of the IPs
+		sendErr = stream.Send(NewPBLookupResponse(result))
+		return sendErr
+	})
+	if sendErr != nil {
+		return sendErr
+	} else if err != nil {
+		return getGRPCError(err)
+	}
+
+	return nil
+}
+
+func (svc *lookupService) StreamInventory(req *ip2crv1.StreamInventoryRequest, stream ip2crv1.LookupService_StreamInventoryServer) error {
+	lookupReq := LookupRequest{
+		Platform: req.GetPlatform(),
+		TenantID: req.GetTenantId(),
+		CloudSvc: req.GetSvc(),
+	}
+
+	inventory, err := svc.srv.Inventory(lookupReq)
+	if err != nil {
+		return getGRPCError(err)
+	}
+
+	for _, resource := range inventory {
+		err = stream.Send(NewPBResource(resource))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (srv *Server) checkGRPCAuth(ctx context.Context) error {
+	if srv.Config.APIToken == "" {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, authVal := range md.Get("authorization") {
+		reqToken, found := strings.CutPrefix(authVal, "Bearer ")
+		if found && subtle.ConstantTimeCompare([]byte(reqToken), []byte(srv.Config.APIToken)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "a valid API token is required")
+}
+
+func (srv *Server) GRPCServer() *grpc.Server {
+	// returns a gRPC server with the v1 API registered, using the same bearer token auth as the REST API
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			err := srv.checkGRPCAuth(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			log.Info("gRPC request: ", info.FullMethod)
+
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(svc any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := srv.checkGRPCAuth(stream.Context())
+			if err != nil {
+				return err
+			}
+
+			log.Info("gRPC request: ", info.FullMethod)
+
+			return handler(svc, stream)
+		}),
+	)
+
+	ip2crv1.RegisterLookupServiceServer(grpcServer, &lookupService{srv: srv})
+
+	return grpcServer
+}
+
+func (srv *Server) ServeGRPC(ctx context.Context, listenAddr string) error {
+	// serves until the context is cancelled, then gives in-flight lookups a chance to finish
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return err
+	}
+
+	grpcServer := srv.GRPCServer()
+
+	go func() {
+		<-ctx.Done()
+
+		log.Info("shutting down gRPC server")
+
+		grpcServer.GracefulStop()
+	}()
+
+	log.Info("gRPC server listening on ", listenAddr)
+
+	return grpcServer.Serve(listener)
+}
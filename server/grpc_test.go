@@ -0,0 +1,205 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	ip2crv1 "github.com/magneticstain/ip-2-cloudresource/api/ip2cr/v1"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/server"
+)
+
+func grpcClientFactory(t *testing.T, apiToken string) ip2crv1.LookupServiceClient {
+	// serves the API over an in-memory connection, so the tests don't need a free port
+	listener := bufconn.Listen(1024 * 1024)
+
+	grpcServer := serverFactory(apiToken).GRPCServer()
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Connecting to gRPC server failed; received error: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return ip2crv1.NewLookupServiceClient(conn)
+}
+
+func TestNewPBResource(t *testing.T) {
+	resource := generalResource.Resource{
+		RID:             "arn:aws:ec2:us-east-1:123456789012:instance/i-1234567890abcdef0",
+		AccountID:       "123456789012",
+		AccountAliases:  []string{"prod"},
+		CloudSvc:        "ec2",
+		PublicIPv4Addrs: []string{"18.161.22.61"},
+		FQDNs:           []string{"example.com"},
+	}
+
+	pbResource := server.NewPBResource(resource)
+
+	if pbResource.GetRid() != resource.RID || pbResource.GetAccountId() != resource.AccountID || pbResource.GetCloudSvc() != resource.CloudSvc {
+		t.Errorf("Converting resource to protobuf failed; expected %+v, received %+v", resource, pbResource)
+	}
+
+	if len(pbResource.GetPublicIpv4Addrs()) != 1 || len(pbResource.GetAccountAliases()) != 1 || len(pbResource.GetFqdns()) != 1 {
+		t.Errorf("Converting resource to protobuf failed; list fields missing from %+v", pbResource)
+	}
+}
+
+func TestGRPC_Lookup(t *testing.T) {
+	var tests = []struct {
+		req          *ip2crv1.LookupRequest
+		expectedRID  string
+		expectedCode codes.Code
+	}{
+		{&ip2crv1.LookupRequest{Ip: "18.161.22.61"}, "arn:aws:ec2:us-east-1:123456789012:instance/i-1234567890abcdef0", codes.OK},
+		{&ip2crv1.LookupRequest{Ip: "2600:9000:24eb:dc00:1:3b80:4f00:21", Svc: "cloudfront"}, "arn:aws:cloudfront::123456789012:distribution/E1234567890ABC", codes.OK},
+		{&ip2crv1.LookupRequest{Ip: "1.1.1.1"}, "", codes.OK},
+		{&ip2crv1.LookupRequest{}, "", codes.InvalidArgument},
+		{&ip2crv1.LookupRequest{Ip: "18.161.22.61", Platform: "oci"}, "", codes.InvalidArgument},
+		{&ip2crv1.LookupRequest{Ip: "18.161.22.61", Platform: "azure"}, "", codes.Internal},
+	}
+
+	client := grpcClientFactory(t, "")
+
+	for _, td := range tests {
+		testName := td.req.GetIp() + "_" + td.req.GetPlatform()
+
+		t.Run(testName, func(t *testing.T) {
+			lookupResp, err := client.Lookup(context.Background(), td.req)
+			if status.Code(err) != td.expectedCode {
+				t.Fatalf("gRPC lookup failed; expected code %s, received %s", td.expectedCode, status.Code(err))
+			}
+
+			if err == nil && (lookupResp.GetFound() != (td.expectedRID != "") || lookupResp.GetResource().GetRid() != td.expectedRID) {
+				t.Errorf("gRPC lookup failed; expected %s, received %s", td.expectedRID, lookupResp.GetResource().GetRid())
+			}
+		})
+	}
+}
+
+func TestGRPC_BatchLookup(t *testing.T) {
+	client := grpcClientFactory(t, "")
+
+	stream, err := client.BatchLookup(context.Background(), &ip2crv1.BatchLookupRequest{Ips: []string{"18.161.22.61", "1.1.1.1", "not-an-ip"}})
+	if err != nil {
+		t.Fatalf("gRPC batch lookup failed; received error: %s", err)
+	}
+
+	results := map[string]*ip2crv1.LookupResponse{}
+	for {
+		lookupResp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("gRPC batch lookup failed; received error: %s", err)
+		}
+
+		results[lookupResp.GetIp()] = lookupResp
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("gRPC batch lookup failed; expected 3 results, received %d", len(results))
+	}
+
+	if !results["18.161.22.61"].GetFound() || results["1.1.1.1"].GetFound() || results["not-an-ip"].GetError() == "" {
+		t.Errorf("gRPC batch lookup failed; received unexpected results: %v", results)
+	}
+}
+
+func TestGRPC_BatchLookup_OverMaxBatchSize(t *testing.T) {
+	client := grpcClientFactory(t, "")
+
+	stream, err := client.BatchLookup(context.Background(), &ip2crv1.BatchLookupRequest{Ips: []string{"1.1.1.1", "1.1.1.2", "1.1.1.3", "1.1.1.4"}})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("gRPC batch lookup size check failed; expected code %s, received %s", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestGRPC_StreamInventory(t *testing.T) {
+	var tests = []struct {
+		svc           string
+		expectedCount int
+	}{
+		{"all", 2},
+		{"ec2", 1},
+	}
+
+	client := grpcClientFactory(t, "")
+
+	for _, td := range tests {
+		t.Run(td.svc, func(t *testing.T) {
+			stream, err := client.StreamInventory(context.Background(), &ip2crv1.StreamInventoryRequest{Svc: td.svc})
+			if err != nil {
+				t.Fatalf("gRPC inventory stream failed; received error: %s", err)
+			}
+
+			resourceCount := 0
+			for {
+				_, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				} else if err != nil {
+					t.Fatalf("gRPC inventory stream failed; received error: %s", err)
+				}
+
+				resourceCount++
+			}
+
+			if resourceCount != td.expectedCount {
+				t.Errorf("gRPC inventory stream failed; expected %d resources, received %d", td.expectedCount, resourceCount)
+			}
+		})
+	}
+}
+
+func TestGRPC_Auth(t *testing.T) {
+	var tests = []struct {
+		authHeader   string
+		expectedCode codes.Code
+	}{
+		{"", codes.Unauthenticated},
+		{"Bearer wrong-token", codes.Unauthenticated},
+		{"Bearer test-token", codes.OK},
+	}
+
+	client := grpcClientFactory(t, "test-token")
+
+	for _, td := range tests {
+		t.Run(td.authHeader, func(t *testing.T) {
+			ctx := context.Background()
+			if td.authHeader != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", td.authHeader)
+			}
+
+			_, err := client.Lookup(ctx, &ip2crv1.LookupRequest{Ip: "18.161.22.61"})
+			if status.Code(err) != td.expectedCode {
+				t.Errorf("Authenticated gRPC lookup failed; expected code %s, received %s", td.expectedCode, status.Code(err))
+			}
+		})
+	}
+}
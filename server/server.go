@@ -0,0 +1,402 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	azureconnector "github.com/magneticstain/ip-2-cloudresource/azure/azure_connector"
+	gcpconnector "github.com/magneticstain/ip-2-cloudresource/gcp/gcp_connector"
+	iplist "github.com/magneticstain/ip-2-cloudresource/ip_list"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	platformsearch "github.com/magneticstain/ip-2-cloudresource/search"
+	"github.com/magneticstain/ip-2-cloudresource/snapshot"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+const maxRequestBodyBytes = 1 << 20
+
+type ServerConfig struct {
+	// used when a request doesn't specify them; tenant IDs are keyed by platform, with "" used for any platform without its own
+	DefaultPlatform                 string
+	DefaultTenantIDs                map[string]string
+	AzureConnConfig                 azureconnector.AzureConnectorConfig
+	GCPConnConfig                   gcpconnector.GCPConnectorConfig
+	OrgSearch                       bool
+	OrgSearchXaccountRoleARN        string
+	OrgSearchRoleName               string
+	OrgSearchOrgUnitID              string
+	OrgSearchMaxWorkers             int
+	GCPAssetSearch, MatchPrivateIPs bool
+	IPFuzzing, AdvIPFuzzing         bool
+	NetworkMapping                  bool
+	// how long cached inventories are reused before being fetched again; <= 0 means they're never refreshed
+	CacheTTL time.Duration
+	// max number of IPs accepted in a single batch lookup
+	MaxBatchSize int
+	// if set, requests must include it as a bearer token
+	APIToken string
+	Snapshot *snapshot.Snapshot
+}
+
+type LookupRequest struct {
+	IPAddrs  []string `json:"ips"`
+	Platform string   `json:"platform"`
+	TenantID string   `json:"tenant_id"`
+	CloudSvc string   `json:"svc"`
+}
+
+type BatchLookupResponse struct {
+	Results []platformsearch.BulkSearchResult
+}
+
+type ErrorResponse struct {
+	Error string
+}
+
+// returned for requests that can never succeed, as opposed to errors when searching (e.g. failing to connect to the platform)
+type InvalidRequestError struct {
+	Err error
+}
+
+func (reqErr InvalidRequestError) Error() string {
+	return reqErr.Err.Error()
+}
+
+// a connected search for a single platform and tenant, shared between requests
+type warmSearch struct {
+	search   platformsearch.Search
+	warmedAt time.Time
+}
+
+type Server struct {
+	Config ServerConfig
+
+	mu           sync.Mutex
+	warmSearches map[string]*warmSearch
+}
+
+func New(config ServerConfig) *Server {
+	if config.DefaultPlatform == "" {
+		config.DefaultPlatform = "aws"
+	}
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = 1000
+	}
+
+	return &Server{Config: config, warmSearches: map[string]*warmSearch{}}
+}
+
+func GetSupportedPlatforms() []string {
+	return []string{
+		"aws",
+		"gcp",
+		"azure",
+	}
+}
+
+func (srv *Server) getSearch(platform string, tenantID string) (platformsearch.Search, error) {
+	// returns a copy of the warm search for the platform and tenant, connecting to it first if this is the first request for it
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	searchKey := platform + "/" + tenantID
+	cachedSearch, found := srv.warmSearches[searchKey]
+	if found && (srv.Config.CacheTTL <= 0 || time.Since(cachedSearch.warmedAt) < srv.Config.CacheTTL) {
+		return cachedSearch.search, nil
+	}
+
+	log.Info("connecting to ", strings.ToUpper(platform), " for API searches")
+
+	newSearch := platformsearch.Search{
+		Platform:            platform,
+		TenantID:            tenantID,
+		AzureConnConfig:     srv.Config.AzureConnConfig,
+		GCPConnConfig:       srv.Config.GCPConnConfig,
+		OrgSearchMaxWorkers: srv.Config.OrgSearchMaxWorkers,
+		GCPAssetSearch:      srv.Config.GCPAssetSearch,
+		MatchPrivateIPs:     srv.Config.MatchPrivateIPs,
+		Snapshot:            srv.Config.Snapshot,
+	}
+
+	err := newSearch.Connect()
+	if err != nil {
+		return newSearch, err
+	}
+
+	srv.warmSearches[searchKey] = &warmSearch{search: newSearch, warmedAt: time.Now()}
+
+	return newSearch, nil
+}
+
+func (srv *Server) NormalizeLookupRequest(lookupReq LookupRequest) (LookupRequest, error) {
+	// fills in the server's defaults for anything the request didn't specify, then checks that the request can be searched
+	if lookupReq.Platform == "" {
+		lookupReq.Platform = srv.Config.DefaultPlatform
+	}
+	if lookupReq.CloudSvc == "" {
+		lookupReq.CloudSvc = "all"
+	}
+	lookupReq.Platform = strings.ToLower(lookupReq.Platform)
+	if lookupReq.TenantID == "" {
+		lookupReq.TenantID = utils.GetPlatformTenantID(srv.Config.DefaultTenantIDs, lookupReq.Platform)
+	}
+
+	if !slices.Contains(GetSupportedPlatforms(), lookupReq.Platform) {
+		return lookupReq, InvalidRequestError{fmt.Errorf("'%s' is not a supported platform", lookupReq.Platform)}
+	} else if lookupReq.Platform != "aws" && lookupReq.TenantID == "" && srv.Config.Snapshot == nil {
+		return lookupReq, InvalidRequestError{fmt.Errorf("tenant ID is required for searching %s", strings.ToUpper(lookupReq.Platform))}
+	}
+
+	if len(lookupReq.IPAddrs) > srv.Config.MaxBatchSize {
+		return lookupReq, InvalidRequestError{fmt.Errorf("a maximum of %d IP addresses can be looked up at once", srv.Config.MaxBatchSize)}
+	}
+
+	return lookupReq, nil
+}
+
+func (srv *Server) LookupEach(ctx context.Context, lookupReq LookupRequest, resultHandler func(platformsearch.BulkSearchResult) error) error {
+	// searches for each IP using the shared connection and inventory cache for the request's platform, passing each result to the handler as soon as it's searched
+	// the lookup stops early if the handler returns an error or the request's context is done, since nobody is waiting on the rest of the results
+	lookupReq, err := srv.NormalizeLookupRequest(lookupReq)
+	if err != nil {
+		return err
+	}
+	if len(lookupReq.IPAddrs) == 0 {
+		return InvalidRequestError{errors.New("at least one IP address is required")}
+	}
+
+	search, err := srv.getSearch(lookupReq.Platform, lookupReq.TenantID)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// same rules as the CLI: fuzzing is only useful when searching every service, and isn't supported everywhere
+	doIPFuzzing := srv.Config.IPFuzzing && lookupReq.CloudSvc == "all" && lookupReq.Platform != "azure"
+	doAdvIPFuzzing := srv.Config.AdvIPFuzzing && lookupReq.CloudSvc == "all" && lookupReq.Platform == "aws"
+	doOrgSearch := srv.Config.OrgSearch && lookupReq.Platform != "azure"
+
+	return search.StartBulkSearch(
+		lookupReq.IPAddrs,
+		func(result platformsearch.BulkSearchResult) error {
+			err := resultHandler(result)
+			if err != nil {
+				return err
+			}
+
+			return ctx.Err()
+		},
+		lookupReq.CloudSvc,
+		doIPFuzzing,
+		doAdvIPFuzzing,
+		doOrgSearch,
+		srv.Config.OrgSearchXaccountRoleARN,
+		srv.Config.OrgSearchRoleName,
+		srv.Config.OrgSearchOrgUnitID,
+		srv.Config.NetworkMapping,
+	)
+}
+
+func (srv *Server) Lookup(ctx context.Context, lookupReq LookupRequest) ([]platformsearch.BulkSearchResult, error) {
+	var results []platformsearch.BulkSearchResult
+
+	err := srv.LookupEach(ctx, lookupReq, func(result platformsearch.BulkSearchResult) error {
+		results = append(results, result)
+		return nil
+	})
+
+	return results, err
+}
+
+func (srv *Server) Inventory(lookupReq LookupRequest) ([]generalResource.Resource, error) {
+	// lists every resource with a public IP on the request's platform; any IPs in the request are ignored
+	lookupReq.IPAddrs = nil
+
+	lookupReq, err := srv.NormalizeLookupRequest(lookupReq)
+	if err != nil {
+		return nil, err
+	}
+
+	search, err := srv.getSearch(lookupReq.Platform, lookupReq.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return search.StartInventory(
+		lookupReq.CloudSvc,
+		srv.Config.OrgSearch && lookupReq.Platform != "azure",
+		srv.Config.OrgSearchXaccountRoleARN,
+		srv.Config.OrgSearchRoleName,
+		srv.Config.OrgSearchOrgUnitID,
+	)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		log.Error("error when writing API response: ", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	writeJSON(w, statusCode, ErrorResponse{Error: err.Error()})
+}
+
+func getErrorStatusCode(err error) int {
+	var reqErr InvalidRequestError
+	if errors.As(err, &reqErr) {
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
+
+func ParseBatchLookupBody(body []byte) (LookupRequest, error) {
+	// accepts a JSON lookup request, or a plain IP list in any format supported by --input
+	var lookupReq LookupRequest
+
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		err := json.Unmarshal(body, &lookupReq)
+
+		return lookupReq, err
+	}
+
+	ipAddrs, err := iplist.Parse(body, "auto")
+	lookupReq.IPAddrs = ipAddrs
+
+	return lookupReq, err
+}
+
+func (srv *Server) handleLookup(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodGet:
+		lookupReq := LookupRequest{
+			IPAddrs:  []string{queryParams.Get("ip")},
+			Platform: queryParams.Get("platform"),
+			TenantID: queryParams.Get("tenant_id"),
+			CloudSvc: queryParams.Get("svc"),
+		}
+		if lookupReq.IPAddrs[0] == "" {
+			writeError(w, http.StatusBadRequest, errors.New("the ip query parameter is required"))
+			return
+		}
+
+		results, err := srv.Lookup(r.Context(), lookupReq)
+		if err != nil {
+			writeError(w, getErrorStatusCode(err), err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, results[0])
+	case http.MethodPost:
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+		if err != nil {
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+
+		lookupReq, err := ParseBatchLookupBody(body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		// query params can be used to set the platform etc. for plain IP lists, but don't override the body
+		if lookupReq.Platform == "" {
+			lookupReq.Platform = queryParams.Get("platform")
+		}
+		if lookupReq.TenantID == "" {
+			lookupReq.TenantID = queryParams.Get("tenant_id")
+		}
+		if lookupReq.CloudSvc == "" {
+			lookupReq.CloudSvc = queryParams.Get("svc")
+		}
+
+		results, err := srv.Lookup(r.Context(), lookupReq)
+		if err != nil {
+			writeError(w, getErrorStatusCode(err), err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, BatchLookupResponse{Results: results})
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s is not supported", r.Method))
+	}
+}
+
+func (srv *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"Status": "ok"})
+}
+
+func (srv *Server) requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if srv.Config.APIToken != "" {
+			reqToken, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found || subtle.ConstantTimeCompare([]byte(reqToken), []byte(srv.Config.APIToken)) != 1 {
+				writeError(w, http.StatusUnauthorized, errors.New("a valid API token is required"))
+				return
+			}
+		}
+
+		log.Info("API request: ", r.Method, " ", r.URL.Path, " from ", r.RemoteAddr)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (srv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/v1/lookup", srv.requireAuth(http.HandlerFunc(srv.handleLookup)))
+	mux.HandleFunc("/healthz", srv.handleHealth)
+
+	return mux
+}
+
+func (srv *Server) ListenAndServe(ctx context.Context, listenAddr string) error {
+	// serves until the context is cancelled, then gives in-flight lookups a chance to finish
+	httpServer := &http.Server{
+		Addr:              listenAddr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErrs := make(chan error, 1)
+	go func() {
+		serveErrs <- httpServer.ListenAndServe()
+	}()
+
+	log.Info("API server listening on ", listenAddr)
+
+	select {
+	case err := <-serveErrs:
+		return err
+	case <-ctx.Done():
+		log.Info("shutting down API server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		return httpServer.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,298 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	platformsearch "github.com/magneticstain/ip-2-cloudresource/search"
+	"github.com/magneticstain/ip-2-cloudresource/server"
+	"github.com/magneticstain/ip-2-cloudresource/snapshot"
+)
+
+func serverFactory(apiToken string) *server.Server {
+	// snapshot-backed, so that lookups can be tested without cloud credentials
+	snap := snapshot.New("v0.0.0-test")
+
+	snap.AddPlatformInventory(snapshot.PlatformInventory{
+		Platform: "aws",
+		Resources: []generalResource.Resource{
+			{RID: "arn:aws:ec2:us-east-1:123456789012:instance/i-1234567890abcdef0", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.61"}},
+			{RID: "arn:aws:cloudfront::123456789012:distribution/E1234567890ABC", AccountID: "123456789012", CloudSvc: "cloudfront", PublicIPv6Addrs: []string{"2600:9000:24eb:dc00:1:3b80:4f00:21"}},
+		},
+	})
+
+	return server.New(server.ServerConfig{APIToken: apiToken, MaxBatchSize: 3, Snapshot: &snap})
+}
+
+func TestParseBatchLookupBody(t *testing.T) {
+	var tests = []struct {
+		body             string
+		expectedPlatform string
+		expectedIPAddrs  []string
+	}{
+		{`{"ips": ["18.161.22.61", "1.1.1.1"], "platform": "aws"}`, "aws", []string{"18.161.22.61", "1.1.1.1"}},
+		{"18.161.22.61\n1.1.1.1\n", "", []string{"18.161.22.61", "1.1.1.1"}},
+		{`["18.161.22.61"]`, "", []string{"18.161.22.61"}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.body, func(t *testing.T) {
+			lookupReq, err := server.ParseBatchLookupBody([]byte(td.body))
+			if err != nil {
+				t.Fatalf("Parsing batch lookup body failed; received error: %s", err)
+			}
+
+			if lookupReq.Platform != td.expectedPlatform || strings.Join(lookupReq.IPAddrs, ",") != strings.Join(td.expectedIPAddrs, ",") {
+				t.Errorf("Parsing batch lookup body failed; expected %s %v, received %s %v", td.expectedPlatform, td.expectedIPAddrs, lookupReq.Platform, lookupReq.IPAddrs)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	var tests = []struct {
+		name        string
+		lookupReq   server.LookupRequest
+		expectedErr bool
+	}{
+		{"SingleIP", server.LookupRequest{IPAddrs: []string{"18.161.22.61"}}, false},
+		{"MultipleIPs", server.LookupRequest{IPAddrs: []string{"18.161.22.61", "2600:9000:24eb:dc00:1:3b80:4f00:21"}, Platform: "AWS"}, false},
+		{"NoIPs", server.LookupRequest{}, true},
+		{"OverMaxBatchSize", server.LookupRequest{IPAddrs: []string{"1.1.1.1", "1.1.1.2", "1.1.1.3", "1.1.1.4"}}, true},
+		{"UnsupportedPlatform", server.LookupRequest{IPAddrs: []string{"18.161.22.61"}, Platform: "oci"}, true},
+		{"PlatformMissingFromSnapshot", server.LookupRequest{IPAddrs: []string{"18.161.22.61"}, Platform: "gcp"}, true},
+	}
+
+	apiServer := serverFactory("")
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			results, err := apiServer.Lookup(context.Background(), td.lookupReq)
+			if (err != nil) != td.expectedErr {
+				t.Fatalf("Lookup failed; expected error: %t, received: %v", td.expectedErr, err)
+			}
+
+			if !td.expectedErr && len(results) != len(td.lookupReq.IPAddrs) {
+				t.Errorf("Lookup failed; expected %d results, received %d", len(td.lookupReq.IPAddrs), len(results))
+			}
+		})
+	}
+}
+
+func TestNormalizeLookupRequest_DefaultTenantIDs(t *testing.T) {
+	apiServer := server.New(server.ServerConfig{
+		DefaultPlatform:  "gcp",
+		DefaultTenantIDs: map[string]string{"gcp": "my-project", "": "default-tenant"},
+		MaxBatchSize:     3,
+	})
+
+	var tests = []struct {
+		name             string
+		lookupReq        server.LookupRequest
+		expectedTenantID string
+	}{
+		{"DefaultPlatform", server.LookupRequest{}, "my-project"},
+		{"OtherPlatform", server.LookupRequest{Platform: "Azure"}, "default-tenant"},
+		{"RequestTenantID", server.LookupRequest{TenantID: "other-project"}, "other-project"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			lookupReq, err := apiServer.NormalizeLookupRequest(td.lookupReq)
+			if err != nil {
+				t.Fatalf("Normalizing lookup request failed; received error: %s", err)
+			}
+
+			if lookupReq.TenantID != td.expectedTenantID {
+				t.Errorf("Normalizing lookup request failed; expected tenant ID %s, received %s", td.expectedTenantID, lookupReq.TenantID)
+			}
+		})
+	}
+}
+
+func TestLookupEach_StopsEarly(t *testing.T) {
+	lookupReq := server.LookupRequest{IPAddrs: []string{"18.161.22.61", "2600:9000:24eb:dc00:1:3b80:4f00:21", "1.1.1.1"}}
+	handlerErr := errors.New("client went away")
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var tests = []struct {
+		name            string
+		ctx             context.Context
+		handlerErr      error
+		expectedErr     error
+		expectedResults int
+	}{
+		{"HandlerError", context.Background(), handlerErr, handlerErr, 1},
+		{"ContextCanceled", canceledCtx, nil, context.Canceled, 0},
+	}
+
+	apiServer := serverFactory("")
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			var results []platformsearch.BulkSearchResult
+
+			err := apiServer.LookupEach(td.ctx, lookupReq, func(result platformsearch.BulkSearchResult) error {
+				results = append(results, result)
+				return td.handlerErr
+			})
+			if !errors.Is(err, td.expectedErr) {
+				t.Errorf("Lookup failed to stop early; expected error %v, received %v", td.expectedErr, err)
+			}
+
+			if len(results) != td.expectedResults {
+				t.Errorf("Lookup failed to stop early; expected %d results, received %d", td.expectedResults, len(results))
+			}
+		})
+	}
+}
+
+func TestHandler_GetLookup(t *testing.T) {
+	var tests = []struct {
+		query, expectedRID string
+		expectedStatusCode int
+	}{
+		{"ip=18.161.22.61", "arn:aws:ec2:us-east-1:123456789012:instance/i-1234567890abcdef0", http.StatusOK},
+		{"ip=18.161.22.61&svc=cloudfront", "", http.StatusOK},
+		{"ip=1.1.1.1&platform=aws", "", http.StatusOK},
+		{"platform=aws", "", http.StatusBadRequest},
+		{"ip=18.161.22.61&platform=oci", "", http.StatusBadRequest},
+	}
+
+	testServer := httptest.NewServer(serverFactory("").Handler())
+	defer testServer.Close()
+
+	for _, td := range tests {
+		t.Run(td.query, func(t *testing.T) {
+			resp, err := http.Get(testServer.URL + "/v1/lookup?" + td.query)
+			if err != nil {
+				t.Fatalf("GET lookup failed; received error: %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != td.expectedStatusCode {
+				t.Fatalf("GET lookup failed; expected status %d, received %d", td.expectedStatusCode, resp.StatusCode)
+			}
+
+			if resp.StatusCode == http.StatusOK {
+				var result platformsearch.BulkSearchResult
+
+				err = json.NewDecoder(resp.Body).Decode(&result)
+				if err != nil {
+					t.Fatalf("GET lookup failed; could not decode response: %s", err)
+				}
+
+				if result.Found != (td.expectedRID != "") || result.Resource.RID != td.expectedRID {
+					t.Errorf("GET lookup failed; expected %s, received %s", td.expectedRID, result.Resource.RID)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_PostLookup(t *testing.T) {
+	var tests = []struct {
+		name, contentType, body string
+		expectedFound           []bool
+		expectedStatusCode      int
+	}{
+		{"JSON", "application/json", `{"ips": ["18.161.22.61", "1.1.1.1"]}`, []bool{true, false}, http.StatusOK},
+		{"PlainText", "text/plain", "2600:9000:24eb:dc00:1:3b80:4f00:21\n", []bool{true}, http.StatusOK},
+		{"InvalidJSON", "application/json", `{"ips": [`, nil, http.StatusBadRequest},
+	}
+
+	testServer := httptest.NewServer(serverFactory("").Handler())
+	defer testServer.Close()
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			resp, err := http.Post(testServer.URL+"/v1/lookup", td.contentType, strings.NewReader(td.body))
+			if err != nil {
+				t.Fatalf("POST lookup failed; received error: %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != td.expectedStatusCode {
+				t.Fatalf("POST lookup failed; expected status %d, received %d", td.expectedStatusCode, resp.StatusCode)
+			}
+
+			if resp.StatusCode == http.StatusOK {
+				var batchResp server.BatchLookupResponse
+
+				err = json.NewDecoder(resp.Body).Decode(&batchResp)
+				if err != nil {
+					t.Fatalf("POST lookup failed; could not decode response: %s", err)
+				}
+
+				if len(batchResp.Results) != len(td.expectedFound) {
+					t.Fatalf("POST lookup failed; expected %d results, received %d", len(td.expectedFound), len(batchResp.Results))
+				}
+
+				for i, result := range batchResp.Results {
+					if result.Found != td.expectedFound[i] {
+						t.Errorf("POST lookup failed; expected %s found: %t, received %t", result.IPAddr, td.expectedFound[i], result.Found)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_Auth(t *testing.T) {
+	var tests = []struct {
+		authHeader         string
+		expectedStatusCode int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong-token", http.StatusUnauthorized},
+		{"test-token", http.StatusUnauthorized},
+		{"Bearer test-token", http.StatusOK},
+	}
+
+	testServer := httptest.NewServer(serverFactory("test-token").Handler())
+	defer testServer.Close()
+
+	for _, td := range tests {
+		t.Run(td.authHeader, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, testServer.URL+"/v1/lookup?ip=18.161.22.61", nil)
+			if td.authHeader != "" {
+				req.Header.Set("Authorization", td.authHeader)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Authenticated lookup failed; received error: %s", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != td.expectedStatusCode {
+				t.Errorf("Authenticated lookup failed; expected status %d, received %d", td.expectedStatusCode, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHandler_UnsupportedMethod(t *testing.T) {
+	testServer := httptest.NewServer(serverFactory("").Handler())
+	defer testServer.Close()
+
+	req, _ := http.NewRequest(http.MethodDelete, testServer.URL+"/v1/lookup", nil)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unsupported method check failed; received error: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Unsupported method check failed; expected status %d, received %d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+}
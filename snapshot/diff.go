@@ -0,0 +1,228 @@
+package snapshot
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+// where a public IP was found within a snapshot
+type IPOwner struct {
+	Platform, AccountID, CloudSvc, RID, Name string
+}
+
+type IPChange struct {
+	IPAddr string
+	// Old is unset for IPs that appeared, and New is unset for IPs that disappeared
+	Old, New IPOwner
+}
+
+type Diff struct {
+	OldCreatedAt, NewCreatedAt   string
+	Appeared, Disappeared, Moved []IPChange
+	// parts of either snapshot that weren't compared since the other snapshot didn't cover them
+	SkippedScopes []string
+}
+
+func newIPOwner(platform string, resource generalResource.Resource) IPOwner {
+	return IPOwner{
+		Platform:  platform,
+		AccountID: resource.AccountID,
+		CloudSvc:  resource.CloudSvc,
+		RID:       resource.RID,
+		Name:      resource.Name,
+	}
+}
+
+func (ipOwner IPOwner) String() string {
+	return fmt.Sprintf("%s %s [ %s ] in account %s", ipOwner.Platform, ipOwner.CloudSvc, ipOwner.RID, ipOwner.AccountID)
+}
+
+func (snap Snapshot) GetIPOwners() map[string]IPOwner {
+	// maps each public IP in the snapshot to the resource it belongs to
+	return snap.getScopedIPOwners(nil)
+}
+
+func (snap Snapshot) getScopedIPOwners(sharedScope map[string][]string) map[string]IPOwner {
+	// same as GetIPOwners(), but only includes the services in the shared scope for each platform; a nil scope includes everything
+	ipOwners := map[string]IPOwner{}
+
+	for _, platformInventory := range snap.Platforms {
+		for _, resource := range platformInventory.Resources {
+			if sharedScope != nil && !slices.Contains(sharedScope[platformInventory.Platform], resource.CloudSvc) {
+				continue
+			}
+
+			ipOwner := newIPOwner(platformInventory.Platform, resource)
+
+			for _, ipAddr := range resource.PublicIPv4Addrs {
+				ipOwners[ipAddr] = ipOwner
+			}
+			for _, ipAddr := range resource.PublicIPv6Addrs {
+				ipOwners[ipAddr] = ipOwner
+			}
+		}
+	}
+
+	return ipOwners
+}
+
+func getPlatformInventory(snap Snapshot, platform string) (PlatformInventory, bool) {
+	for _, platformInventory := range snap.Platforms {
+		if platformInventory.Platform == platform {
+			return platformInventory, true
+		}
+	}
+
+	return PlatformInventory{}, false
+}
+
+func GetSharedScope(oldSnap Snapshot, newSnap Snapshot) (map[string][]string, []string) {
+	// IPs outside of what both snapshots inventoried would otherwise show up as appeared or disappeared, so we work out which services
+	// of which platforms can be compared, along with a description of everything that can't
+	sharedScope := map[string][]string{}
+	var skippedScopes []string
+
+	for _, oldPlatformInventory := range oldSnap.Platforms {
+		platform := oldPlatformInventory.Platform
+
+		newPlatformInventory, found := getPlatformInventory(newSnap, platform)
+		switch {
+		case !found:
+			skippedScopes = append(skippedScopes, fmt.Sprintf("%s is only in the old snapshot", platform))
+			continue
+		case oldPlatformInventory.TenantID != newPlatformInventory.TenantID:
+			skippedScopes = append(skippedScopes, fmt.Sprintf("%s was inventoried for different tenants ( %q -> %q )", platform, oldPlatformInventory.TenantID, newPlatformInventory.TenantID))
+			continue
+		case oldPlatformInventory.OrgSearch != newPlatformInventory.OrgSearch:
+			skippedScopes = append(skippedScopes, fmt.Sprintf("%s was only inventoried org-wide in one of the snapshots", platform))
+			continue
+		}
+
+		sharedSvcs := []string{}
+		for _, cloudSvc := range oldPlatformInventory.CloudSvcs {
+			if slices.Contains(newPlatformInventory.CloudSvcs, cloudSvc) {
+				sharedSvcs = append(sharedSvcs, cloudSvc)
+			} else {
+				skippedScopes = append(skippedScopes, fmt.Sprintf("%s %s is only in the old snapshot", platform, cloudSvc))
+			}
+		}
+		for _, cloudSvc := range newPlatformInventory.CloudSvcs {
+			if !slices.Contains(oldPlatformInventory.CloudSvcs, cloudSvc) {
+				skippedScopes = append(skippedScopes, fmt.Sprintf("%s %s is only in the new snapshot", platform, cloudSvc))
+			}
+		}
+
+		sharedScope[platform] = sharedSvcs
+	}
+
+	for _, newPlatformInventory := range newSnap.Platforms {
+		if _, found := getPlatformInventory(oldSnap, newPlatformInventory.Platform); !found {
+			skippedScopes = append(skippedScopes, fmt.Sprintf("%s is only in the new snapshot", newPlatformInventory.Platform))
+		}
+	}
+
+	return sharedScope, skippedScopes
+}
+
+func sortIPChanges(ipChanges []IPChange) {
+	sort.Slice(ipChanges, func(i, j int) bool {
+		return ipChanges[i].IPAddr < ipChanges[j].IPAddr
+	})
+}
+
+func Compare(oldSnap Snapshot, newSnap Snapshot) Diff {
+	diff := Diff{
+		OldCreatedAt: oldSnap.CreatedAt.Format(time.RFC3339),
+		NewCreatedAt: newSnap.CreatedAt.Format(time.RFC3339),
+		// initialized so that JSON output always has arrays, even if nothing changed
+		Appeared:    []IPChange{},
+		Disappeared: []IPChange{},
+		Moved:       []IPChange{},
+	}
+
+	var sharedScope map[string][]string
+	sharedScope, diff.SkippedScopes = GetSharedScope(oldSnap, newSnap)
+
+	oldIPOwners := oldSnap.getScopedIPOwners(sharedScope)
+	newIPOwners := newSnap.getScopedIPOwners(sharedScope)
+
+	for ipAddr, newIPOwner := range newIPOwners {
+		oldIPOwner, found := oldIPOwners[ipAddr]
+
+		switch {
+		case !found:
+			diff.Appeared = append(diff.Appeared, IPChange{IPAddr: ipAddr, New: newIPOwner})
+		case oldIPOwner.Platform != newIPOwner.Platform || oldIPOwner.AccountID != newIPOwner.AccountID || oldIPOwner.RID != newIPOwner.RID:
+			diff.Moved = append(diff.Moved, IPChange{IPAddr: ipAddr, Old: oldIPOwner, New: newIPOwner})
+		}
+	}
+
+	for ipAddr, oldIPOwner := range oldIPOwners {
+		if _, found := newIPOwners[ipAddr]; !found {
+			// e.g. an EIP released back to the pool; anything still pointing at it (like a DNS record) is now dangling
+			diff.Disappeared = append(diff.Disappeared, IPChange{IPAddr: ipAddr, Old: oldIPOwner})
+		}
+	}
+
+	// map iteration order is random, so we sort the changes to keep output stable between runs
+	sortIPChanges(diff.Appeared)
+	sortIPChanges(diff.Disappeared)
+	sortIPChanges(diff.Moved)
+
+	return diff
+}
+
+func (diff Diff) HasChanges() bool {
+	return len(diff.Appeared) > 0 || len(diff.Disappeared) > 0 || len(diff.Moved) > 0
+}
+
+func (diff Diff) WriteText(writer io.Writer) error {
+	_, err := fmt.Fprintf(writer, "comparing snapshot from %s to snapshot from %s\n", diff.OldCreatedAt, diff.NewCreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, skippedScope := range diff.SkippedScopes {
+		_, err = fmt.Fprintf(writer, "not compared: %s\n", skippedScope)
+		if err != nil {
+			return err
+		}
+	}
+
+	sections := []struct {
+		title     string
+		ipChanges []IPChange
+		fmtChange func(IPChange) string
+	}{
+		{"appeared", diff.Appeared, func(ipChange IPChange) string {
+			return fmt.Sprintf("+ %s -> %s", ipChange.IPAddr, ipChange.New)
+		}},
+		{"disappeared", diff.Disappeared, func(ipChange IPChange) string {
+			return fmt.Sprintf("- %s (was %s)", ipChange.IPAddr, ipChange.Old)
+		}},
+		{"moved", diff.Moved, func(ipChange IPChange) string {
+			return fmt.Sprintf("~ %s: %s -> %s", ipChange.IPAddr, ipChange.Old, ipChange.New)
+		}},
+	}
+
+	for _, section := range sections {
+		_, err = fmt.Fprintf(writer, "\n%d public IP(s) %s\n", len(section.ipChanges), section.title)
+		if err != nil {
+			return err
+		}
+
+		for _, ipChange := range section.ipChanges {
+			_, err = fmt.Fprintln(writer, section.fmtChange(ipChange))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
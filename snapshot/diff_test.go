@@ -0,0 +1,145 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/snapshot"
+)
+
+func diffSnapshotFactory(resources []generalResource.Resource) snapshot.Snapshot {
+	snap := snapshot.New("v0.0.0-test")
+	snap.AddPlatformInventory(snapshot.PlatformInventory{Platform: "aws", CloudSvcs: []string{"ec2"}, Resources: resources})
+
+	return snap
+}
+
+func TestCompare(t *testing.T) {
+	oldSnap := diffSnapshotFactory([]generalResource.Resource{
+		{RID: "i-aaaa", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.61", "1.1.1.1"}},
+		{RID: "i-bbbb", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.62"}},
+		{RID: "i-cccc", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv6Addrs: []string{"2600:9000:24eb:dc00:1:3b80:4f00:21"}},
+	})
+	newSnap := diffSnapshotFactory([]generalResource.Resource{
+		{RID: "i-aaaa", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.61"}},
+		{RID: "i-dddd", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.62", "18.161.22.63"}},
+		{RID: "i-cccc", AccountID: "210987654321", CloudSvc: "ec2", PublicIPv6Addrs: []string{"2600:9000:24eb:dc00:1:3b80:4f00:21"}},
+	})
+
+	snapshotDiff := snapshot.Compare(oldSnap, newSnap)
+
+	var tests = []struct {
+		name            string
+		ipChanges       []snapshot.IPChange
+		expectedIPAddrs []string
+	}{
+		{"appeared", snapshotDiff.Appeared, []string{"18.161.22.63"}},
+		{"disappeared", snapshotDiff.Disappeared, []string{"1.1.1.1"}},
+		{"moved", snapshotDiff.Moved, []string{"18.161.22.62", "2600:9000:24eb:dc00:1:3b80:4f00:21"}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			var ipAddrs []string
+			for _, ipChange := range td.ipChanges {
+				ipAddrs = append(ipAddrs, ipChange.IPAddr)
+			}
+
+			if strings.Join(ipAddrs, ",") != strings.Join(td.expectedIPAddrs, ",") {
+				t.Errorf("Comparing snapshots failed; expected %s IPs %s, received %s", td.name, td.expectedIPAddrs, ipAddrs)
+			}
+		})
+	}
+
+	if snapshotDiff.Moved[1].Old.AccountID != "123456789012" || snapshotDiff.Moved[1].New.AccountID != "210987654321" {
+		t.Errorf("Comparing snapshots failed; expected IP to move between accounts, received %+v", snapshotDiff.Moved[1])
+	}
+}
+
+func TestCompare_DifferentScopes(t *testing.T) {
+	oldSnap := snapshot.New("v0.0.0-test")
+	oldSnap.AddPlatformInventory(snapshot.PlatformInventory{Platform: "aws", CloudSvcs: []string{"ec2", "elbv2"}, Resources: []generalResource.Resource{
+		{RID: "i-aaaa", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.61"}},
+		{RID: "lb-aaaa", AccountID: "123456789012", CloudSvc: "elbv2", PublicIPv4Addrs: []string{"18.161.22.62"}},
+	}})
+	oldSnap.AddPlatformInventory(snapshot.PlatformInventory{Platform: "gcp", CloudSvcs: []string{"compute"}, Resources: []generalResource.Resource{
+		{RID: "vm-aaaa", AccountID: "project-a", CloudSvc: "compute", PublicIPv4Addrs: []string{"34.0.0.1"}},
+	}})
+
+	newSnap := snapshot.New("v0.0.0-test")
+	newSnap.AddPlatformInventory(snapshot.PlatformInventory{Platform: "aws", CloudSvcs: []string{"ec2", "cloudfront"}, Resources: []generalResource.Resource{
+		{RID: "i-aaaa", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.61", "18.161.22.63"}},
+		{RID: "cf-aaaa", AccountID: "123456789012", CloudSvc: "cloudfront", PublicIPv4Addrs: []string{"18.161.22.64"}},
+	}})
+	newSnap.AddPlatformInventory(snapshot.PlatformInventory{Platform: "azure", TenantID: "tenant-a", CloudSvcs: []string{"virtual_machines"}, Resources: []generalResource.Resource{
+		{RID: "vm-bbbb", AccountID: "sub-a", CloudSvc: "virtual_machines", PublicIPv4Addrs: []string{"20.0.0.1"}},
+	}})
+
+	snapshotDiff := snapshot.Compare(oldSnap, newSnap)
+
+	// only AWS EC2 is in both snapshots, so the IPs of every other service shouldn't be reported as appeared or disappeared
+	if len(snapshotDiff.Appeared) != 1 || snapshotDiff.Appeared[0].IPAddr != "18.161.22.63" || len(snapshotDiff.Disappeared) != 0 {
+		t.Errorf("Comparing snapshots with different scopes failed; expected only 18.161.22.63 to appear, received %+v", snapshotDiff)
+	}
+
+	expectedSkippedScopes := []string{
+		"aws elbv2 is only in the old snapshot",
+		"aws cloudfront is only in the new snapshot",
+		"gcp is only in the old snapshot",
+		"azure is only in the new snapshot",
+	}
+	if strings.Join(snapshotDiff.SkippedScopes, ",") != strings.Join(expectedSkippedScopes, ",") {
+		t.Errorf("Comparing snapshots with different scopes failed; expected skipped scopes %s, received %s", expectedSkippedScopes, snapshotDiff.SkippedScopes)
+	}
+}
+
+func TestCompare_DifferentTenants(t *testing.T) {
+	oldSnap := snapshot.New("v0.0.0-test")
+	oldSnap.AddPlatformInventory(snapshot.PlatformInventory{Platform: "azure", TenantID: "tenant-a", CloudSvcs: []string{"virtual_machines"}, Resources: []generalResource.Resource{
+		{RID: "vm-aaaa", AccountID: "sub-a", CloudSvc: "virtual_machines", PublicIPv4Addrs: []string{"20.0.0.1"}},
+	}})
+
+	newSnap := snapshot.New("v0.0.0-test")
+	newSnap.AddPlatformInventory(snapshot.PlatformInventory{Platform: "azure", TenantID: "tenant-b", CloudSvcs: []string{"virtual_machines"}, Resources: []generalResource.Resource{
+		{RID: "vm-bbbb", AccountID: "sub-b", CloudSvc: "virtual_machines", PublicIPv4Addrs: []string{"20.0.0.2"}},
+	}})
+
+	snapshotDiff := snapshot.Compare(oldSnap, newSnap)
+
+	if snapshotDiff.HasChanges() || len(snapshotDiff.SkippedScopes) != 1 {
+		t.Errorf("Comparing snapshots of different tenants failed; expected no changes and the platform to be skipped, received %+v", snapshotDiff)
+	}
+}
+
+func TestCompare_NoChanges(t *testing.T) {
+	snap := snapshotFactory()
+
+	newSnap := snapshotFactory()
+	newSnap.CreatedAt = newSnap.CreatedAt.Add(time.Hour)
+
+	snapshotDiff := snapshot.Compare(snap, newSnap)
+	if snapshotDiff.HasChanges() {
+		t.Errorf("Comparing identical snapshots failed; expected no changes, received %+v", snapshotDiff)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	oldSnap := diffSnapshotFactory(nil)
+	newSnap := diffSnapshotFactory([]generalResource.Resource{
+		{RID: "i-aaaa", AccountID: "123456789012", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.61"}},
+	})
+
+	var output bytes.Buffer
+	err := snapshot.Compare(oldSnap, newSnap).WriteText(&output)
+	if err != nil {
+		t.Fatalf("Writing snapshot diff failed; received error: %s", err)
+	}
+
+	expectedLine := "+ 18.161.22.61 -> aws ec2 [ i-aaaa ] in account 123456789012"
+	if !strings.Contains(output.String(), expectedLine) {
+		t.Errorf("Writing snapshot diff failed; expected output to contain %s, received %s", expectedLine, output.String())
+	}
+}
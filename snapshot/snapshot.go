@@ -0,0 +1,134 @@
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+// bump this whenever a change to the snapshot format would break reading older snapshots
+const SchemaVersion = 1
+
+type PlatformInventory struct {
+	Platform, TenantID string
+	CloudSvcs          []string
+	OrgSearch          bool
+	Resources          []generalResource.Resource
+}
+
+type Snapshot struct {
+	SchemaVersion int
+	// version of IP2CR that created the snapshot
+	AppVersion string
+	CreatedAt  time.Time
+	Platforms  []PlatformInventory
+}
+
+func New(appVersion string) Snapshot {
+	return Snapshot{
+		SchemaVersion: SchemaVersion,
+		AppVersion:    appVersion,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+func (snap *Snapshot) AddPlatformInventory(platformInventory PlatformInventory) {
+	snap.Platforms = append(snap.Platforms, platformInventory)
+}
+
+func (snap Snapshot) GetPlatforms() []string {
+	var platforms []string
+
+	for _, platformInventory := range snap.Platforms {
+		if !slices.Contains(platforms, platformInventory.Platform) {
+			platforms = append(platforms, platformInventory.Platform)
+		}
+	}
+
+	return platforms
+}
+
+func (snap Snapshot) GetResources(platform string) []generalResource.Resource {
+	var resources []generalResource.Resource
+
+	for _, platformInventory := range snap.Platforms {
+		if platformInventory.Platform == platform {
+			resources = append(resources, platformInventory.Resources...)
+		}
+	}
+
+	return resources
+}
+
+func (snap Snapshot) GetAge(now time.Time) time.Duration {
+	return now.Sub(snap.CreatedAt)
+}
+
+func (snap Snapshot) IsStale(now time.Time, maxAge time.Duration) bool {
+	// a max age of zero or less disables the staleness check
+	return maxAge > 0 && snap.GetAge(now) > maxAge
+}
+
+func Write(snapshotPath string, snap Snapshot) error {
+	// the snapshot is written to a temp file first so that a failed write never clobbers the previous snapshot
+	tmpFile, err := os.CreateTemp(filepath.Dir(snapshotPath), filepath.Base(snapshotPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	gzipWriter := gzip.NewWriter(tmpFile)
+	gzipWriter.Comment = fmt.Sprintf("ip2cr snapshot v%d", snap.SchemaVersion)
+
+	err = json.NewEncoder(gzipWriter).Encode(snap)
+	if err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	err = gzipWriter.Close()
+	if err != nil {
+		tmpFile.Close()
+		return err
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), snapshotPath)
+}
+
+func Read(snapshotPath string) (Snapshot, error) {
+	var snap Snapshot
+
+	snapshotFile, err := os.Open(snapshotPath)
+	if err != nil {
+		return snap, err
+	}
+	defer snapshotFile.Close()
+
+	gzipReader, err := gzip.NewReader(snapshotFile)
+	if err != nil {
+		return snap, fmt.Errorf("%s is not a valid snapshot: %w", snapshotPath, err)
+	}
+	defer gzipReader.Close()
+
+	err = json.NewDecoder(gzipReader).Decode(&snap)
+	if err != nil {
+		return snap, fmt.Errorf("%s is not a valid snapshot: %w", snapshotPath, err)
+	}
+
+	if snap.SchemaVersion < 1 || snap.SchemaVersion > SchemaVersion {
+		return snap, fmt.Errorf("snapshot schema version %d is not supported by this version of IP2CR (supported: 1-%d)", snap.SchemaVersion, SchemaVersion)
+	}
+
+	return snap, nil
+}
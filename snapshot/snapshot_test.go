@@ -0,0 +1,156 @@
+package snapshot_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/snapshot"
+)
+
+func snapshotFactory() snapshot.Snapshot {
+	snap := snapshot.New("v0.0.0-test")
+
+	snap.AddPlatformInventory(snapshot.PlatformInventory{
+		Platform:  "aws",
+		CloudSvcs: []string{"ec2"},
+		Resources: []generalResource.Resource{
+			{RID: "arn:aws:ec2:us-east-1:123456789012:instance/i-1234567890abcdef0", CloudSvc: "ec2", PublicIPv4Addrs: []string{"18.161.22.61"}},
+		},
+	})
+	snap.AddPlatformInventory(snapshot.PlatformInventory{
+		Platform:  "gcp",
+		TenantID:  "my-project",
+		CloudSvcs: []string{"compute"},
+		Resources: []generalResource.Resource{
+			{RID: "projects/my-project/zones/us-central1-a/instances/web01", CloudSvc: "compute", PublicIPv6Addrs: []string{"2600:9000:24eb:dc00:1:3b80:4f00:21"}},
+		},
+	})
+
+	return snap
+}
+
+func TestNew(t *testing.T) {
+	snap := snapshot.New("v0.0.0-test")
+
+	expectedType := "Snapshot"
+	factoryType := reflect.TypeOf(snap)
+	if factoryType.Name() != expectedType {
+		t.Errorf("Snapshot factory failed; expected %s, received %s", expectedType, factoryType.Name())
+	}
+
+	if snap.SchemaVersion != snapshot.SchemaVersion || snap.CreatedAt.IsZero() {
+		t.Errorf("Snapshot factory failed; expected schema version %d and creation time, received %d and %s", snapshot.SchemaVersion, snap.SchemaVersion, snap.CreatedAt)
+	}
+}
+
+func TestGetPlatforms(t *testing.T) {
+	platforms := snapshotFactory().GetPlatforms()
+
+	if !slices.Equal(platforms, []string{"aws", "gcp"}) {
+		t.Errorf("Getting snapshot platforms failed; expected [aws gcp], received %s", platforms)
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	var tests = []struct {
+		platform         string
+		expectedResource int
+	}{
+		{"aws", 1},
+		{"gcp", 1},
+		{"azure", 0},
+	}
+
+	for _, td := range tests {
+		testName := td.platform
+
+		t.Run(testName, func(t *testing.T) {
+			resources := snapshotFactory().GetResources(td.platform)
+
+			if len(resources) != td.expectedResource {
+				t.Errorf("Getting snapshot resources failed; expected %d resources, received %d", td.expectedResource, len(resources))
+			}
+		})
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	var tests = []struct {
+		name     string
+		age      time.Duration
+		maxAge   time.Duration
+		expected bool
+	}{
+		{"fresh", time.Hour, 24 * time.Hour, false},
+		{"stale", 48 * time.Hour, 24 * time.Hour, true},
+		{"disabled", 48 * time.Hour, 0, false},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			snap := snapshotFactory()
+
+			isStale := snap.IsStale(snap.CreatedAt.Add(td.age), td.maxAge)
+			if isStale != td.expected {
+				t.Errorf("Checking snapshot staleness failed; expected %t, received %t", td.expected, isStale)
+			}
+		})
+	}
+}
+
+func TestWriteAndRead(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "ip2cr.snap")
+	snap := snapshotFactory()
+
+	err := snapshot.Write(snapshotPath, snap)
+	if err != nil {
+		t.Fatalf("Writing snapshot failed; received error: %s", err)
+	}
+
+	readSnap, err := snapshot.Read(snapshotPath)
+	if err != nil {
+		t.Fatalf("Reading snapshot failed; received error: %s", err)
+	}
+
+	if !readSnap.CreatedAt.Equal(snap.CreatedAt) || !reflect.DeepEqual(readSnap.Platforms, snap.Platforms) {
+		t.Errorf("Reading snapshot failed; expected %+v, received %+v", snap, readSnap)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	plaintextPath := filepath.Join(tmpDir, "plaintext.snap")
+	_ = os.WriteFile(plaintextPath, []byte(`{"SchemaVersion": 1}`), 0o600)
+
+	futureVersionPath := filepath.Join(tmpDir, "future.snap")
+	futureVersionFile, _ := os.Create(futureVersionPath)
+	gzipWriter := gzip.NewWriter(futureVersionFile)
+	_ = json.NewEncoder(gzipWriter).Encode(map[string]int{"SchemaVersion": snapshot.SchemaVersion + 1})
+	gzipWriter.Close()
+	futureVersionFile.Close()
+
+	var tests = []struct {
+		name, snapshotPath string
+	}{
+		{"missing", filepath.Join(tmpDir, "missing.snap")},
+		{"plaintext", plaintextPath},
+		{"future_version", futureVersionPath},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			_, err := snapshot.Read(td.snapshotPath)
+			if err == nil {
+				t.Errorf("Reading snapshot should have failed for %s, but did not", td.snapshotPath)
+			}
+		})
+	}
+}
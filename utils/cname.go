@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const maxCNAMEChainLength = 10
+const dnsQueryTimeout = 5 * time.Second
+
+func NormalizeFQDN(fqdn string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(fqdn)), ".")
+}
+
+func getNameservers(resolvConfPath string) []string {
+	// returns the address (host:port) of each nameserver, in the order they should be tried
+	var nameservers []string
+
+	resolvConf, err := os.Open(resolvConfPath)
+	if err != nil {
+		return nameservers
+	}
+	defer resolvConf.Close()
+
+	scanner := bufio.NewScanner(resolvConf)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			nameservers = append(nameservers, net.JoinHostPort(fields[1], "53"))
+		}
+	}
+
+	return nameservers
+}
+
+func newDNSQueryID() (uint16, error) {
+	// query IDs need to be unpredictable so that spoofed responses can't easily be matched to our queries
+	var idBytes [2]byte
+
+	_, err := rand.Read(idBytes[:])
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint16(idBytes[:]), nil
+}
+
+func exchangeDNSMsg(network, nameserver string, packedQuery []byte) ([]byte, error) {
+	conn, err := net.DialTimeout(network, nameserver, dnsQueryTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	err = conn.SetDeadline(time.Now().Add(dnsQueryTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	if network == "udp" {
+		_, err = conn.Write(packedQuery)
+		if err != nil {
+			return nil, err
+		}
+
+		respBuffer := make([]byte, 4096)
+		respLen, err := conn.Read(respBuffer)
+		if err != nil {
+			return nil, err
+		}
+
+		return respBuffer[:respLen], nil
+	}
+
+	// messages sent over TCP are prefixed with their length
+	tcpQuery := binary.BigEndian.AppendUint16(nil, uint16(len(packedQuery)))
+	_, err = conn.Write(append(tcpQuery, packedQuery...))
+	if err != nil {
+		return nil, err
+	}
+
+	var respLenBytes [2]byte
+	_, err = io.ReadFull(conn, respLenBytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	respBuffer := make([]byte, binary.BigEndian.Uint16(respLenBytes[:]))
+	_, err = io.ReadFull(conn, respBuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return respBuffer, nil
+}
+
+func validateDNSResponse(queryMsg, respMsg dnsmessage.Message) error {
+	// anything that isn't the answer to our question, e.g. a stray or spoofed packet, can't be trusted
+	if !respMsg.Response || respMsg.ID != queryMsg.ID {
+		return errors.New("DNS response doesn't match the query ID")
+	}
+
+	if len(respMsg.Questions) != 1 {
+		return errors.New("DNS response doesn't include the queried question")
+	}
+
+	query, resp := queryMsg.Questions[0], respMsg.Questions[0]
+	if !strings.EqualFold(resp.Name.String(), query.Name.String()) || resp.Type != query.Type || resp.Class != query.Class {
+		return errors.New("DNS response is for a different question: " + resp.GoString())
+	}
+
+	return nil
+}
+
+func queryCNAMERecord(fqdn, nameserver string) (string, error) {
+	// queries the CNAME record for just this name so we can see each hop of a chain; the stdlib resolver only returns the final name
+	var cnameTarget string
+
+	queryName, err := dnsmessage.NewName(NormalizeFQDN(fqdn) + ".")
+	if err != nil {
+		return cnameTarget, err
+	}
+
+	queryID, err := newDNSQueryID()
+	if err != nil {
+		return cnameTarget, err
+	}
+
+	queryMsg := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: queryID, RecursionDesired: true},
+		Questions: []dnsmessage.Question{
+			{Name: queryName, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET},
+		},
+	}
+	packedQuery, err := queryMsg.Pack()
+	if err != nil {
+		return cnameTarget, err
+	}
+
+	var respMsg dnsmessage.Message
+	for _, network := range []string{"udp", "tcp"} {
+		packedResp, err := exchangeDNSMsg(network, nameserver, packedQuery)
+		if err != nil {
+			return cnameTarget, err
+		}
+
+		err = respMsg.Unpack(packedResp)
+		if err != nil {
+			return cnameTarget, err
+		}
+
+		err = validateDNSResponse(queryMsg, respMsg)
+		if err != nil {
+			return cnameTarget, err
+		}
+
+		// the response didn't fit in a UDP packet, so we'll need to retry over TCP to get all of it
+		if !respMsg.Truncated {
+			break
+		}
+	}
+
+	if respMsg.RCode != dnsmessage.RCodeSuccess && respMsg.RCode != dnsmessage.RCodeNameError {
+		return cnameTarget, errors.New("DNS query for " + fqdn + " failed: " + respMsg.RCode.String())
+	}
+
+	for _, answer := range respMsg.Answers {
+		cnameRecord, isCNAME := answer.Body.(*dnsmessage.CNAMEResource)
+		if isCNAME && NormalizeFQDN(answer.Header.Name.String()) == NormalizeFQDN(fqdn) {
+			cnameTarget = NormalizeFQDN(cnameRecord.CNAME.String())
+			break
+		}
+	}
+
+	return cnameTarget, nil
+}
+
+func queryCNAMERecordFromNameservers(fqdn string, nameservers []string) (string, error) {
+	// nameservers are tried in order until one of them answers, same as the system resolver
+	var queryErrs []error
+
+	for _, nameserver := range nameservers {
+		cnameTarget, err := queryCNAMERecord(fqdn, nameserver)
+		if err == nil {
+			return cnameTarget, nil
+		}
+
+		queryErrs = append(queryErrs, fmt.Errorf("%s: %w", nameserver, err))
+	}
+
+	return "", errors.Join(queryErrs...)
+}
+
+func LookupCNAMEChain(fqdn string) ([]string, error) {
+	// returns the FQDN followed by each name its CNAME chain points to, in order
+	cnameChain := []string{NormalizeFQDN(fqdn)}
+
+	nameservers := getNameservers("/etc/resolv.conf")
+	if len(nameservers) == 0 {
+		// no nameserver to query directly (e.g. on Windows), so we can only get the final name in the chain
+		canonicalName, err := net.LookupCNAME(fqdn)
+		if err != nil {
+			return cnameChain, err
+		}
+
+		if NormalizeFQDN(canonicalName) != cnameChain[0] {
+			cnameChain = append(cnameChain, NormalizeFQDN(canonicalName))
+		}
+
+		return cnameChain, nil
+	}
+
+	for len(cnameChain) < maxCNAMEChainLength {
+		cnameTarget, err := queryCNAMERecordFromNameservers(cnameChain[len(cnameChain)-1], nameservers)
+		if err != nil {
+			return cnameChain, err
+		}
+
+		// stop at the end of the chain, or if it loops back on itself
+		if cnameTarget == "" || slices.Contains(cnameChain, cnameTarget) {
+			break
+		}
+
+		cnameChain = append(cnameChain, cnameTarget)
+	}
+
+	return cnameChain, nil
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func dnsRespFactory(t *testing.T, queryMsg dnsmessage.Message, truncated bool, cnameTarget string) []byte {
+	respMsg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: queryMsg.ID, Response: true, Truncated: truncated},
+		Questions: queryMsg.Questions,
+	}
+
+	if cnameTarget != "" {
+		respMsg.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: queryMsg.Questions[0].Name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET},
+			Body:   &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(cnameTarget + ".")},
+		}}
+	}
+
+	packedResp, err := respMsg.Pack()
+	if err != nil {
+		t.Errorf("Packing test DNS response failed; received error: %s", err)
+	}
+
+	return packedResp
+}
+
+func nameserverFactory(t *testing.T, udpRespFunc func(queryMsg dnsmessage.Message) []byte, tcpCNAMETarget string) string {
+	// serves CNAME queries over UDP using the response func, and over TCP with the given target on the same port
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Starting test nameserver failed; received error: %s", err)
+	}
+	t.Cleanup(func() { udpConn.Close() })
+
+	tcpListener, err := net.Listen("tcp", udpConn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Starting test nameserver failed; received error: %s", err)
+	}
+	t.Cleanup(func() { tcpListener.Close() })
+
+	go func() {
+		queryBuffer := make([]byte, 512)
+		for {
+			queryLen, clientAddr, err := udpConn.ReadFrom(queryBuffer)
+			if err != nil {
+				return
+			}
+
+			var queryMsg dnsmessage.Message
+			if queryMsg.Unpack(queryBuffer[:queryLen]) == nil {
+				udpConn.WriteTo(udpRespFunc(queryMsg), clientAddr)
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			conn, err := tcpListener.Accept()
+			if err != nil {
+				return
+			}
+
+			var queryLenBytes [2]byte
+			io.ReadFull(conn, queryLenBytes[:])
+			queryBuffer := make([]byte, binary.BigEndian.Uint16(queryLenBytes[:]))
+			io.ReadFull(conn, queryBuffer)
+
+			var queryMsg dnsmessage.Message
+			if queryMsg.Unpack(queryBuffer) == nil {
+				packedResp := dnsRespFactory(t, queryMsg, false, tcpCNAMETarget)
+				conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(packedResp))), packedResp...))
+			}
+			conn.Close()
+		}
+	}()
+
+	return udpConn.LocalAddr().String()
+}
+
+func TestQueryCNAMERecord(t *testing.T) {
+	var tests = []struct {
+		name                string
+		udpRespFunc         func(queryMsg dnsmessage.Message) []byte
+		expectedCNAMETarget string
+		expectErr           bool
+	}{
+		{"udp", func(queryMsg dnsmessage.Message) []byte {
+			return dnsRespFactory(t, queryMsg, false, "udp.example.com")
+		}, "udp.example.com", false},
+		{"truncated", func(queryMsg dnsmessage.Message) []byte {
+			return dnsRespFactory(t, queryMsg, true, "")
+		}, "tcp.example.com", false},
+		{"mismatched_id", func(queryMsg dnsmessage.Message) []byte {
+			queryMsg.ID++
+			return dnsRespFactory(t, queryMsg, false, "spoofed.example.com")
+		}, "", true},
+		{"mismatched_question", func(queryMsg dnsmessage.Message) []byte {
+			queryMsg.Questions[0].Name = dnsmessage.MustNewName("other.example.com.")
+			return dnsRespFactory(t, queryMsg, false, "spoofed.example.com")
+		}, "", true},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			nameserver := nameserverFactory(t, td.udpRespFunc, "tcp.example.com")
+
+			cnameTarget, err := queryCNAMERecord("www.example.com", nameserver)
+			if (err != nil) != td.expectErr {
+				t.Errorf("Querying CNAME record failed; expected error: %t, received: %v", td.expectErr, err)
+			}
+
+			if cnameTarget != td.expectedCNAMETarget {
+				t.Errorf("Querying CNAME record failed; expected %s, received %s", td.expectedCNAMETarget, cnameTarget)
+			}
+		})
+	}
+}
+
+func TestQueryCNAMERecordFromNameservers(t *testing.T) {
+	// nothing is listening on the first nameserver, so the query should fall back to the second one
+	unusedConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Reserving unused nameserver address failed; received error: %s", err)
+	}
+	deadNameserver := unusedConn.LocalAddr().String()
+	unusedConn.Close()
+
+	nameserver := nameserverFactory(t, func(queryMsg dnsmessage.Message) []byte {
+		return dnsRespFactory(t, queryMsg, false, "target.example.com")
+	}, "")
+
+	cnameTarget, err := queryCNAMERecordFromNameservers("www.example.com", []string{deadNameserver, nameserver})
+	if err != nil || cnameTarget != "target.example.com" {
+		t.Errorf("Querying CNAME record from multiple nameservers failed; expected target.example.com, received %s (error: %v)", cnameTarget, err)
+	}
+}
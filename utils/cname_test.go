@@ -0,0 +1,29 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+func TestNormalizeFQDN(t *testing.T) {
+	var tests = []struct {
+		fqdn, expected string
+	}{
+		{"example.com", "example.com"},
+		{"Example.COM.", "example.com"},
+		{" d111111abcdef8.cloudfront.net. ", "d111111abcdef8.cloudfront.net"},
+	}
+
+	for _, td := range tests {
+		testName := td.fqdn
+
+		t.Run(testName, func(t *testing.T) {
+			normalizedFQDN := utils.NormalizeFQDN(td.fqdn)
+
+			if normalizedFQDN != td.expected {
+				t.Errorf("Normalizing FQDN failed; expected %s, received %s", td.expected, normalizedFQDN)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// a block of IPs, given either as a CIDR (e.g. 10.0.0.0/8) or as an inclusive start-end range (e.g. 10.0.0.1-10.0.0.50)
+type IPRange struct {
+	Start, End netip.Addr
+}
+
+func IsIPRange(ipRangeStr string) bool {
+	// IPv6 addresses never include a dash, so any dash or slash means we're working with a range
+	return strings.ContainsAny(ipRangeStr, "/-–")
+}
+
+func ParseIPRange(ipRangeStr string) (IPRange, error) {
+	var ipRange IPRange
+
+	ipRangeStr = strings.TrimSpace(ipRangeStr)
+
+	if strings.Contains(ipRangeStr, "/") {
+		prefix, err := netip.ParsePrefix(ipRangeStr)
+		if err != nil {
+			return ipRange, err
+		}
+		prefix = prefix.Masked()
+
+		ipRange.Start = prefix.Addr()
+		ipRange.End = GetLastAddrInPrefix(prefix)
+
+		return ipRange, nil
+	}
+
+	// accept en dashes as well since that's what ranges copied out of documents tend to use
+	rangeBounds := strings.Split(strings.ReplaceAll(ipRangeStr, "–", "-"), "-")
+	if len(rangeBounds) != 2 {
+		return ipRange, fmt.Errorf("invalid IP range provided: '%s'", ipRangeStr)
+	}
+
+	start, err := netip.ParseAddr(strings.TrimSpace(rangeBounds[0]))
+	if err != nil {
+		return ipRange, err
+	}
+
+	end, err := netip.ParseAddr(strings.TrimSpace(rangeBounds[1]))
+	if err != nil {
+		return ipRange, err
+	}
+
+	start, end = start.Unmap(), end.Unmap()
+	if start.BitLen() != end.BitLen() {
+		return ipRange, fmt.Errorf("IP range start and end must be the same IP version: '%s'", ipRangeStr)
+	} else if end.Less(start) {
+		return ipRange, fmt.Errorf("IP range end must not come before its start: '%s'", ipRangeStr)
+	}
+
+	ipRange.Start, ipRange.End = start, end
+
+	return ipRange, nil
+}
+
+func GetLastAddrInPrefix(prefix netip.Prefix) netip.Addr {
+	addrBytes := prefix.Addr().AsSlice()
+
+	// set every host bit
+	for bit := prefix.Bits(); bit < len(addrBytes)*8; bit++ {
+		addrBytes[bit/8] |= 1 << (7 - bit%8)
+	}
+
+	lastAddr, _ := netip.AddrFromSlice(addrBytes)
+
+	return lastAddr
+}
+
+func (ipRange IPRange) Contains(ipAddr string) bool {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ipAddr))
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	if addr.BitLen() != ipRange.Start.BitLen() {
+		return false
+	}
+
+	return !addr.Less(ipRange.Start) && !ipRange.End.Less(addr)
+}
+
+func (ipRange IPRange) ContainsAny(ipAddrs []string) bool {
+	for _, ipAddr := range ipAddrs {
+		if ipRange.Contains(ipAddr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ipRange IPRange) String() string {
+	return fmt.Sprintf("%s-%s", ipRange.Start, ipRange.End)
+}
+
+func SplitIPAddrsByVersion(ipAddrs []net.IP) ([]string, []string) {
+	// returns IPv4 addresses, then IPv6 addresses
+	var ipv4Addrs, ipv6Addrs []string
+
+	for _, ipAddr := range ipAddrs {
+		if ipAddr.To4() != nil {
+			ipv4Addrs = append(ipv4Addrs, ipAddr.String())
+		} else {
+			ipv6Addrs = append(ipv6Addrs, ipAddr.String())
+		}
+	}
+
+	return ipv4Addrs, ipv6Addrs
+}
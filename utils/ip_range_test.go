@@ -0,0 +1,127 @@
+package utils_test
+
+import (
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+func TestIsIPRange(t *testing.T) {
+	var tests = []struct {
+		ipRangeStr string
+		expected   bool
+	}{
+		{"1.1.1.1", false},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", false},
+		{"18.161.22.0/24", true},
+		{"2600:9000:24eb::/48", true},
+		{"18.161.22.1-18.161.22.61", true},
+		{"18.161.22.1–18.161.22.61", true},
+	}
+
+	for _, td := range tests {
+		testName := td.ipRangeStr
+
+		t.Run(testName, func(t *testing.T) {
+			isIPRange := utils.IsIPRange(td.ipRangeStr)
+
+			if isIPRange != td.expected {
+				t.Errorf("Checking if IP range failed; expected %t, received %t", td.expected, isIPRange)
+			}
+		})
+	}
+}
+
+func TestParseIPRange(t *testing.T) {
+	var tests = []struct {
+		ipRangeStr, expectedStart, expectedEnd string
+	}{
+		{"18.161.22.0/24", "18.161.22.0", "18.161.22.255"},
+		{"18.161.22.61/24", "18.161.22.0", "18.161.22.255"},
+		{"1.1.1.1/32", "1.1.1.1", "1.1.1.1"},
+		{"2600:9000:24eb::/48", "2600:9000:24eb::", "2600:9000:24eb:ffff:ffff:ffff:ffff:ffff"},
+		{"18.161.22.1-18.161.22.61", "18.161.22.1", "18.161.22.61"},
+		{"18.161.22.1 – 18.161.22.61", "18.161.22.1", "18.161.22.61"},
+	}
+
+	for _, td := range tests {
+		testName := td.ipRangeStr
+
+		t.Run(testName, func(t *testing.T) {
+			ipRange, err := utils.ParseIPRange(td.ipRangeStr)
+			if err != nil {
+				t.Fatalf("Parsing IP range failed; received error: %s", err)
+			}
+
+			if ipRange.Start.String() != td.expectedStart || ipRange.End.String() != td.expectedEnd {
+				t.Errorf("Parsing IP range failed; expected %s-%s, received %s", td.expectedStart, td.expectedEnd, ipRange)
+			}
+		})
+	}
+}
+
+func TestParseIPRange_Invalid(t *testing.T) {
+	var tests = []struct {
+		ipRangeStr string
+	}{
+		{"1234.45.9666.1/24"},
+		{"18.161.22.0/99"},
+		{"18.161.22.61-18.161.22.1"},
+		{"18.161.22.1-2600:9000:24eb::1"},
+		{"18.161.22.1-18.161.22.2-18.161.22.3"},
+		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21-2600:9000:24eb:dc00:1:3b80:4f00:21"},
+	}
+
+	for _, td := range tests {
+		testName := td.ipRangeStr
+
+		t.Run(testName, func(t *testing.T) {
+			_, err := utils.ParseIPRange(td.ipRangeStr)
+			if err == nil {
+				t.Errorf("Parsing IP range should have failed for %s, but did not", td.ipRangeStr)
+			}
+		})
+	}
+}
+
+func TestIPRangeContains(t *testing.T) {
+	var tests = []struct {
+		ipRangeStr, ipAddr string
+		expected           bool
+	}{
+		{"18.161.22.0/24", "18.161.22.61", true},
+		{"18.161.22.0/24", "18.161.23.61", false},
+		{"18.161.22.1-18.161.22.61", "18.161.22.61", true},
+		{"18.161.22.1-18.161.22.61", "18.161.22.62", false},
+		{"18.161.22.0/24", "::ffff:18.161.22.61", true},
+		{"18.161.22.0/24", "2600:9000:24eb:dc00:1:3b80:4f00:21", false},
+		{"2600:9000:24eb::/48", "2600:9000:24eb:dc00:1:3b80:4f00:21", true},
+		{"2600:9000:24eb::/48", "1234.45.9666.1", false},
+	}
+
+	for _, td := range tests {
+		testName := td.ipRangeStr + "_" + td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			ipRange, _ := utils.ParseIPRange(td.ipRangeStr)
+
+			if ipRange.Contains(td.ipAddr) != td.expected {
+				t.Errorf("Checking IP range for IP failed; expected %t for %s in %s", td.expected, td.ipAddr, td.ipRangeStr)
+			}
+		})
+	}
+}
+
+func TestSplitIPAddrsByVersion(t *testing.T) {
+	ipv4Addrs, ipv6Addrs := utils.SplitIPAddrsByVersion([]net.IP{
+		net.ParseIP("18.161.22.61"),
+		net.ParseIP("2600:9000:24eb:dc00:1:3b80:4f00:21"),
+		net.ParseIP("1.1.1.1"),
+	})
+
+	if !slices.Equal(ipv4Addrs, []string{"18.161.22.61", "1.1.1.1"}) || !slices.Equal(ipv6Addrs, []string{"2600:9000:24eb:dc00:1:3b80:4f00:21"}) {
+		t.Errorf("Splitting IPs by version failed; received IPv4 IPs %s and IPv6 IPs %s", ipv4Addrs, ipv6Addrs)
+	}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"strings"
+)
+
+func ParseTenantIDs(tenantIDParam string) map[string]string {
+	// supports a single tenant ID, or per-platform tenant IDs in CSV format, e.g. gcp=my-project,azure=<subscription ID>
+	tenantIDs := map[string]string{}
+
+	for _, tenantIDEntry := range strings.Split(tenantIDParam, ",") {
+		platform, tenantID, isPerPlatform := strings.Cut(strings.TrimSpace(tenantIDEntry), "=")
+		if !isPerPlatform {
+			tenantIDs[""] = platform
+			continue
+		}
+
+		tenantIDs[strings.ToLower(platform)] = tenantID
+	}
+
+	return tenantIDs
+}
+
+func GetPlatformTenantID(tenantIDs map[string]string, platform string) string {
+	// platforms without their own tenant ID fall back to the one that wasn't tied to a platform, if any
+	tenantID, found := tenantIDs[strings.ToLower(platform)]
+	if !found {
+		tenantID = tenantIDs[""]
+	}
+
+	return tenantID
+}
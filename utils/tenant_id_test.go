@@ -0,0 +1,33 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+func TestGetPlatformTenantID(t *testing.T) {
+	var tests = []struct {
+		tenantIDParam, platform, expectedTenantID string
+	}{
+		{"my-project", "gcp", "my-project"},
+		{"", "gcp", ""},
+		{"gcp=my-project,azure=my-subscription", "gcp", "my-project"},
+		{"gcp=my-project, azure=my-subscription", "AZURE", "my-subscription"},
+		{"gcp=my-project,azure=my-subscription", "aws", ""},
+		{"GCP=my-project,default-tenant", "azure", "default-tenant"},
+		{"GCP=my-project,default-tenant", "gcp", "my-project"},
+	}
+
+	for _, td := range tests {
+		testName := td.tenantIDParam + "_" + td.platform
+
+		t.Run(testName, func(t *testing.T) {
+			tenantID := utils.GetPlatformTenantID(utils.ParseTenantIDs(td.tenantIDParam), td.platform)
+
+			if tenantID != td.expectedTenantID {
+				t.Errorf("Getting platform tenant ID failed; expected %s, received %s", td.expectedTenantID, tenantID)
+			}
+		})
+	}
+}
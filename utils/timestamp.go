@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func GetSupportedTimestampLayouts() []string {
+	return []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+}
+
+func ParseTimestamp(timestampStr string) (time.Time, error) {
+	// accepts RFC 3339 timestamps, the shorter forms alert tickets tend to use (assumed to be UTC), or unix epoch seconds
+	timestampStr = strings.TrimSpace(timestampStr)
+
+	epochSecs, err := strconv.ParseInt(timestampStr, 10, 64)
+	if err == nil {
+		return time.Unix(epochSecs, 0).UTC(), nil
+	}
+
+	for _, layout := range GetSupportedTimestampLayouts() {
+		timestamp, err := time.Parse(layout, timestampStr)
+		if err == nil {
+			return timestamp.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid timestamp provided: '%s'", timestampStr)
+}
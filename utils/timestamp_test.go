@@ -0,0 +1,59 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	var tests = []struct {
+		timestampStr string
+		expected     time.Time
+	}{
+		{"2024-04-01T12:30:00Z", time.Date(2024, 4, 1, 12, 30, 0, 0, time.UTC)},
+		{"2024-04-01T08:30:00-04:00", time.Date(2024, 4, 1, 12, 30, 0, 0, time.UTC)},
+		{"2024-04-01 12:30:00", time.Date(2024, 4, 1, 12, 30, 0, 0, time.UTC)},
+		{"2024-04-01 12:30", time.Date(2024, 4, 1, 12, 30, 0, 0, time.UTC)},
+		{"2024-04-01", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"1711974600", time.Date(2024, 4, 1, 12, 30, 0, 0, time.UTC)},
+	}
+
+	for _, td := range tests {
+		testName := td.timestampStr
+
+		t.Run(testName, func(t *testing.T) {
+			timestamp, err := utils.ParseTimestamp(td.timestampStr)
+			if err != nil {
+				t.Fatalf("Parsing timestamp failed; received error: %s", err)
+			}
+
+			if !timestamp.Equal(td.expected) {
+				t.Errorf("Parsing timestamp failed; expected %s, received %s", td.expected, timestamp)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp_Invalid(t *testing.T) {
+	var tests = []struct {
+		timestampStr string
+	}{
+		{""},
+		{"yesterday"},
+		{"2024-13-01"},
+		{"04/01/2024"},
+	}
+
+	for _, td := range tests {
+		testName := td.timestampStr
+
+		t.Run(testName, func(t *testing.T) {
+			_, err := utils.ParseTimestamp(td.timestampStr)
+			if err == nil {
+				t.Errorf("Parsing timestamp should have failed for '%s', but did not", td.timestampStr)
+			}
+		})
+	}
+}